@@ -39,7 +39,21 @@ func ConnectDatabase() {
 	utils.LogInfo("Database connected successfully")
 
 	// Migration - انجام migration‌های تمامی models
-	err = DB.AutoMigrate(
+	err = DB.AutoMigrate(Models()...)
+
+	if err != nil {
+		utils.LogError("Failed to migrate database", err)
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	utils.LogInfo("Database migration completed successfully")
+
+	backfillGroupOwners()
+}
+
+// Models - تمام models برنامه به ترتیب migration؛ تست‌ها هم پایگاه داده خود را با همین لیست می‌سازند
+func Models() []interface{} {
+	return []interface{}{
 		&models.User{},
 		&models.Group{},
 		&models.GroupMember{},
@@ -49,14 +63,50 @@ func ConnectDatabase() {
 		&models.TaskAssignment{},
 		&models.File{},
 		&models.Notification{},
-	)
-
-	if err != nil {
-		utils.LogError("Failed to migrate database", err)
-		log.Fatal("Failed to migrate database:", err)
+		&models.TaskRecurrence{},
+		&models.TaskDependency{},
+		&models.Label{},
+		&models.TaskStatusTransition{},
+		&models.ReminderRule{},
+		&models.Reminder{},
+		&models.TaskComment{},
+		&models.CommentRevision{},
+		&models.ActivityLog{},
+		&models.TimeEntry{},
+		&models.ChecklistItem{},
+		&models.BoardColumn{},
+		&models.TaskTemplate{},
+		&models.TaskTemplateItem{},
+		&models.GroupRole{},
+		&models.OwnershipTransfer{},
+		&models.GroupInvite{},
+		&models.GroupInviteUse{},
+		&models.GroupInvitation{},
+		&models.GroupJoinRequest{},
+		&models.GroupTeam{},
+		&models.GroupTeamMember{},
+		&models.TaskSubmission{},
 	}
+}
 
-	utils.LogInfo("Database migration completed successfully")
+// backfillGroupOwners - در گروه‌های ساخته‌شده پیش از نقش owner، قدیمی‌ترین مدیر پذیرفته‌شده (سازنده) مالک می‌شود
+func backfillGroupOwners() {
+	owned := DB.Model(&models.GroupMember{}).Select("group_id").Where("role = ?", models.RoleOwner)
+	var groupIDs []uint
+	if err := DB.Model(&models.Group{}).Where("id NOT IN (?)", owned).Pluck("id", &groupIDs).Error; err != nil {
+		utils.LogError("Failed to backfill group owners", err)
+		return
+	}
+	for _, groupID := range groupIDs {
+		var first models.GroupMember
+		if err := DB.Where("group_id = ? AND role = ? AND accepted = ?", groupID, models.RoleAdmin, true).
+			Order("id ASC").First(&first).Error; err != nil {
+			continue
+		}
+		if err := DB.Model(&first).Update("role", models.RoleOwner).Error; err != nil {
+			utils.LogError("Failed to backfill group owners", err)
+		}
+	}
 }
 
 // GetDB برای دسترسی به database instance
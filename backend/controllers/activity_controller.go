@@ -0,0 +1,80 @@
+// backend/controllers/activity_controller.go
+
+package controllers
+
+import (
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var activityListSpec = utils.ListSpec{
+	Table: "activity_logs",
+	Sortable: map[string]string{
+		"created_at": "activity_logs.created_at",
+	},
+	DefaultSort: "-created_at",
+	Filters: map[string]string{
+		"action":   "activity_logs.action",
+		"actor_id": "activity_logs.actor_id",
+		"task_id":  "activity_logs.task_id",
+	},
+	DateColumn: "activity_logs.created_at",
+}
+
+func preloadActivityActor(db *gorm.DB) *gorm.DB {
+	return db.Preload("Actor")
+}
+
+// GetTaskActivity - تاریخچه فعالیت‌های یک تسک؛ due_from/due_to روی زمان ثبت فعالیت اعمال می‌شوند
+func GetTaskActivity(c *gin.Context) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	list, err := utils.ParseListQuery(c, activityListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var activities []models.ActivityLog
+	query := config.DB.Model(&models.ActivityLog{}).Where("activity_logs.task_id = ?", task.ID)
+	if err := list.Find(query, &activities, preloadActivityActor); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت فعالیت‌ها")
+		return
+	}
+
+	utils.ListResponse(c, activities, list)
+}
+
+// GetGroupActivity - فعالیت‌های گروه و تمام تسک‌های آن برای اعضای گروه
+func GetGroupActivity(c *gin.Context) {
+	groupID := c.Param("id")
+
+	// بررسی اینکه کاربر عضو گروه است
+	member, ok := authorizeGroup(c, StringToUint(groupID), "", "")
+	if !ok {
+		return
+	}
+
+	list, err := utils.ParseListQuery(c, activityListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var activities []models.ActivityLog
+	query := config.DB.Model(&models.ActivityLog{}).Where("activity_logs.group_id = ?", member.GroupID)
+	if err := list.Find(query, &activities, preloadActivityActor); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت فعالیت‌ها")
+		return
+	}
+
+	utils.ListResponse(c, activities, list)
+}
@@ -7,9 +7,11 @@ import (
 	"time"
 	"task-manager/config"
 	"task-manager/models"
+	"task-manager/services"
 	"task-manager/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type StreakData struct {
@@ -99,8 +101,164 @@ func GetAnalyticsSummary(c *gin.Context) {
 	// تعداد تسک‌های گروهی برای کاربر
 	config.DB.Model(&models.TaskAssignment{}).
 		Joins("JOIN tasks ON tasks.id = task_assignments.task_id").
-		Where("task_assignments.user_id = ? AND tasks.is_group_task = ?", userID, true).
+		Where("task_assignments.user_id = ? AND tasks.is_group_task = ? AND tasks.deleted_at IS NULL", userID, true).
 		Count(&summary.GroupTaskCount)
 
 	utils.SuccessResponse(c, http.StatusOK, "OK", summary)
 }
+
+// TaskTimeRow زمان ثبت‌شده روی یک تسک در برابر تخمین آن
+type TaskTimeRow struct {
+	TaskID          uint   `json:"task_id"`
+	Title           string `json:"title"`
+	EstimateMinutes *int   `json:"estimate_minutes"`
+	TrackedSeconds  int64  `json:"tracked_seconds"`
+	VarianceSeconds *int64 `json:"variance_seconds,omitempty"` // مثبت یعنی بیشتر از تخمین
+}
+
+// UserTimeRow زمان ثبت‌شده یک کاربر
+type UserTimeRow struct {
+	UserID         uint  `json:"user_id"`
+	TrackedSeconds int64 `json:"tracked_seconds"`
+}
+
+// TimeReport گزارش زمان؛ EstimatedSeconds و TrackedOnEstimated فقط تسک‌های دارای تخمین را در نظر می‌گیرند
+type TimeReport struct {
+	TotalSeconds       int64         `json:"total_seconds"`
+	EstimatedSeconds   int64         `json:"estimated_seconds"`
+	TrackedOnEstimated int64         `json:"tracked_on_estimated_seconds"`
+	ByTask             []TaskTimeRow `json:"by_task"`
+	ByUser             []UserTimeRow `json:"by_user,omitempty"`
+}
+
+// timeRange - فیلتر from/to (YYYY-MM-DD یا RFC3339) روی زمان شروع رکوردها
+func timeRange(c *gin.Context, db *gorm.DB) (*gorm.DB, bool) {
+	parse := func(v string) (time.Time, error) {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		return time.ParseInLocation("2006-01-02", v, time.Local)
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := parse(v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "from نامعتبر است")
+			return nil, false
+		}
+		db = db.Where("time_entries.started_at >= ?", from)
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := parse(v)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "to نامعتبر است")
+			return nil, false
+		}
+		if len(v) == len("2006-01-02") {
+			to = to.AddDate(0, 0, 1)
+		}
+		db = db.Where("time_entries.started_at < ?", to)
+	}
+	return db, true
+}
+
+// buildTimeReport - جمع زمان‌ها به تفکیک تسک (و در صورت نیاز کاربر) برای رکوردهای entries
+func buildTimeReport(entries *gorm.DB, byUser bool) (*TimeReport, error) {
+	report := &TimeReport{ByTask: []TaskTimeRow{}}
+
+	if err := entries.Session(&gorm.Session{}).
+		Select("time_entries.task_id, tasks.title, tasks.estimate_minutes, SUM(" + services.TrackedSecondsExpr(entries) + ") AS tracked_seconds").
+		Joins("JOIN tasks ON tasks.id = time_entries.task_id AND tasks.deleted_at IS NULL").
+		Group("time_entries.task_id, tasks.title, tasks.estimate_minutes").
+		Order("tracked_seconds DESC").
+		Scan(&report.ByTask).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range report.ByTask {
+		row := &report.ByTask[i]
+		report.TotalSeconds += row.TrackedSeconds
+		if row.EstimateMinutes != nil {
+			estimate := int64(*row.EstimateMinutes) * 60
+			variance := row.TrackedSeconds - estimate
+			row.VarianceSeconds = &variance
+			report.EstimatedSeconds += estimate
+			report.TrackedOnEstimated += row.TrackedSeconds
+		}
+	}
+
+	if byUser {
+		report.ByUser = []UserTimeRow{}
+		if err := entries.Session(&gorm.Session{}).
+			Select("time_entries.user_id, SUM(" + services.TrackedSecondsExpr(entries) + ") AS tracked_seconds").
+			Joins("JOIN tasks ON tasks.id = time_entries.task_id AND tasks.deleted_at IS NULL").
+			Group("time_entries.user_id").
+			Order("tracked_seconds DESC").
+			Scan(&report.ByUser).Error; err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// GetMyTimeReport - زمان‌های ثبت‌شده کاربر به تفکیک تسک همراه با مقایسه با تخمین
+func GetMyTimeReport(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	entries, ok := timeRange(c, config.DB.Table("time_entries").Where("time_entries.user_id = ?", userID))
+	if !ok {
+		return
+	}
+
+	report, err := buildTimeReport(entries, false)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در تهیه گزارش زمان")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", report)
+}
+
+// GetTaskTimeReport - جمع زمان تسک به تفکیک کاربر و مقایسه با تخمین
+func GetTaskTimeReport(c *gin.Context) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	entries, ok := timeRange(c, config.DB.Table("time_entries").Where("time_entries.task_id = ?", task.ID))
+	if !ok {
+		return
+	}
+
+	report, err := buildTimeReport(entries, true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در تهیه گزارش زمان")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", report)
+}
+
+// GetGroupTimeReport - جمع زمان تسک‌های گروه به تفکیک تسک و عضو؛ فقط برای اعضای دارای اجازه view_reports
+func GetGroupTimeReport(c *gin.Context) {
+	groupID := c.Param("id")
+
+	member, ok := authorizeGroup(c, StringToUint(groupID), models.PermViewReports, "شما اجازه مشاهده گزارش زمان این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	groupTasks := config.DB.Model(&models.Task{}).Select("id").Where("group_id = ?", member.GroupID)
+	entries, ok := timeRange(c, config.DB.Table("time_entries").Where("time_entries.task_id IN (?)", groupTasks))
+	if !ok {
+		return
+	}
+
+	report, err := buildTimeReport(entries, true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در تهیه گزارش زمان")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", report)
+}
@@ -0,0 +1,337 @@
+// backend/controllers/board_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BoardColumnRequest struct {
+	Name     string            `json:"name" binding:"required"`
+	Status   models.TaskStatus `json:"status" binding:"required,oneof=pending in_progress completed expired"`
+	WIPLimit int               `json:"wip_limit" binding:"min=0"`
+}
+
+type ReorderBoardColumnsRequest struct {
+	ColumnIDs []uint `json:"column_ids" binding:"required"` // ترتیب جدید تمام ستون‌ها
+}
+
+type MoveBoardTaskRequest struct {
+	TaskID   uint  `json:"task_id" binding:"required"`
+	ColumnID uint  `json:"column_id" binding:"required"`
+	BeforeID *uint `json:"before_id"` // تسکی که بالای تسک قرار می‌گیرد
+	AfterID  *uint `json:"after_id"`  // تسکی که زیر تسک قرار می‌گیرد
+}
+
+// Board نمای بورد گروه؛ Unplaced تسک‌هایی هستند که وضعیتشان ستونی ندارد
+type Board struct {
+	Columns  []models.BoardColumn `json:"columns"`
+	Unplaced []models.Task        `json:"unplaced"`
+}
+
+// boardMember - عضویت کاربر در گروه؛ با adminOnly اجازه تنظیم بورد (edit_settings) لازم است
+func boardMember(c *gin.Context, adminOnly bool) (*models.GroupMember, bool) {
+	if adminOnly {
+		return authorizeGroup(c, StringToUint(c.Param("id")), models.PermEditSettings, "شما اجازه تنظیم بورد را ندارید")
+	}
+	return authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+}
+
+// loadBoard - ستون‌ها به همراه تسک‌های مرتب‌شده هر ستون
+func loadBoard(groupID uint) (*Board, error) {
+	board := &Board{Columns: []models.BoardColumn{}, Unplaced: []models.Task{}}
+	if err := config.DB.Where("group_id = ?", groupID).
+		Order("position ASC, id ASC").
+		Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+			return db.Order("board_rank ASC, id ASC").Preload("TaskAssignments")
+		}).
+		Find(&board.Columns).Error; err != nil {
+		return nil, err
+	}
+	err := config.DB.Where("group_id = ? AND is_group_task = ? AND board_column_id IS NULL", groupID, true).
+		Order("created_at ASC").
+		Find(&board.Unplaced).Error
+	return board, err
+}
+
+// GetBoard - دریافت بورد کانبان گروه؛ در اولین درخواست ستون‌های پیش‌فرض ساخته می‌شوند
+func GetBoard(c *gin.Context) {
+	member, ok := boardMember(c, false)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := services.EnsureBoard(tx, member.GroupID)
+		return err
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در آماده‌سازی بورد")
+		return
+	}
+
+	board, err := loadBoard(member.GroupID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت بورد")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", board)
+}
+
+// CreateBoardColumn - افزودن ستون به انتهای بورد
+func CreateBoardColumn(c *gin.Context) {
+	var req BoardColumnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "نام ستون نمی‌تواند خالی باشد")
+		return
+	}
+
+	member, ok := boardMember(c, true)
+	if !ok {
+		return
+	}
+
+	column := models.BoardColumn{GroupID: member.GroupID, Name: name, Status: req.Status, WIPLimit: req.WIPLimit}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		columns, err := services.EnsureBoard(tx, member.GroupID)
+		if err != nil {
+			return err
+		}
+		column.Position = len(columns)
+		if len(columns) > 0 {
+			column.Position = columns[len(columns)-1].Position + 1
+		}
+		return tx.Create(&column).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ایجاد ستون")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "ستون با موفقیت ایجاد شد", column)
+}
+
+// UpdateBoardColumn - تغییر نام، وضعیت یا محدودیت WIP ستون
+// وضعیت ستونی که تسک دارد قابل تغییر نیست چون وضعیت تسک‌ها از ستون می‌آید
+func UpdateBoardColumn(c *gin.Context) {
+	var req BoardColumnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "نام ستون نمی‌تواند خالی باشد")
+		return
+	}
+
+	member, ok := boardMember(c, true)
+	if !ok {
+		return
+	}
+
+	var column models.BoardColumn
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.LockBoard(tx, member.GroupID); err != nil {
+			return err
+		}
+		if err := tx.Where("id = ? AND group_id = ?", c.Param("column_id"), member.GroupID).First(&column).Error; err != nil {
+			return err
+		}
+		if column.Status != req.Status {
+			var count int64
+			if err := tx.Model(&models.Task{}).Where("board_column_id = ?", column.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return services.ErrBoardChanged
+			}
+		}
+		column.Name = name
+		column.Status = req.Status
+		column.WIPLimit = req.WIPLimit
+		return tx.Save(&column).Error
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "ستون پیدا نشد")
+		return
+	case errors.Is(err, services.ErrBoardChanged):
+		utils.ErrorResponse(c, http.StatusConflict, "وضعیت ستونی که تسک دارد قابل تغییر نیست")
+		return
+	case err != nil:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی ستون")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "ستون با موفقیت بروزرسانی شد", column)
+}
+
+// DeleteBoardColumn - حذف ستون خالی
+func DeleteBoardColumn(c *gin.Context) {
+	member, ok := boardMember(c, true)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.LockBoard(tx, member.GroupID); err != nil {
+			return err
+		}
+		var column models.BoardColumn
+		if err := tx.Where("id = ? AND group_id = ?", c.Param("column_id"), member.GroupID).First(&column).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.Task{}).Where("board_column_id = ?", column.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return services.ErrBoardChanged
+		}
+		return tx.Delete(&column).Error
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "ستون پیدا نشد")
+		return
+	case errors.Is(err, services.ErrBoardChanged):
+		utils.ErrorResponse(c, http.StatusConflict, "ابتدا تسک‌های این ستون را جابه‌جا کنید")
+		return
+	case err != nil:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف ستون")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "ستون با موفقیت حذف شد", nil)
+}
+
+// ReorderBoardColumns - تعیین ترتیب ستون‌ها؛ column_ids باید دقیقا شامل تمام ستون‌های بورد باشد
+func ReorderBoardColumns(c *gin.Context) {
+	var req ReorderBoardColumnsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, ok := boardMember(c, true)
+	if !ok {
+		return
+	}
+
+	errInvalidOrder := errors.New("invalid column order")
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		columns, err := services.EnsureBoard(tx, member.GroupID)
+		if err != nil {
+			return err
+		}
+		known := make(map[uint]bool, len(columns))
+		for _, column := range columns {
+			known[column.ID] = true
+		}
+		if len(req.ColumnIDs) != len(columns) {
+			return errInvalidOrder
+		}
+		for position, id := range req.ColumnIDs {
+			if !known[id] {
+				return errInvalidOrder
+			}
+			delete(known, id) // شناسه تکراری
+			if err := tx.Model(&models.BoardColumn{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errInvalidOrder) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ترتیب باید شامل تمام ستون‌های بورد باشد")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در مرتب‌سازی ستون‌ها")
+		return
+	}
+
+	board, err := loadBoard(member.GroupID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت بورد")
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "ترتیب ستون‌ها ذخیره شد", board)
+}
+
+// MoveBoardTask - جابه‌جایی تسک بین ستون‌ها یا داخل یک ستون
+// مدیران هر تسکی را و اعضا فقط تسک‌های اختصاص‌یافته به خودشان را جابه‌جا می‌کنند
+func MoveBoardTask(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req MoveBoardTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermContribute, "شما اجازه جابه‌جایی تسک‌ها را ندارید")
+	if !ok {
+		return
+	}
+	if !hasGroupPermission(userID, member.GroupID, models.PermManageTasks) {
+		var assignment models.TaskAssignment
+		if err := config.DB.Where("task_id = ? AND user_id = ?", req.TaskID, userID).First(&assignment).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusForbidden, "شما فقط تسک‌های خودتان را می‌توانید جابه‌جا کنید")
+			return
+		}
+	}
+
+	var task *models.Task
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		task, err = services.MoveTask(tx, member.GroupID, services.BoardMove{
+			TaskID:   req.TaskID,
+			ColumnID: req.ColumnID,
+			BeforeID: req.BeforeID,
+			AfterID:  req.AfterID,
+		}, userID)
+		return err
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "تسک یا ستون پیدا نشد")
+		return
+	case errors.Is(err, services.ErrBoardChanged):
+		utils.ErrorResponse(c, http.StatusConflict, "بورد تغییر کرده است؛ آن را دوباره بارگذاری کنید")
+		return
+	case errors.Is(err, services.ErrWIPLimitReached):
+		utils.ErrorResponse(c, http.StatusConflict, "ظرفیت این ستون تکمیل است")
+		return
+	case errors.Is(err, services.ErrTaskBlocked):
+		utils.ErrorResponse(c, http.StatusConflict, "این تسک منتظر تکمیل پیش‌نیازهایش است")
+		return
+	case errors.Is(err, services.ErrSubtasksIncomplete):
+		utils.ErrorResponse(c, http.StatusConflict, "ابتدا زیرتسک‌ها را کامل کنید")
+		return
+	case errors.Is(err, services.ErrGroupTaskUnfinished):
+		utils.ErrorResponse(c, http.StatusConflict, "کار همه اعضا هنوز تکمیل یا تایید نشده است")
+		return
+	case err != nil:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در جابه‌جایی تسک")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "تسک جابه‌جا شد", task)
+}
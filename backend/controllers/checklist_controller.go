@@ -0,0 +1,351 @@
+// backend/controllers/checklist_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CreateChecklistItemRequest struct {
+	Title        string `json:"title" binding:"required"`
+	UserID       uint   `json:"user_id"`       // صاحب چک‌لیست در تسک گروهی؛ پیش‌فرض کاربر فعلی
+	AllAssignees bool   `json:"all_assignees"` // افزودن آیتم به چک‌لیست تمام اعضای اختصاص‌یافته
+}
+
+type UpdateChecklistItemRequest struct {
+	Title string `json:"title" binding:"required"`
+}
+
+type ReorderChecklistRequest struct {
+	UserID  uint   `json:"user_id"`
+	ItemIDs []uint `json:"item_ids" binding:"required"` // ترتیب جدید تمام آیتم‌های چک‌لیست
+}
+
+// checklistError - تبدیل خطای هم‌گام‌سازی پیشرفت به پاسخ مناسب
+func checklistError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrTaskBlocked) {
+		utils.ErrorResponse(c, http.StatusConflict, "این تسک منتظر تکمیل پیش‌نیازهایش است")
+		return
+	}
+	if errors.Is(err, services.ErrSubtasksIncomplete) {
+		utils.ErrorResponse(c, http.StatusConflict, "ابتدا زیرتسک‌ها را کامل کنید")
+		return
+	}
+	utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی چک‌لیست")
+}
+
+// isChecklistOwner - آیا کاربر می‌تواند روی این تسک چک‌لیست داشته باشد
+func isChecklistOwner(task *models.Task, userID uint) bool {
+	if !task.IsGroupTask {
+		return task.CreatorID == userID
+	}
+	var assignment models.TaskAssignment
+	return config.DB.Where("task_id = ? AND user_id = ?", task.ID, userID).First(&assignment).Error == nil
+}
+
+// canEditChecklist - هر کاربر چک‌لیست خودش را و مدیر تسک چک‌لیست همه را ویرایش می‌کند
+func canEditChecklist(userID, ownerID uint, task *models.Task) bool {
+	return userID == ownerID || canManageTask(userID, task)
+}
+
+// loadChecklistItem - دریافت آیتم با بررسی دسترسی ویرایش؛ در صورت خطا پاسخ نوشته می‌شود
+func loadChecklistItem(c *gin.Context) (*models.Task, *models.ChecklistItem, bool) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return nil, nil, false
+	}
+
+	var item models.ChecklistItem
+	if err := config.DB.Where("id = ? AND task_id = ?", c.Param("item_id"), task.ID).First(&item).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "آیتم چک‌لیست پیدا نشد")
+		return nil, nil, false
+	}
+	if !canEditChecklist(c.GetUint("userID"), item.UserID, task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه ویرایش این چک‌لیست را ندارید")
+		return nil, nil, false
+	}
+	return task, &item, true
+}
+
+// nextChecklistPosition - جایگاه آیتم بعدی در انتهای چک‌لیست
+func nextChecklistPosition(tx *gorm.DB, taskID, userID uint) (int, error) {
+	var max *int
+	err := tx.Model(&models.ChecklistItem{}).
+		Where("task_id = ? AND user_id = ?", taskID, userID).
+		Select("MAX(position)").Scan(&max).Error
+	if err != nil || max == nil {
+		return 0, err
+	}
+	return *max + 1, nil
+}
+
+// GetChecklist - دریافت چک‌لیست مرتب‌شده؛ user_id برای دیدن چک‌لیست عضو دیگر در تسک گروهی
+func GetChecklist(c *gin.Context) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	ownerID := StringToUintWithDefault(c.Query("user_id"), c.GetUint("userID"))
+	if !task.IsGroupTask {
+		ownerID = task.CreatorID
+	}
+
+	var items []models.ChecklistItem
+	if err := config.DB.Where("task_id = ? AND user_id = ?", task.ID, ownerID).
+		Order("position ASC, id ASC").
+		Find(&items).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت چک‌لیست")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", items)
+}
+
+// CreateChecklistItem - افزودن آیتم به انتهای چک‌لیست
+func CreateChecklistItem(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "عنوان آیتم نمی‌تواند خالی باشد")
+		return
+	}
+
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var owners []uint
+	switch {
+	case req.AllAssignees:
+		if !task.IsGroupTask || !canManageTask(userID, task) {
+			utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌توانند برای همه اعضا آیتم اضافه کنند")
+			return
+		}
+		assignees, err := services.TaskAssigneeIDs(config.DB, task.ID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت اعضای تسک")
+			return
+		}
+		owners = assignees
+	default:
+		ownerID := req.UserID
+		if ownerID == 0 {
+			ownerID = userID
+		}
+		if !canEditChecklist(userID, ownerID, task) {
+			utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه ویرایش این چک‌لیست را ندارید")
+			return
+		}
+		if !isChecklistOwner(task, ownerID) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "این تسک به این کاربر اختصاص داده نشده است")
+			return
+		}
+		owners = []uint{ownerID}
+	}
+
+	items := make([]models.ChecklistItem, 0, len(owners))
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, ownerID := range owners {
+			position, err := nextChecklistPosition(tx, task.ID, ownerID)
+			if err != nil {
+				return err
+			}
+			item := models.ChecklistItem{
+				TaskID:    task.ID,
+				UserID:    ownerID,
+				Title:     title,
+				Position:  position,
+				CreatedBy: userID,
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+			items = append(items, item)
+
+			// آیتم جدید تیک‌نخورده درصد پیشرفت را کم می‌کند
+			if err := services.SyncChecklistProgress(tx, task, ownerID, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		checklistError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "آیتم چک‌لیست اضافه شد", items)
+}
+
+// UpdateChecklistItem - تغییر عنوان آیتم
+func UpdateChecklistItem(c *gin.Context) {
+	var req UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "عنوان آیتم نمی‌تواند خالی باشد")
+		return
+	}
+
+	_, item, ok := loadChecklistItem(c)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Model(item).Update("title", title).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی آیتم")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "آیتم چک‌لیست بروزرسانی شد", item)
+}
+
+// DeleteChecklistItem - حذف آیتم و محاسبه مجدد پیشرفت
+func DeleteChecklistItem(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	task, item, ok := loadChecklistItem(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(item).Error; err != nil {
+			return err
+		}
+		return services.SyncChecklistProgress(tx, task, item.UserID, userID)
+	})
+	if err != nil {
+		checklistError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "آیتم چک‌لیست حذف شد", nil)
+}
+
+// CheckChecklistItem - تیک زدن آیتم
+func CheckChecklistItem(c *gin.Context) {
+	setChecklistItemChecked(c, true)
+}
+
+// UncheckChecklistItem - برداشتن تیک آیتم
+func UncheckChecklistItem(c *gin.Context) {
+	setChecklistItemChecked(c, false)
+}
+
+func setChecklistItemChecked(c *gin.Context, checked bool) {
+	userID := c.GetUint("userID")
+
+	task, item, ok := loadChecklistItem(c)
+	if !ok {
+		return
+	}
+	if item.Checked == checked {
+		utils.SuccessResponse(c, http.StatusOK, "تغییری ایجاد نشد", item)
+		return
+	}
+
+	item.Checked = checked
+	if checked {
+		now := time.Now()
+		item.CheckedAt = &now
+		item.CheckedBy = &userID
+	} else {
+		item.CheckedAt = nil
+		item.CheckedBy = nil
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(item).Error; err != nil {
+			return err
+		}
+		return services.SyncChecklistProgress(tx, task, item.UserID, userID)
+	})
+	if err != nil {
+		checklistError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "آیتم چک‌لیست بروزرسانی شد", item)
+}
+
+// ReorderChecklist - تعیین ترتیب جدید؛ item_ids باید دقیقا شامل تمام آیتم‌های چک‌لیست باشد
+func ReorderChecklist(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	ownerID := req.UserID
+	if ownerID == 0 || !task.IsGroupTask {
+		ownerID = userID
+	}
+	if !canEditChecklist(userID, ownerID, task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه ویرایش این چک‌لیست را ندارید")
+		return
+	}
+
+	var existing []uint
+	config.DB.Model(&models.ChecklistItem{}).Where("task_id = ? AND user_id = ?", task.ID, ownerID).Pluck("id", &existing)
+	known := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		known[id] = true
+	}
+	if len(req.ItemIDs) != len(existing) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "ترتیب باید شامل تمام آیتم‌های چک‌لیست باشد")
+		return
+	}
+	for _, id := range req.ItemIDs {
+		if !known[id] {
+			utils.ErrorResponse(c, http.StatusBadRequest, "ترتیب باید شامل تمام آیتم‌های چک‌لیست باشد")
+			return
+		}
+		delete(known, id) // شناسه تکراری
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for position, id := range req.ItemIDs {
+			if err := tx.Model(&models.ChecklistItem{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در مرتب‌سازی چک‌لیست")
+		return
+	}
+
+	var items []models.ChecklistItem
+	config.DB.Where("task_id = ? AND user_id = ?", task.ID, ownerID).Order("position ASC, id ASC").Find(&items)
+	utils.SuccessResponse(c, http.StatusOK, "ترتیب چک‌لیست ذخیره شد", items)
+}
@@ -0,0 +1,305 @@
+// backend/controllers/comment_controller.go
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxCommentLength حداکثر طول متن دیدگاه (کاراکتر)
+const maxCommentLength = 10000
+
+type CreateCommentRequest struct {
+	Body     string `json:"body" binding:"required"` // Markdown
+	ParentID *uint  `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// validCommentBody - متن دیدگاه خالی یا بیش از حد طولانی نباشد
+func validCommentBody(c *gin.Context, body string) (string, bool) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "متن دیدگاه نمی‌تواند خالی باشد")
+		return "", false
+	}
+	if len([]rune(body)) > maxCommentLength {
+		utils.ErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("متن دیدگاه حداکثر %d کاراکتر است", maxCommentLength))
+		return "", false
+	}
+	return body, true
+}
+
+// loadTaskComment - دریافت دیدگاه تسک با بررسی دسترسی به تسک؛ در صورت خطا پاسخ نوشته می‌شود
+func loadTaskComment(c *gin.Context) (*models.Task, *models.TaskComment, bool) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return nil, nil, false
+	}
+
+	var comment models.TaskComment
+	if err := config.DB.Where("id = ? AND task_id = ?", c.Param("comment_id"), task.ID).First(&comment).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "دیدگاه پیدا نشد")
+		return nil, nil, false
+	}
+	return task, &comment, true
+}
+
+// mentionedMembers - کاربران اشاره‌شده با @username که عضو پذیرفته‌شده گروه تسک هستند
+// در تسک شخصی کسی جز سازنده به تسک دسترسی ندارد، پس اشاره‌ای اعلان نمی‌شود
+func mentionedMembers(tx *gorm.DB, task *models.Task, body string) ([]uint, error) {
+	usernames := utils.ExtractMentions(body)
+	if !task.IsGroupTask || task.GroupID == nil || len(usernames) == 0 {
+		return nil, nil
+	}
+	var ids []uint
+	err := tx.Table("users").
+		Joins("JOIN group_members ON group_members.user_id = users.id").
+		Where("users.username IN ? AND group_members.group_id = ? AND group_members.accepted = ?", usernames, *task.GroupID, true).
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
+
+// notifyMentions - اعلان به اعضای اشاره‌شده؛ اعضایی که در except هستند (مثلا قبلا اعلان گرفته‌اند) و نویسنده حذف می‌شوند
+func notifyMentions(tx *gorm.DB, task *models.Task, comment *models.TaskComment, except []uint) error {
+	mentioned, err := mentionedMembers(tx, task, comment.Body)
+	if err != nil {
+		return err
+	}
+	skip := map[uint]bool{comment.UserID: true}
+	for _, id := range except {
+		skip[id] = true
+	}
+	var recipients []uint
+	for _, id := range mentioned {
+		if !skip[id] {
+			recipients = append(recipients, id)
+		}
+	}
+	return services.Notify(tx, recipients, models.NotificationMention,
+		"در یک دیدگاه به شما اشاره شد",
+		fmt.Sprintf("در دیدگاهی روی تسک «%s» به شما اشاره شده است", task.Title),
+		task.ID)
+}
+
+// GetTaskComments - دریافت گفتگوی تسک به صورت درختی، مرتب بر اساس زمان ایجاد
+func GetTaskComments(c *gin.Context) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var comments []*models.TaskComment
+	if err := config.DB.Preload("User").
+		Where("task_id = ?", task.ID).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت دیدگاه‌ها")
+		return
+	}
+
+	byID := make(map[uint]*models.TaskComment, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+	}
+	var threads []*models.TaskComment
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			if parent, ok := byID[*comment.ParentID]; ok {
+				parent.Replies = append(parent.Replies, comment)
+				continue
+			}
+		}
+		threads = append(threads, comment)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", threads)
+}
+
+// CreateTaskComment - افزودن دیدگاه یا پاسخ به دیدگاه
+func CreateTaskComment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	body, ok := validCommentBody(c, req.Body)
+	if !ok {
+		return
+	}
+
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if req.ParentID != nil {
+		var parent models.TaskComment
+		if err := config.DB.Where("id = ? AND task_id = ?", *req.ParentID, task.ID).First(&parent).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "دیدگاه والد در این تسک پیدا نشد")
+			return
+		}
+	}
+
+	comment := models.TaskComment{
+		TaskID:   task.ID,
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Body:     body,
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		return notifyMentions(tx, task, &comment, nil)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ثبت دیدگاه")
+		return
+	}
+
+	config.DB.Preload("User").First(&comment, comment.ID)
+	utils.SuccessResponse(c, http.StatusCreated, "دیدگاه ثبت شد", comment)
+}
+
+// UpdateTaskComment - ویرایش دیدگاه توسط نویسنده؛ متن قبلی در تاریخچه ذخیره می‌شود
+func UpdateTaskComment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	body, ok := validCommentBody(c, req.Body)
+	if !ok {
+		return
+	}
+
+	task, comment, ok := loadTaskComment(c)
+	if !ok {
+		return
+	}
+	if comment.UserID != userID {
+		utils.ErrorResponse(c, http.StatusForbidden, "فقط نویسنده می‌تواند دیدگاه را ویرایش کند")
+		return
+	}
+	if comment.DeletedAt != nil {
+		utils.ErrorResponse(c, http.StatusConflict, "دیدگاه حذف شده است")
+		return
+	}
+	if comment.Body == body {
+		utils.SuccessResponse(c, http.StatusOK, "تغییری ایجاد نشد", comment)
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// اعضایی که در متن قبلی اشاره شده بودند دوباره اعلان نمی‌گیرند
+		previous, err := mentionedMembers(tx, task, comment.Body)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&models.CommentRevision{
+			CommentID: comment.ID,
+			Body:      comment.Body,
+			Action:    models.CommentActionEdit,
+			ChangedBy: userID,
+		}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		comment.Body = body
+		comment.EditedAt = &now
+		if err := tx.Model(comment).Updates(map[string]interface{}{"body": body, "edited_at": now}).Error; err != nil {
+			return err
+		}
+		return notifyMentions(tx, task, comment, previous)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ویرایش دیدگاه")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "دیدگاه ویرایش شد", comment)
+}
+
+// DeleteTaskComment - حذف دیدگاه توسط نویسنده یا مدیر تسک؛ پاسخ‌ها باقی می‌مانند
+func DeleteTaskComment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	task, comment, ok := loadTaskComment(c)
+	if !ok {
+		return
+	}
+	if comment.UserID != userID && !canManageTask(userID, task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه حذف این دیدگاه را ندارید")
+		return
+	}
+	if comment.DeletedAt != nil {
+		utils.ErrorResponse(c, http.StatusConflict, "دیدگاه قبلا حذف شده است")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.CommentRevision{
+			CommentID: comment.ID,
+			Body:      comment.Body,
+			Action:    models.CommentActionDelete,
+			ChangedBy: userID,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(comment).Updates(map[string]interface{}{
+			"body":       "",
+			"deleted_at": time.Now(),
+			"deleted_by": userID,
+		}).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف دیدگاه")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "دیدگاه حذف شد", nil)
+}
+
+// GetCommentHistory - تاریخچه ویرایش و حذف دیدگاه؛ برای نویسنده و مدیر تسک
+func GetCommentHistory(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	task, comment, ok := loadTaskComment(c)
+	if !ok {
+		return
+	}
+	if comment.UserID != userID && !canManageTask(userID, task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه مشاهده تاریخچه این دیدگاه را ندارید")
+		return
+	}
+
+	var revisions []models.CommentRevision
+	if err := config.DB.Preload("User").
+		Where("comment_id = ?", comment.ID).
+		Order("created_at ASC, id ASC").
+		Find(&revisions).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت تاریخچه دیدگاه")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", revisions)
+}
@@ -0,0 +1,183 @@
+// backend/controllers/dependency_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AddDependencyRequest struct {
+	DependsOnID uint `json:"depends_on_id" binding:"required"`
+}
+
+type TaskDependencies struct {
+	DependsOn []models.Task `json:"depends_on"` // پیش‌نیازهای این تسک
+	Blocking  []models.Task `json:"blocking"`   // تسک‌هایی که منتظر این تسک هستند
+	Blocked   bool          `json:"blocked"`
+}
+
+type BlockedTask struct {
+	Task      models.Task   `json:"task"`
+	BlockedBy []models.Task `json:"blocked_by"`
+}
+
+// GetTaskDependencies - دریافت پیش‌نیازها و وابسته‌های یک تسک
+func GetTaskDependencies(c *gin.Context) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var result TaskDependencies
+	config.DB.Joins("JOIN task_dependencies ON task_dependencies.depends_on_id = tasks.id").
+		Where("task_dependencies.task_id = ?", task.ID).
+		Find(&result.DependsOn)
+	config.DB.Joins("JOIN task_dependencies ON task_dependencies.task_id = tasks.id").
+		Where("task_dependencies.depends_on_id = ?", task.ID).
+		Find(&result.Blocking)
+
+	for _, dependency := range result.DependsOn {
+		if dependency.Status != models.StatusCompleted {
+			result.Blocked = true
+			break
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", result)
+}
+
+// AddTaskDependency - افزودن پیش‌نیاز به تسک
+func AddTaskDependency(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if !canManageTask(userID, task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه مدیریت وابستگی‌های این تسک را ندارید")
+		return
+	}
+
+	var dependsOn models.Task
+	if err := config.DB.First(&dependsOn, req.DependsOnID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "تسک پیش‌نیاز پیدا نشد")
+		return
+	}
+
+	// وابستگی فقط بین تسک‌های شخصی یک کاربر یا تسک‌های یک گروه مجاز است
+	sameScope := task.IsGroupTask == dependsOn.IsGroupTask
+	if sameScope && task.IsGroupTask {
+		sameScope = dependsOn.GroupID != nil && *dependsOn.GroupID == *task.GroupID
+	} else if sameScope {
+		sameScope = dependsOn.CreatorID == task.CreatorID
+	}
+	if !sameScope {
+		utils.ErrorResponse(c, http.StatusBadRequest, "وابستگی فقط بین تسک‌های یک گروه یا تسک‌های شخصی خودتان ممکن است")
+		return
+	}
+
+	var dependency *models.TaskDependency
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		dependency, err = services.AddDependency(tx, task.ID, dependsOn.ID, userID)
+		return err
+	})
+	if errors.Is(err, services.ErrDependencyCycle) {
+		utils.ErrorResponse(c, http.StatusConflict, "این وابستگی باعث ایجاد حلقه می‌شود")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ثبت وابستگی")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "وابستگی با موفقیت ثبت شد", dependency)
+}
+
+// RemoveTaskDependency - حذف پیش‌نیاز از تسک
+func RemoveTaskDependency(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if !canManageTask(userID, task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه مدیریت وابستگی‌های این تسک را ندارید")
+		return
+	}
+
+	result := config.DB.Where("task_id = ? AND depends_on_id = ?", task.ID, c.Param("depends_on_id")).
+		Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف وابستگی")
+		return
+	}
+	if result.RowsAffected == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, "وابستگی پیدا نشد")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "وابستگی با موفقیت حذف شد", nil)
+}
+
+// GetBlockedOnMe - تسک‌هایی که منتظر تکمیل تسک‌های من هستند
+// تسک‌های من: تسک‌های شخصی خودم و تسک‌های گروهی که به من اختصاص داده شده و هنوز انجام نداده‌ام
+func GetBlockedOnMe(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var myOpenTasks []models.Task
+	if err := config.DB.Where("status <> ?", models.StatusCompleted).
+		Where(config.DB.Where("creator_id = ? AND is_group_task = ?", userID, false).
+			Or("id IN (?)", config.DB.Model(&models.TaskAssignment{}).
+				Select("task_id").
+				Where("user_id = ? AND completed = ?", userID, false))).
+		Find(&myOpenTasks).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت تسک‌ها")
+		return
+	}
+
+	mine := make(map[uint]models.Task, len(myOpenTasks))
+	ids := make([]uint, 0, len(myOpenTasks))
+	for _, task := range myOpenTasks {
+		mine[task.ID] = task
+		ids = append(ids, task.ID)
+	}
+
+	result := []BlockedTask{}
+	if len(ids) > 0 {
+		var edges []models.TaskDependency
+		config.DB.Where("depends_on_id IN ?", ids).Preload("Task").Order("task_id").Find(&edges)
+
+		index := make(map[uint]int)
+		for _, edge := range edges {
+			if edge.Task == nil || edge.Task.Status == models.StatusCompleted {
+				continue
+			}
+			i, seen := index[edge.TaskID]
+			if !seen {
+				i = len(result)
+				index[edge.TaskID] = i
+				result = append(result, BlockedTask{Task: *edge.Task})
+			}
+			result[i].BlockedBy = append(result[i].BlockedBy, mine[edge.DependsOnID])
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", result)
+}
@@ -3,13 +3,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"task-manager/config"
 	"task-manager/models"
+	"task-manager/services"
 	"task-manager/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // ApproveGroupTaskFile - تایید فایل برای تسک گروهی توسط مدیر
@@ -25,10 +28,8 @@ func ApproveGroupTaskFile(c *gin.Context) {
 		return
 	}
 
-	// بررسی اینکه کاربر مدیر گروه است
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ?", groupID, userID, "admin").First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌توانند فایل‌ها را تایید کنند")
+	// بررسی اجازه تایید فایل
+	if _, ok := authorizeGroup(c, StringToUint(groupID), models.PermApproveFiles, "شما اجازه تایید فایل‌های این گروه را ندارید"); !ok {
 		return
 	}
 
@@ -44,12 +45,44 @@ func ApproveGroupTaskFile(c *gin.Context) {
 		return
 	}
 
+	// فایل متصل به نسخه ارسالی فقط همراه کل نسخه بررسی می‌شود
+	if file.SubmissionID != nil {
+		var submission models.TaskSubmission
+		if err := config.DB.First(&submission, *file.SubmissionID).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusNotFound, "نسخه ارسالی فایل پیدا نشد")
+			return
+		}
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			return services.ReviewSubmission(tx, &task, &submission, models.ReviewApprove, "", userID)
+		})
+		if errors.Is(err, services.ErrNotAwaitingReview) {
+			utils.ErrorResponse(c, http.StatusConflict, "نسخه ارسالی این فایل قبلا بررسی شده است")
+			return
+		}
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در تایید فایل")
+			return
+		}
+		utils.SuccessResponse(c, http.StatusOK, "نسخه ارسالی همراه فایل‌هایش تایید شد", submission)
+		return
+	}
+
 	// تایید فایل
 	file.Approved = true
 	file.ApprovedBy = &userID
 	now := time.Now()
 	file.ApprovedAt = &now
-	if err := config.DB.Save(&file).Error; err != nil {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&file).Error; err != nil {
+			return err
+		}
+		return services.LogTaskActivity(tx, &task, userID, models.ActivityFileApproved, map[string]interface{}{
+			"file_id":     file.ID,
+			"filename":    file.Filename,
+			"uploaded_by": file.UserID,
+		})
+	})
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در تایید فایل")
 		return
 	}
@@ -9,12 +9,28 @@ import (
 	"strconv"
 	"task-manager/config"
 	"task-manager/models"
+	"task-manager/services"
 	"task-manager/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+var fileListSpec = utils.ListSpec{
+	Table: "files",
+	Sortable: map[string]string{
+		"id":   "files.id",
+		"name": "files.filename",
+		"size": "files.file_size",
+	},
+	DefaultSort: "-id",
+	Searchable:  []string{"files.filename"},
+	Filters: map[string]string{
+		"mime_type": "files.mime_type",
+	},
+}
+
 // UploadFile - آپلود فایل برای تسک (شخصی یا گروهی)
 func UploadFile(c *gin.Context) {
 	userID := c.GetUint("userID")
@@ -44,12 +60,14 @@ func UploadFile(c *gin.Context) {
 
 	// بررسی دسترسی کاربر به تسک
 	if task.IsGroupTask {
-		// برای تسک گروهی، بررسی عضویت در گروه
-		var member models.GroupMember
-		if err := config.DB.Where("group_id = ? AND user_id = ? AND accepted = ?", task.GroupID, userID, true).First(&member).Error; err != nil {
+		// برای تسک گروهی، نقش کاربر باید اجازه انجام تسک داشته باشد
+		if task.GroupID == nil {
 			utils.ErrorResponse(c, http.StatusForbidden, "شما به این تسک دسترسی ندارید")
 			return
 		}
+		if _, ok := authorizeGroup(c, *task.GroupID, models.PermContribute, "شما اجازه آپلود فایل برای این تسک را ندارید"); !ok {
+			return
+		}
 	} else {
 		// برای تسک شخصی، بررسی مالکیت
 		if task.CreatorID != userID {
@@ -94,24 +112,42 @@ func UploadFile(c *gin.Context) {
 		TaskID:   uint(taskID),
 	}
 
-	config.DB.Create(&fileRecord)
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&fileRecord).Error; err != nil {
+			return err
+		}
 
-	// اگر تسک گروهی است، آپدیت یادداشت در پیشرفت
-	if task.IsGroupTask {
-		var groupProgress models.GroupTaskProgress
-		if err := config.DB.Where("task_id = ? AND user_id = ?", taskID, userID).First(&groupProgress).Error; err != nil {
-			// ایجاد رکورد جدید اگر وجود ندارد
-			groupProgress = models.GroupTaskProgress{
-				TaskID:     uint(taskID),
-				UserID:     userID,
-				AssignedBy: task.CreatorID,
-				Notes:      notes,
+		// اگر تسک گروهی است، آپدیت یادداشت در پیشرفت
+		if task.IsGroupTask {
+			var groupProgress models.GroupTaskProgress
+			if err := tx.Where("task_id = ? AND user_id = ?", taskID, userID).First(&groupProgress).Error; err != nil {
+				// ایجاد رکورد جدید اگر وجود ندارد
+				groupProgress = models.GroupTaskProgress{
+					TaskID:     uint(taskID),
+					UserID:     userID,
+					AssignedBy: task.CreatorID,
+					Notes:      notes,
+				}
+				if err := tx.Create(&groupProgress).Error; err != nil {
+					return err
+				}
+			} else {
+				groupProgress.Notes = notes
+				if err := tx.Save(&groupProgress).Error; err != nil {
+					return err
+				}
 			}
-			config.DB.Create(&groupProgress)
-		} else {
-			groupProgress.Notes = notes
-			config.DB.Save(&groupProgress)
 		}
+
+		return services.LogTaskActivity(tx, &task, userID, models.ActivityFileUploaded, map[string]interface{}{
+			"file_id":  fileRecord.ID,
+			"filename": fileRecord.Filename,
+		})
+	})
+	if err != nil {
+		os.Remove(filePath)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ثبت فایل")
+		return
 	}
 
 	utils.SuccessResponse(c, http.StatusCreated, "فایل با موفقیت آپلود شد", fileRecord)
@@ -142,10 +178,20 @@ func GetTaskFiles(c *gin.Context) {
 		}
 	}
 
+	list, err := utils.ParseListQuery(c, fileListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var files []models.File
-	config.DB.Where("task_id = ?", taskID).Preload("User").Find(&files)
+	query := config.DB.Model(&models.File{}).Where("task_id = ?", taskID)
+	if err := list.Find(query, &files, preloadFileUser); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت فایل‌ها")
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "OK", files)
+	utils.ListResponse(c, files, list)
 }
 
 // GetGroupTaskFilesByUser - دریافت فایل‌های آپلود شده توسط یک کاربر برای تسک گروهی
@@ -172,10 +218,24 @@ func GetGroupTaskFilesByUser(c *gin.Context) {
 		return
 	}
 
+	list, err := utils.ParseListQuery(c, fileListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var files []models.File
-	config.DB.Where("task_id = ? AND user_id = ?", taskID, uploadUserID).Preload("User").Find(&files)
+	query := config.DB.Model(&models.File{}).Where("task_id = ? AND user_id = ?", taskID, uploadUserID)
+	if err := list.Find(query, &files, preloadFileUser); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت فایل‌ها")
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "OK", files)
+	utils.ListResponse(c, files, list)
+}
+
+func preloadFileUser(db *gorm.DB) *gorm.DB {
+	return db.Preload("User")
 }
 
 // DownloadFile - دانلود فایل
@@ -218,7 +278,7 @@ func DeleteFile(c *gin.Context) {
 	fileID := c.Param("id")
 
 	var file models.File
-	if err := config.DB.Preload("Task").First(&file, fileID).Error; err != nil {
+	if err := config.DB.Preload("Task").First(&file, fileID).Error; err != nil || file.Task == nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "فایل پیدا نشد")
 		return
 	}
@@ -232,13 +292,26 @@ func DeleteFile(c *gin.Context) {
 		return
 	}
 
-	// حذف فایل فیزیکی
-	if _, err := os.Stat(file.Filepath); err == nil {
-		os.Remove(file.Filepath)
+	// فایل‌های نسخه ارسالی سابقه بررسی‌اند و حذف نمی‌شوند
+	if file.SubmissionID != nil {
+		utils.ErrorResponse(c, http.StatusConflict, "این فایل بخشی از یک نسخه ارسالی است و قابل حذف نیست")
+		return
 	}
 
-	// حذف رکورد دیتابیس
-	config.DB.Delete(&file)
+	// انتقال فایل به سطل زباله؛ فایل فیزیکی هنگام پاک‌سازی نهایی حذف می‌شود
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&file).Error; err != nil {
+			return err
+		}
+		return services.LogTaskActivity(tx, file.Task, userID, models.ActivityFileDeleted, map[string]interface{}{
+			"file_id":  file.ID,
+			"filename": file.Filename,
+		})
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف فایل")
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "فایل با موفقیت حذف شد", nil)
+	utils.SuccessResponse(c, http.StatusOK, "فایل به سطل زباله منتقل شد", nil)
 }
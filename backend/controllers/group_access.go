@@ -0,0 +1,41 @@
+// backend/controllers/group_access.go
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authorizeGroup - بررسی یکسان دسترسی تمام endpointهای گروه بر اساس ماتریس اجازه نقش‌ها
+// perm خالی فقط عضویت پذیرفته‌شده را بررسی می‌کند؛ در صورت خطا پاسخ نوشته می‌شود
+func authorizeGroup(c *gin.Context, groupID uint, perm models.Permission, message string) (*models.GroupMember, bool) {
+	member, err := services.AuthorizeGroup(config.DB, groupID, c.GetUint("userID"), perm)
+	if errors.Is(err, services.ErrForbidden) {
+		if message == "" {
+			message = "شما عضو این گروه نیستید"
+		}
+		utils.ErrorResponse(c, http.StatusForbidden, message)
+		return nil, false
+	}
+	if errors.Is(err, services.ErrGroupNotFound) {
+		utils.ErrorResponse(c, http.StatusNotFound, "گروه پیدا نشد")
+		return nil, false
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بررسی دسترسی")
+		return nil, false
+	}
+	return member, true
+}
+
+// hasGroupPermission - نسخه بدون پاسخ authorizeGroup برای بررسی‌های ترکیبی
+func hasGroupPermission(userID, groupID uint, perm models.Permission) bool {
+	_, err := services.AuthorizeGroup(config.DB, groupID, userID, perm)
+	return err == nil
+}
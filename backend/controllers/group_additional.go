@@ -2,17 +2,20 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"task-manager/config"
 	"task-manager/models"
+	"task-manager/services"
 	"task-manager/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetGroupDetails - دریافت جزئیات یک گروه
 func GetGroupDetails(c *gin.Context) {
-	userID := c.GetUint("userID")
 	groupID := c.Param("id")
 
 	var group models.Group
@@ -23,35 +26,48 @@ func GetGroupDetails(c *gin.Context) {
 	}
 
 	// بررسی عضویت کاربر در گروه
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND accepted = ?", groupID, userID, true).
-		First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "شما عضو این گروه نیستید")
+	if _, ok := authorizeGroup(c, StringToUint(groupID), "", ""); !ok {
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "OK", group)
 }
 
+// GetGroupMembers - دریافت اعضای پذیرفته‌شده گروه همراه با نقش آن‌ها
+func GetGroupMembers(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+	if !ok {
+		return
+	}
+
+	var members []models.GroupMember
+	if err := config.DB.Where("group_id = ? AND accepted = ?", member.GroupID, true).
+		Preload("User").
+		Order("id ASC").
+		Find(&members).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت اعضای گروه")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", members)
+}
+
 // UpdateGroup - بروزرسانی گروه
 func UpdateGroup(c *gin.Context) {
-	userID := c.GetUint("userID")
 	groupID := c.Param("id")
 
 	var req struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
+		Visibility  string `json:"visibility"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// بررسی اینکه کاربر مدیر گروه است
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ?", groupID, userID, "admin").
-		First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌توانند گروه را بروزرسانی کنند")
+	// بررسی اجازه ویرایش تنظیمات گروه
+	if _, ok := authorizeGroup(c, StringToUint(groupID), models.PermEditSettings, "شما اجازه ویرایش تنظیمات گروه را ندارید"); !ok {
 		return
 	}
 
@@ -67,21 +83,31 @@ func UpdateGroup(c *gin.Context) {
 	if req.Description != "" {
 		group.Description = req.Description
 	}
+	if req.Visibility != "" {
+		if !services.IsValidVisibility(req.Visibility) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "نمایش گروه باید private، discoverable یا open باشد")
+			return
+		}
+		group.Visibility = req.Visibility
+	}
 
 	config.DB.Save(&group)
 	utils.SuccessResponse(c, http.StatusOK, "گروه با موفقیت بروزرسانی شد", group)
 }
 
-// DeleteGroup - حذف گروه
+// DeleteGroup - انتقال گروه به سطل زباله؛ با permanent=true گروه (حتی از سطل زباله) همراه تمام تسک‌ها،
+// عضویت‌ها، پیشرفت‌ها، اعلان‌ها و فایل‌هایش در یک تراکنش برای همیشه حذف می‌شود
 func DeleteGroup(c *gin.Context) {
 	userID := c.GetUint("userID")
 	groupID := c.Param("id")
 
-	// بررسی اینکه کاربر مدیر گروه است
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ?", groupID, userID, "admin").
-		First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌توانند گروه را حذف کنند")
+	// بررسی اجازه حذف گروه
+	if _, ok := authorizeGroup(c, StringToUint(groupID), models.PermDeleteGroup, "شما اجازه حذف این گروه را ندارید"); !ok {
+		return
+	}
+
+	if c.Query("permanent") == "true" {
+		purgeGroup(c, groupID)
 		return
 	}
 
@@ -91,8 +117,107 @@ func DeleteGroup(c *gin.Context) {
 		return
 	}
 
-	config.DB.Delete(&group)
-	utils.SuccessResponse(c, http.StatusOK, "گروه با موفقیت حذف شد", nil)
+	// گروه و تسک‌هایش به سطل زباله می‌روند و تا پایان دوره نگهداری قابل بازگردانی هستند
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.TrashGroup(tx, &group); err != nil {
+			return err
+		}
+		return services.LogGroupActivity(tx, group.ID, userID, models.ActivityGroupDeleted, map[string]interface{}{"name": group.Name})
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف گروه")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "گروه به سطل زباله منتقل شد", nil)
+}
+
+// purgeGroup - حذف دائمی گروه؛ فایل‌های روی دیسک فقط پس از commit پاک می‌شوند
+func purgeGroup(c *gin.Context, groupID string) {
+	userID := c.GetUint("userID")
+
+	var group models.Group
+	if err := config.DB.Unscoped().First(&group, groupID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "گروه پیدا نشد")
+		return
+	}
+
+	var paths []string
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.LogGroupActivity(tx, group.ID, userID, models.ActivityGroupDeleted, map[string]interface{}{
+			"name":      group.Name,
+			"permanent": true,
+		}); err != nil {
+			return err
+		}
+		var err error
+		paths, err = services.PurgeGroup(tx, &group)
+		return err
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف گروه")
+		return
+	}
+	services.RemoveStoredFiles(paths)
+
+	utils.SuccessResponse(c, http.StatusOK, "گروه برای همیشه حذف شد", nil)
+}
+
+// ArchiveGroup - بایگانی گروه؛ گروه و تسک‌هایش قابل مشاهده می‌مانند ولی تغییر نمی‌کنند
+func ArchiveGroup(c *gin.Context) {
+	setGroupArchived(c, true)
+}
+
+// UnarchiveGroup - خارج کردن گروه از بایگانی
+func UnarchiveGroup(c *gin.Context) {
+	setGroupArchived(c, false)
+}
+
+func setGroupArchived(c *gin.Context, archived bool) {
+	userID := c.GetUint("userID")
+	groupID := c.Param("id")
+
+	// بررسی اجازه ویرایش تنظیمات گروه
+	if _, ok := authorizeGroup(c, StringToUint(groupID), models.PermEditSettings, "شما اجازه بایگانی این گروه را ندارید"); !ok {
+		return
+	}
+
+	var group models.Group
+	if err := config.DB.First(&group, groupID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "گروه پیدا نشد")
+		return
+	}
+	if (group.ArchivedAt != nil) == archived {
+		utils.SuccessResponse(c, http.StatusOK, "تغییری ایجاد نشد", group)
+		return
+	}
+
+	action := models.ActivityGroupUnarchived
+	group.ArchivedAt = nil
+	group.ArchivedBy = nil
+	if archived {
+		now := time.Now()
+		action = models.ActivityGroupArchived
+		group.ArchivedAt = &now
+		group.ArchivedBy = &userID
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&group).Select("archived_at", "archived_by").Updates(&group).Error; err != nil {
+			return err
+		}
+		return services.LogGroupActivity(tx, group.ID, userID, action, nil)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بایگانی گروه")
+		return
+	}
+
+	message := "گروه از بایگانی خارج شد"
+	if archived {
+		message = "گروه بایگانی شد"
+	}
+	utils.SuccessResponse(c, http.StatusOK, message, group)
 }
 
 // RemoveMember - حذف عضو از گروه
@@ -101,50 +226,99 @@ func RemoveMember(c *gin.Context) {
 	groupID := c.Param("id")
 	memberID := c.Param("user_id")
 
-	// بررسی اینکه کاربر مدیر گروه است
-	var adminMember models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ?", groupID, userID, "admin").
-		First(&adminMember).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌توانند اعضا را حذف کنند")
+	// بررسی اجازه مدیریت اعضا
+	adminMember, ok := authorizeGroup(c, StringToUint(groupID), models.PermManageMembers, "شما اجازه حذف اعضای این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	var target models.GroupMember
+	if err := config.DB.Where("group_id = ? AND user_id = ?", adminMember.GroupID, memberID).First(&target).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "عضو پیدا نشد")
+		return
+	}
+	// مانند تغییر نقش: مدیر را فقط مالک حذف می‌کند و عضوی با اجازه‌های بیشتر از حذف‌کننده حذف نمی‌شود
+	if target.UserID != userID && !canGrantRoles(c, adminMember, target.Role) {
 		return
 	}
 
-	// حذف عضو
-	if err := config.DB.Where("group_id = ? AND user_id = ?", groupID, memberID).
-		Delete(&models.GroupMember{}).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف عضو")
+	// حذف عضو؛ مالک و آخرین مدیر بدون تعیین جانشین حذف نمی‌شوند
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.RemoveMembership(tx, adminMember.GroupID, target.UserID, userID, models.ActivityMemberRemoved)
+	})
+	if err != nil {
+		membershipError(c, err, "خطا در حذف عضو")
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "عضو با موفقیت از گروه حذف شد", nil)
 }
 
-// AcceptInvitation - پذیرفتن دعوت به گروه
-func AcceptInvitation(c *gin.Context) {
+// LeaveGroup - خروج کاربر از گروه؛ مالک ابتدا باید مالکیت را منتقل کند و آخرین مدیر جانشین تعیین کند
+func LeaveGroup(c *gin.Context) {
 	userID := c.GetUint("userID")
-	groupID := c.Param("id")
 
-	// پیدا کردن دعوت
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, "دعوت پیدا نشد")
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+	if !ok {
 		return
 	}
 
-	// اگر قبلاً پذیرفته شده
-	if member.Accepted {
-		utils.SuccessResponse(c, http.StatusOK, "شما قبلاً این دعوت را پذیرفته‌اید", member)
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.RemoveMembership(tx, member.GroupID, userID, userID, models.ActivityMemberLeft)
+	})
+	if err != nil {
+		membershipError(c, err, "خطا در خروج از گروه")
 		return
 	}
 
-	// پذیرفتن دعوت
-	member.Accepted = true
-	if err := config.DB.Save(&member).Error; err != nil {
+	utils.SuccessResponse(c, http.StatusOK, "شما از گروه خارج شدید", nil)
+}
+
+// AcceptInvitation - پذیرفتن دعوت به گروه
+func AcceptInvitation(c *gin.Context) {
+	userID := c.GetUint("userID")
+	groupID := c.Param("id")
+
+	// پذیرفتن دعوت و اعلان به دعوت‌کننده
+	var member *models.GroupMember
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		member, err = services.AcceptInvitation(tx, StringToUint(groupID), userID)
+		return err
+	})
+	switch {
+	case errors.Is(err, services.ErrNotGroupMember):
+		utils.ErrorResponse(c, http.StatusNotFound, "دعوت پیدا نشد")
+	case errors.Is(err, services.ErrAlreadyMember):
+		// اگر قبلاً پذیرفته شده
+		utils.SuccessResponse(c, http.StatusOK, "شما قبلاً این دعوت را پذیرفته‌اید", member)
+	case errors.Is(err, services.ErrInvitationExpired):
+		utils.ErrorResponse(c, http.StatusGone, "این دعوت منقضی شده است")
+	case err != nil:
 		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در پذیرش دعوت")
-		return
+	default:
+		utils.SuccessResponse(c, http.StatusOK, "دعوت با موفقیت پذیرفته شد", member)
 	}
+}
 
-	utils.SuccessResponse(c, http.StatusOK, "دعوت با موفقیت پذیرفته شد", member)
+// RejectInvitation - رد دعوت به گروه؛ عضویت در انتظار حذف و دعوت‌کننده مطلع می‌شود
+func RejectInvitation(c *gin.Context) {
+	userID := c.GetUint("userID")
+	groupID := c.Param("id")
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.RejectInvitation(tx, StringToUint(groupID), userID)
+	})
+	switch {
+	case errors.Is(err, services.ErrNotGroupMember):
+		utils.ErrorResponse(c, http.StatusNotFound, "دعوت پیدا نشد")
+	case errors.Is(err, services.ErrAlreadyMember):
+		utils.ErrorResponse(c, http.StatusConflict, "شما قبلاً این دعوت را پذیرفته‌اید؛ برای خروج از گروه از leave استفاده کنید")
+	case err != nil:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در رد دعوت")
+	default:
+		utils.SuccessResponse(c, http.StatusOK, "دعوت رد شد", nil)
+	}
 }
 
 func GetPendingInvitations(c *gin.Context) {
@@ -5,14 +5,17 @@ import (
 	"net/http"
 	"task-manager/config"
 	"task-manager/models"
+	"task-manager/services"
 	"task-manager/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type CreateGroupRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
+	Visibility  string `json:"visibility"` // private (default), discoverable or open
 	UserIDs     []uint `json:"user_ids"`
 }
 
@@ -35,11 +38,20 @@ func CreateGroup(c *gin.Context) {
 		return
 	}
 
+	if req.Visibility == "" {
+		req.Visibility = models.GroupPrivate
+	}
+	if !services.IsValidVisibility(req.Visibility) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Visibility must be private, discoverable or open")
+		return
+	}
+
 	// Create group
 	group := models.Group{
 		Name:        req.Name,
 		Description: req.Description,
-		CreatorID:   userID,
+		Visibility:  req.Visibility,
+		CreatedBy:   userID,
 	}
 
 	if err := config.DB.Create(&group).Error; err != nil {
@@ -47,12 +59,12 @@ func CreateGroup(c *gin.Context) {
 		return
 	}
 
-	// Add creator as admin
+	// Add creator as owner
 	creatorMember := models.GroupMember{
 		GroupID:  group.ID,
 		UserID:   userID,
 		Accepted: true,
-		Role:     "admin",
+		Role:     models.RoleOwner,
 	}
 
 	if err := config.DB.Create(&creatorMember).Error; err != nil {
@@ -70,21 +82,13 @@ func CreateGroup(c *gin.Context) {
 					continue // Skip if user doesn't exist
 				}
 
-				// Check if member already exists
-				var existingMember models.GroupMember
-				if err := config.DB.Where("group_id = ? AND user_id = ?", group.ID, memberID).First(&existingMember).Error; err != nil {
-					// Member doesn't exist, create new
-					newMember := models.GroupMember{
-						GroupID:  group.ID,
-						UserID:   memberID,
-						Accepted: false,
-						Role:     "member",
-					}
-
-					if err := config.DB.Create(&newMember).Error; err != nil {
-						// Log error but continue with other members
-						continue
-					}
+				// Invite as a pending member; existing members are skipped
+				if err := config.DB.Transaction(func(tx *gorm.DB) error {
+					_, err := services.InviteMember(tx, group.ID, memberID, userID, models.RoleMember)
+					return err
+				}); err != nil {
+					// Log error but continue with other members
+					continue
 				}
 			}
 		}
@@ -117,26 +121,90 @@ func GetUserGroups(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "OK", groups)
 }
 
+var groupDiscoverySpec = utils.ListSpec{
+	Table: "groups",
+	Sortable: map[string]string{
+		"name":       "groups.name",
+		"created_at": "groups.created_at",
+	},
+	DefaultSort: "name",
+	Searchable:  []string{"groups.name", "groups.description"},
+	Filters:     map[string]string{"visibility": "groups.visibility"},
+}
+
+// DiscoveredGroup a discoverable group as seen by non-members; the member list itself is never exposed
+type DiscoveredGroup struct {
+	models.Group
+	MemberCount int64  `json:"member_count"`
+	Membership  string `json:"membership"` // member, invited, requested or empty
+}
+
+// SearchGroups - discovery of discoverable and open groups only
 func SearchGroups(c *gin.Context) {
-	query := c.Query("q")
+	userID := c.GetUint("userID")
+
+	list, err := utils.ParseListQuery(c, groupDiscoverySpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var groups []models.Group
+	// non-members only see the creator's public fields, never the email
+	creator := func(db *gorm.DB) *gorm.DB { return db.Select("id", "username") }
+	if err := list.Find(services.DiscoverableGroups(config.DB).Preload("Creator", creator), &groups); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search groups")
+		return
+	}
 
-	if query != "" {
-		if err := config.DB.Where("name LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%").
-			Preload("Members.User").
-			Preload("Creator").
-			Find(&groups).Error; err != nil {
+	groupIDs := make([]uint, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.ID
+	}
+	var counts []struct {
+		GroupID uint
+		Total   int64
+	}
+	var memberships []models.GroupMember
+	var requested []uint
+	if len(groupIDs) > 0 {
+		if err := config.DB.Model(&models.GroupMember{}).
+			Select("group_id, COUNT(*) AS total").
+			Where("group_id IN ? AND accepted = ?", groupIDs, true).
+			Group("group_id").
+			Scan(&counts).Error; err != nil {
 			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search groups")
 			return
 		}
-	} else {
-		if err := config.DB.Preload("Members.User").Preload("Creator").Find(&groups).Error; err != nil {
-			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to load groups")
-			return
+		config.DB.Where("group_id IN ? AND user_id = ?", groupIDs, userID).Find(&memberships)
+		config.DB.Model(&models.GroupJoinRequest{}).
+			Where("group_id IN ? AND user_id = ? AND status = ?", groupIDs, userID, models.JoinRequestPending).
+			Pluck("group_id", &requested)
+	}
+
+	totals := make(map[uint]int64, len(counts))
+	for _, count := range counts {
+		totals[count.GroupID] = count.Total
+	}
+	status := make(map[uint]string)
+	for _, groupID := range requested {
+		status[groupID] = "requested"
+	}
+	for _, membership := range memberships {
+		status[membership.GroupID] = "invited"
+		if membership.Accepted {
+			status[membership.GroupID] = "member"
 		}
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "OK", groups)
+	discovered := make([]DiscoveredGroup, len(groups))
+	for i, group := range groups {
+		group.Members = nil
+		group.Tasks = nil
+		discovered[i] = DiscoveredGroup{Group: group, MemberCount: totals[group.ID], Membership: status[group.ID]}
+	}
+
+	utils.ListResponse(c, discovered, list)
 }
 
 func AddGroupMembers(c *gin.Context) {
@@ -149,10 +217,9 @@ func AddGroupMembers(c *gin.Context) {
 		return
 	}
 
-	// Check if user is group admin
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ?", groupID, userID, "admin").First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "Only group admins can add members")
+	// Check if user may manage members
+	member, ok := authorizeGroup(c, StringToUint(groupID), models.PermManageMembers, "You are not allowed to add members to this group")
+	if !ok {
 		return
 	}
 
@@ -165,19 +232,12 @@ func AddGroupMembers(c *gin.Context) {
 				continue // Skip if user doesn't exist
 			}
 
-			var existingMember models.GroupMember
-			if err := config.DB.Where("group_id = ? AND user_id = ?", groupID, memberID).First(&existingMember).Error; err != nil {
-				// Member doesn't exist, create new
-				newMember := models.GroupMember{
-					GroupID:  member.GroupID,
-					UserID:   memberID,
-					Accepted: false,
-					Role:     "member",
-				}
-
-				if err := config.DB.Create(&newMember).Error; err != nil {
-					continue // Log error but continue with other members
-				}
+			// Invite as a pending member; existing members and pending invitations are skipped
+			if err := config.DB.Transaction(func(tx *gorm.DB) error {
+				_, err := services.InviteMember(tx, member.GroupID, memberID, userID, models.RoleMember)
+				return err
+			}); err != nil {
+				continue // Log error but continue with other members
 			}
 		}
 	}
@@ -185,23 +245,28 @@ func AddGroupMembers(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Members added successfully", nil)
 }
 
+var userListSpec = utils.ListSpec{
+	Table: "users",
+	Sortable: map[string]string{
+		"username":   "users.username",
+		"created_at": "users.created_at",
+	},
+	DefaultSort: "username",
+	Searchable:  []string{"users.username", "users.email", "users.full_name"},
+}
+
 // تابع جدید برای جستجوی کاربران
 func SearchUsers(c *gin.Context) {
-	query := c.Query("q")
-	var users []models.User
+	list, err := utils.ParseListQuery(c, userListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	if query != "" {
-		if err := config.DB.Where("username LIKE ? OR email LIKE ? OR full_name LIKE ?",
-			"%"+query+"%", "%"+query+"%", "%"+query+"%").
-			Find(&users).Error; err != nil {
-			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search users")
-			return
-		}
-	} else {
-		if err := config.DB.Limit(20).Find(&users).Error; err != nil {
-			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to load users")
-			return
-		}
+	var users []models.User
+	if err := list.Find(config.DB.Model(&models.User{}), &users); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search users")
+		return
 	}
 
 	// حذف اطلاعات حساس
@@ -209,5 +274,5 @@ func SearchUsers(c *gin.Context) {
 		users[i].Password = ""
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "OK", users)
+	utils.ListResponse(c, users, list)
 }
@@ -3,10 +3,13 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"task-manager/config"
 	"task-manager/models"
+	"task-manager/services"
 	"task-manager/utils"
 	"time"
 
@@ -20,10 +23,24 @@ type CreateGroupTaskRequest struct {
 	DueDate      *time.Time `json:"due_date"`
 	StartTime    *time.Time `json:"start_time"`
 	EndTime      *time.Time `json:"end_time"`
-	UserIDs      []uint     `json:"user_ids"`                  // کاربران خاصی، خالی برای تمام اعضا
+	UserIDs      []uint     `json:"user_ids"`                  // کاربران خاصی؛ همراه با team_ids خالی برای تمام اعضا
+	TeamIDs      []uint     `json:"team_ids"`                  // تیم‌هایی که تسک به اعضای فعلی و آینده‌شان اختصاص دارد
+	AssignAll    bool       `json:"assign_all"`                // اختصاص به تمام اعضای فعلی و آینده گروه
 	RequireFiles bool       `json:"require_files"`             // آیا فایل آپلود الزامی است
 	MaxFiles     int        `json:"max_files" binding:"min=1"` // حداکثر تعداد فایل
 	AllowTypes   string     `json:"allow_types"`               // نوع‌های مجاز: pdf,image,video
+
+	EstimateMinutes       *int `json:"estimate_minutes" binding:"omitempty,min=0"` // زمان تخمینی هر عضو
+	ProgressFromChecklist bool `json:"progress_from_checklist"`                    // پیشرفت هر عضو از چک‌لیست خودش
+
+	// Strategy - انتخاب خودکار assignee_count عضو (پیش‌فرض 1) از میان user_ids و team_ids یا تمام اعضا
+	Strategy      string `json:"strategy"`
+	AssigneeCount int    `json:"assignee_count" binding:"min=0"`
+}
+
+type TaskAssigneesRequest struct {
+	UserIDs   []uint `json:"user_ids"`
+	AssignAll bool   `json:"assign_all"` // فقط در جایگزینی؛ تمام اعضای فعلی و آینده گروه
 }
 
 type UpdateGroupTaskRequest struct {
@@ -35,6 +52,112 @@ type UpdateGroupTaskRequest struct {
 	RequireFiles bool       `json:"require_files"`
 	MaxFiles     int        `json:"max_files"`
 	AllowTypes   string     `json:"allow_types"`
+
+	EstimateMinutes       *int  `json:"estimate_minutes" binding:"omitempty,min=0"`
+	ProgressFromChecklist *bool `json:"progress_from_checklist"`
+}
+
+// loadGroupTask - تسک گروه برای اعضای دارای اجازه manage_tasks؛ در صورت خطا پاسخ نوشته می‌شود
+func loadGroupTask(c *gin.Context) (*models.Task, bool) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageTasks, "شما اجازه مدیریت تسک‌های این گروه را ندارید")
+	if !ok {
+		return nil, false
+	}
+	var task models.Task
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&task, c.Param("task_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "تسک پیدا نشد")
+		return nil, false
+	}
+	return &task, true
+}
+
+// respondGroupTask - بازگرداندن تسک گروهی همراه با اختصاص‌ها، پیشرفت و تیم‌ها
+func respondGroupTask(c *gin.Context, status int, message string, taskID uint) {
+	var task models.Task
+	config.DB.Preload("TaskAssignments.User").
+		Preload("GroupProgress").
+		Preload("Teams").
+		First(&task, taskID)
+	utils.SuccessResponse(c, status, message, task)
+}
+
+// rankAssignees - رتبه‌بندی اعضای کاندید برای اختصاص خودکار؛ user_ids و team_ids دامنه انتخاب را محدود می‌کنند
+// در صورت خطا پاسخ نوشته می‌شود
+func rankAssignees(c *gin.Context, groupID uint, strategy string, count int, userIDs, teamIDs []uint) ([]services.AssigneeCandidate, bool) {
+	if !services.IsValidStrategy(strategy) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "strategy باید یکی از round_robin، least_open_tasks، least_estimated_hours یا random باشد")
+		return nil, false
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	var pool []uint
+	if len(userIDs) > 0 || len(teamIDs) > 0 {
+		if _, ok := findGroupTeams(c, groupID, teamIDs); !ok {
+			return nil, false
+		}
+		teamMembers, err := services.TeamContributorIDs(config.DB, groupID, teamIDs)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت اعضای تیم‌ها")
+			return nil, false
+		}
+		pool = append(append([]uint{}, userIDs...), teamMembers...)
+	}
+
+	candidates, err := services.RankAssignees(config.DB, groupID, pool, strategy, count)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در محاسبه بار کاری اعضا")
+		return nil, false
+	}
+	return candidates, true
+}
+
+// PreviewGroupTaskAssignment - پیش‌نمایش اختصاص خودکار: بار کاری هر عضو، رتبه و دلیل انتخاب یا رد او
+// پارامترها: strategy، count، user_ids و team_ids (جداشده با کاما)
+func PreviewGroupTaskAssignment(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageTasks, "شما اجازه ایجاد تسک در این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	count, err := strconv.Atoi(c.DefaultQuery("count", "1"))
+	if err != nil || count < 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "count نامعتبر است")
+		return
+	}
+	userIDs, ok := queryIDList(c, "user_ids")
+	if !ok {
+		return
+	}
+	teamIDs, ok := queryIDList(c, "team_ids")
+	if !ok {
+		return
+	}
+
+	candidates, ok := rankAssignees(c, member.GroupID, c.Query("strategy"), count, userIDs, teamIDs)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", candidates)
+}
+
+// queryIDList - خواندن فهرست شناسه‌های جداشده با کاما از query؛ در صورت خطا پاسخ نوشته می‌شود
+func queryIDList(c *gin.Context, name string) ([]uint, bool) {
+	var ids []uint
+	for _, value := range strings.Split(c.Query(name), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, name+" نامعتبر است")
+			return nil, false
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, true
 }
 
 // CreateGroupTask - ایجاد تسک گروهی
@@ -48,10 +171,8 @@ func CreateGroupTask(c *gin.Context) {
 		return
 	}
 
-	// بررسی اینکه کاربر مدیر گروه است
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ? AND accepted = ?", groupID, userID, "admin", true).First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌تواند تسک ایجاد کنند")
+	// بررسی اجازه مدیریت تسک‌های گروه
+	if _, ok := authorizeGroup(c, StringToUint(groupID), models.PermManageTasks, "شما اجازه ایجاد تسک در این گروه را ندارید"); !ok {
 		return
 	}
 
@@ -85,71 +206,126 @@ func CreateGroupTask(c *gin.Context) {
 		RequireFiles: req.RequireFiles,
 		MaxFiles:     maxFiles,
 		AllowTypes:   req.AllowTypes,
+
+		EstimateMinutes:       req.EstimateMinutes,
+		ProgressFromChecklist: req.ProgressFromChecklist,
+		AssignAll:             req.AssignAll,
 	}
 
-	if err := config.DB.Create(&task).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ایجاد تسک")
+	// تیم‌های تسک باید متعلق به همین گروه باشند
+	teams, ok := findGroupTeams(c, groupIDUint, req.TeamIDs)
+	if !ok {
 		return
 	}
 
-	// دریافت اعضای گروه برای اختصاص تسک
-	var members []models.GroupMember
-	if len(req.UserIDs) > 0 {
-		config.DB.Where("group_id = ? AND user_id IN (?) AND accepted = ?", groupID, req.UserIDs, true).Find(&members)
+	var userIDs []uint
+	if req.Strategy != "" {
+		if req.AssignAll {
+			utils.ErrorResponse(c, http.StatusBadRequest, "assign_all همراه با strategy قابل استفاده نیست")
+			return
+		}
+		// با اختصاص خودکار تیم‌ها فقط دامنه انتخاب را محدود می‌کنند و به تسک متصل نمی‌شوند
+		candidates, ok := rankAssignees(c, groupIDUint, req.Strategy, req.AssigneeCount, req.UserIDs, req.TeamIDs)
+		if !ok {
+			return
+		}
+		for _, candidate := range candidates {
+			if candidate.Selected {
+				userIDs = append(userIDs, candidate.UserID)
+			}
+		}
+		if len(userIDs) == 0 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "هیچ عضوی برای اختصاص خودکار پیدا نشد")
+			return
+		}
+		teams = nil
 	} else {
-		config.DB.Where("group_id = ? AND accepted = ?", groupID, true).Find(&members)
+		// دریافت اعضای گروه برای اختصاص تسک؛ فقط نقش‌هایی که اجازه انجام تسک دارند
+		contributors, err := services.MembersWith(config.DB, groupIDUint, models.PermContribute)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت اعضای گروه")
+			return
+		}
+		var members []models.GroupMember
+		if len(req.UserIDs) > 0 && !req.AssignAll {
+			config.DB.Where("group_id = ? AND user_id IN (?) AND user_id IN (?)", groupID, req.UserIDs, contributors).Find(&members)
+		} else if len(teams) == 0 || req.AssignAll {
+			config.DB.Where("group_id = ? AND user_id IN (?)", groupID, contributors).Find(&members)
+		}
+		for _, groupMember := range members {
+			userIDs = append(userIDs, groupMember.UserID)
+		}
 	}
 
-	// اختصاص تسک به اعضا
-	for _, groupMember := range members {
-		config.DB.Create(&models.TaskAssignment{
-			TaskID: task.ID,
-			UserID: groupMember.UserID,
-		})
-
-		// ایجاد رکورد پیشرفت برای هر عضو
-		config.DB.Create(&models.GroupTaskProgress{
-			TaskID:     task.ID,
-			UserID:     groupMember.UserID,
-			AssignedBy: userID,
-		})
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+
+		// اختصاص مستقیم تسک به اعضا همراه با رکورد پیشرفت هر عضو
+		assignees, err := services.AssignTaskUsers(tx, &task, userIDs, userID, false)
+		if err != nil {
+			return err
+		}
+
+		details := map[string]interface{}{
+			"title":     task.Title,
+			"assignees": assignees,
+		}
+		if req.Strategy != "" {
+			details["strategy"] = req.Strategy
+		}
+		if err := services.LogTaskActivity(tx, &task, userID, models.ActivityTaskCreated, details); err != nil {
+			return err
+		}
+
+		// اعضای تیم‌ها از طریق تیم اختصاص می‌گیرند تا با تغییر اعضای تیم همگام بمانند
+		_, err = services.AssignTaskTeams(tx, &task, teams, userID)
+		return err
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ایجاد تسک")
+		return
 	}
 
 	// دریافت تسک کامل
-	var taskWithAssignments models.Task
-	config.DB.Preload("TaskAssignments.User").
-		Preload("GroupProgress").
-		First(&taskWithAssignments, task.ID)
-
-	utils.SuccessResponse(c, http.StatusCreated, "تسک گروهی با موفقیت ایجاد شد", taskWithAssignments)
+	respondGroupTask(c, http.StatusCreated, "تسک گروهی با موفقیت ایجاد شد", task.ID)
 }
 
 // GetGroupTasks - دریافت تمام تسک‌های گروهی
 func GetGroupTasks(c *gin.Context) {
-	userID := c.GetUint("userID")
 	groupID := c.Param("id")
 
 	// بررسی اینکه کاربر عضو گروه است
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND accepted = ?", groupID, userID, true).First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "شما عضو این گروه نیستید")
+	if _, ok := authorizeGroup(c, StringToUint(groupID), "", ""); !ok {
+		return
+	}
+
+	list, err := utils.ParseListQuery(c, taskListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	var tasks []models.Task
-	config.DB.Where("group_id = ?", groupID).
-		Preload("TaskAssignments", func(db *gorm.DB) *gorm.DB {
+	query := applyLabelFilter(c, config.DB.Model(&models.Task{}).Where("group_id = ?", groupID))
+	if err := list.Find(query, &tasks, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("TaskAssignments", func(db *gorm.DB) *gorm.DB {
 			return db.Preload("User")
 		}).
-		Preload("GroupProgress", func(db *gorm.DB) *gorm.DB {
-			return db.Preload("User")
-		}).
-		Preload("Creator").
-		Preload("Files").
-		Order("created_at DESC").
-		Find(&tasks)
+			Preload("GroupProgress", func(db *gorm.DB) *gorm.DB {
+				return db.Preload("User")
+			}).
+			Preload("Creator").
+			Preload("Files").
+			Preload("Labels").
+			Preload("Teams")
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت تسک‌ها")
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "OK", tasks)
+	utils.ListResponse(c, tasks, list)
 }
 
 // UpdateGroupTask - بروزرسانی تسک گروهی
@@ -170,10 +346,8 @@ func UpdateGroupTask(c *gin.Context) {
 		return
 	}
 
-	// بررسی اینکه کاربر مدیر گروه است
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ? AND accepted = ?", groupID, userID, "admin", true).First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌تواند تسک را بروزرسانی کنند")
+	// بررسی اجازه مدیریت تسک‌های گروه
+	if _, ok := authorizeGroup(c, StringToUint(groupID), models.PermManageTasks, "شما اجازه بروزرسانی تسک‌های این گروه را ندارید"); !ok {
 		return
 	}
 
@@ -189,6 +363,8 @@ func UpdateGroupTask(c *gin.Context) {
 		return
 	}
 
+	before := task
+
 	// بروزرسانی فیلدها
 	if req.Title != "" {
 		task.Title = req.Title
@@ -213,8 +389,38 @@ func UpdateGroupTask(c *gin.Context) {
 	if req.AllowTypes != "" {
 		task.AllowTypes = req.AllowTypes
 	}
+	if req.EstimateMinutes != nil {
+		task.EstimateMinutes = req.EstimateMinutes
+	}
+	if req.ProgressFromChecklist != nil {
+		task.ProgressFromChecklist = *req.ProgressFromChecklist
+	}
 
-	config.DB.Save(&task)
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
+		changes := services.DiffFields(before, task,
+			"Title", "Description", "DueDate", "StartTime", "EndTime", "RequireFiles", "MaxFiles", "AllowTypes", "EstimateMinutes", "ProgressFromChecklist")
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := services.LogTaskActivity(tx, &task, userID, models.ActivityTaskUpdated, changes); err != nil {
+			return err
+		}
+		if task.ProgressFromChecklist && !before.ProgressFromChecklist {
+			return services.SyncAllChecklistProgress(tx, &task, userID)
+		}
+		return nil
+	})
+	if errors.Is(err, services.ErrTaskBlocked) {
+		utils.ErrorResponse(c, http.StatusConflict, "این تسک منتظر تکمیل پیش‌نیازهایش است")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی تسک")
+		return
+	}
 
 	// دریافت تسک کامل
 	var updatedTask models.Task
@@ -237,10 +443,8 @@ func DeleteGroupTask(c *gin.Context) {
 		return
 	}
 
-	// بررسی اینکه کاربر مدیر گروه است
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ? AND accepted = ?", groupID, userID, "admin", true).First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌تواند تسک را حذف کنند")
+	// بررسی اجازه مدیریت تسک‌های گروه
+	if _, ok := authorizeGroup(c, StringToUint(groupID), models.PermManageTasks, "شما اجازه حذف تسک‌های این گروه را ندارید"); !ok {
 		return
 	}
 
@@ -256,10 +460,21 @@ func DeleteGroupTask(c *gin.Context) {
 		return
 	}
 
-	// حذف تسک و تمام وابستگی‌های آن
-	config.DB.Delete(&task)
+	// انتقال تسک به سطل زباله؛ داده‌های وابسته پس از پایان دوره نگهداری همراه آن پاک می‌شوند
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.TrashTask(tx, &task, services.SubtasksMove); err != nil {
+			return err
+		}
+		return services.LogTaskActivity(tx, &task, userID, models.ActivityTaskDeleted, map[string]interface{}{
+			"title": task.Title,
+		})
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف تسک")
+		return
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, "تسک با موفقیت حذف شد", nil)
+	utils.SuccessResponse(c, http.StatusOK, "تسک به سطل زباله منتقل شد", nil)
 }
 
 // UpdateTaskAssignment - بروزرسانی وضعیت انجام تسک توسط عضو
@@ -278,3 +493,97 @@ func UpdateTaskAssignment(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "وضعیت تسک بروزرسانی شد", assignment)
 }
+
+// assigneeError - تبدیل خطای مدیریت اعضای تسک به پاسخ مناسب
+func assigneeError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrNotAssignable) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "تسک فقط به اعضای پذیرفته‌شده گروه که اجازه انجام تسک دارند اختصاص داده می‌شود")
+		return
+	}
+	utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی اعضای تسک")
+}
+
+// AddGroupTaskAssignees - افزودن اعضا به تسک گروهی موجود
+func AddGroupTaskAssignees(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req TaskAssigneesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.UserIDs) == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "user_ids نمی‌تواند خالی باشد")
+		return
+	}
+
+	task, ok := loadGroupTask(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := services.AddTaskAssignees(tx, task, req.UserIDs, userID)
+		return err
+	})
+	if err != nil {
+		assigneeError(c, err)
+		return
+	}
+
+	respondGroupTask(c, http.StatusOK, "اعضا به تسک اضافه شدند", task.ID)
+}
+
+// ReplaceGroupTaskAssignees - جایگزینی کامل اعضای تسک گروهی؛ اعضای حذف‌شده بایگانی و اعضای جدید اضافه می‌شوند
+func ReplaceGroupTaskAssignees(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req TaskAssigneesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, ok := loadGroupTask(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		_, _, err := services.ReplaceTaskAssignees(tx, task, req.UserIDs, req.AssignAll, userID)
+		return err
+	})
+	if err != nil {
+		assigneeError(c, err)
+		return
+	}
+
+	respondGroupTask(c, http.StatusOK, "اعضای تسک بروزرسانی شدند", task.ID)
+}
+
+// RemoveGroupTaskAssignee - برداشتن یک عضو از تسک گروهی؛ پیشرفت او بایگانی می‌شود و با اختصاص دوباره برمی‌گردد
+func RemoveGroupTaskAssignee(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	task, ok := loadGroupTask(c)
+	if !ok {
+		return
+	}
+
+	var removed []uint
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = services.RemoveTaskAssignees(tx, task, []uint{StringToUint(c.Param("user_id"))}, userID)
+		return err
+	})
+	if err != nil {
+		assigneeError(c, err)
+		return
+	}
+	if len(removed) == 0 {
+		utils.ErrorResponse(c, http.StatusNotFound, "این کاربر عضو تسک نیست")
+		return
+	}
+
+	respondGroupTask(c, http.StatusOK, "عضو از تسک برداشته شد", task.ID)
+}
@@ -0,0 +1,114 @@
+// backend/controllers/invitation_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// loadGroupInvitation - دعوت گروه برای اعضای دارای اجازه manage_members همراه با عضویت درخواست‌کننده؛ در صورت خطا پاسخ نوشته می‌شود
+func loadGroupInvitation(c *gin.Context) (*models.GroupMember, *models.GroupInvitation, bool) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه مدیریت دعوت‌های این گروه را ندارید")
+	if !ok {
+		return nil, nil, false
+	}
+	var invitation models.GroupInvitation
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&invitation, c.Param("invitation_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "دعوت پیدا نشد")
+		return nil, nil, false
+	}
+	return member, &invitation, true
+}
+
+// invitationError - تبدیل خطای چرخه دعوت به پاسخ مناسب
+func invitationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvitationClosed):
+		utils.ErrorResponse(c, http.StatusConflict, "این دعوت دیگر در انتظار پاسخ نیست")
+	case errors.Is(err, services.ErrInvitationPending):
+		utils.ErrorResponse(c, http.StatusConflict, "این کاربر دعوت در انتظار دیگری دارد")
+	case errors.Is(err, services.ErrAlreadyMember):
+		utils.ErrorResponse(c, http.StatusConflict, "این کاربر عضو گروه است")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در پردازش دعوت")
+	}
+}
+
+// GetGroupInvitations - تاریخچه دعوت‌های گروه؛ با status قابل فیلتر است
+func GetGroupInvitations(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه مدیریت دعوت‌های این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	query := config.DB.Where("group_id = ?", member.GroupID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var invitations []models.GroupInvitation
+	if err := query.Preload("User").Preload("Inviter").
+		Order("created_at DESC, id DESC").
+		Find(&invitations).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت دعوت‌ها")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", invitations)
+}
+
+// RevokeGroupInvitation - لغو دعوت در انتظار
+func RevokeGroupInvitation(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	_, invitation, ok := loadGroupInvitation(c)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.RevokeInvitation(tx, invitation, userID)
+	}); err != nil {
+		invitationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "دعوت لغو شد", invitation)
+}
+
+// ResendGroupInvitation - ارسال دوباره دعوت در انتظار، ردشده، لغوشده یا منقضی‌شده
+func ResendGroupInvitation(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	member, invitation, ok := loadGroupInvitation(c)
+	if !ok {
+		return
+	}
+	// نقش ذخیره‌شده در دعوت ممکن است بالاتر از نقشی باشد که درخواست‌کننده اجازه دادنش را دارد
+	if !canGrantRoles(c, member, invitation.Role) {
+		return
+	}
+
+	var resent *models.GroupInvitation
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		resent, err = services.ResendInvitation(tx, invitation, userID)
+		return err
+	}); err != nil {
+		invitationError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "دعوت دوباره ارسال شد", resent)
+}
@@ -0,0 +1,187 @@
+// backend/controllers/invite_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CreateInviteRequest struct {
+	Role      string     `json:"role"`                     // پیش‌فرض member
+	MaxUses   int        `json:"max_uses" binding:"min=0"` // صفر برای بدون محدودیت، یک برای یک‌بار مصرف
+	ExpiresIn string     `json:"expires_in"`               // مثلا 24h یا 7d
+	ExpiresAt *time.Time `json:"expires_at"`               // به جای expires_in
+}
+
+// loadGroupInvite - لینک دعوت گروه برای اعضای دارای اجازه manage_members؛ در صورت خطا پاسخ نوشته می‌شود
+func loadGroupInvite(c *gin.Context) (*models.GroupInvite, bool) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه مدیریت لینک‌های دعوت این گروه را ندارید")
+	if !ok {
+		return nil, false
+	}
+	var invite models.GroupInvite
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&invite, c.Param("invite_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "لینک دعوت پیدا نشد")
+		return nil, false
+	}
+	return &invite, true
+}
+
+// GetGroupInvites - لینک‌های دعوت گروه، از جمله باطل‌شده و منقضی‌شده
+func GetGroupInvites(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه مدیریت لینک‌های دعوت این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	var invites []models.GroupInvite
+	if err := config.DB.Where("group_id = ?", member.GroupID).
+		Preload("Creator").
+		Order("created_at DESC").
+		Find(&invites).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت لینک‌های دعوت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", invites)
+}
+
+// CreateGroupInvite - ساخت لینک دعوت امضاشده و دارای تاریخ انقضا
+func CreateGroupInvite(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه ساخت لینک دعوت برای این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	if role == "" {
+		role = models.RoleMember
+	}
+	valid, err := services.IsValidRole(config.DB, member.GroupID, role)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ساخت لینک دعوت")
+		return
+	}
+	if !valid {
+		utils.ErrorResponse(c, http.StatusBadRequest, "نقش نامعتبر است")
+		return
+	}
+	if !canGrantRoles(c, member, role) {
+		return
+	}
+
+	expiresAt := time.Now().Add(services.InviteLinkTTL())
+	switch {
+	case req.ExpiresAt != nil:
+		expiresAt = *req.ExpiresAt
+	case req.ExpiresIn != "":
+		ttl, err := utils.ParseFriendlyDuration(req.ExpiresIn)
+		if err != nil || ttl == 0 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "expires_in نامعتبر است")
+			return
+		}
+		expiresAt = time.Now().Add(ttl)
+	}
+	if !expiresAt.After(time.Now()) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "زمان انقضا باید در آینده باشد")
+		return
+	}
+
+	invite := models.GroupInvite{
+		GroupID:   member.GroupID,
+		Role:      role,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: expiresAt,
+		CreatedBy: userID,
+	}
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.CreateInvite(tx, &invite)
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ساخت لینک دعوت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "لینک دعوت ساخته شد", invite)
+}
+
+// RevokeGroupInvite - باطل کردن لینک دعوت
+func RevokeGroupInvite(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	invite, ok := loadGroupInvite(c)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.RevokeInvite(tx, invite, userID)
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در باطل کردن لینک دعوت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "لینک دعوت باطل شد", invite)
+}
+
+// GetGroupInviteUses - کاربرانی که با یک لینک دعوت عضو گروه شده‌اند
+func GetGroupInviteUses(c *gin.Context) {
+	invite, ok := loadGroupInvite(c)
+	if !ok {
+		return
+	}
+
+	var uses []models.GroupInviteUse
+	if err := config.DB.Where("invite_id = ?", invite.ID).
+		Preload("User").
+		Order("created_at ASC").
+		Find(&uses).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت استفاده‌های لینک دعوت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", uses)
+}
+
+// JoinGroupByCode - عضویت در گروه با کد لینک دعوت
+func JoinGroupByCode(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var member *models.GroupMember
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		member, err = services.JoinByCode(tx, c.Param("code"), userID)
+		return err
+	})
+	switch {
+	case errors.Is(err, services.ErrInviteInvalid):
+		utils.ErrorResponse(c, http.StatusNotFound, "لینک دعوت نامعتبر است")
+	case errors.Is(err, services.ErrInviteExpired), errors.Is(err, services.ErrInviteRevoked), errors.Is(err, services.ErrInviteExhausted):
+		utils.ErrorResponse(c, http.StatusGone, "این لینک دعوت دیگر معتبر نیست")
+	case errors.Is(err, services.ErrAlreadyMember):
+		utils.ErrorResponse(c, http.StatusConflict, "شما قبلاً عضو این گروه هستید")
+	case errors.Is(err, services.ErrGroupArchived):
+		utils.ErrorResponse(c, http.StatusForbidden, "این گروه بایگانی شده و فقط خواندنی است")
+	case err != nil:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در عضویت در گروه")
+	default:
+		utils.SuccessResponse(c, http.StatusOK, "شما به گروه پیوستید", member)
+	}
+}
@@ -0,0 +1,185 @@
+// backend/controllers/join_request_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type JoinRequestRequest struct {
+	Message string `json:"message" binding:"max=1000"`
+}
+
+// JoinRequestResult نتیجه درخواست عضویت؛ در گروه open عضویت فوری و در گروه discoverable درخواست ثبت می‌شود
+type JoinRequestResult struct {
+	Request *models.GroupJoinRequest `json:"request,omitempty"`
+	Member  *models.GroupMember      `json:"member,omitempty"`
+}
+
+// joinRequestError - تبدیل خطای درخواست عضویت به پاسخ مناسب
+func joinRequestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrGroupNotJoinable):
+		utils.ErrorResponse(c, http.StatusNotFound, "گروه پیدا نشد")
+	case errors.Is(err, services.ErrAlreadyMember):
+		utils.ErrorResponse(c, http.StatusConflict, "این کاربر عضو گروه است")
+	case errors.Is(err, services.ErrHasPendingInvite):
+		utils.ErrorResponse(c, http.StatusConflict, "شما یک دعوت در انتظار برای این گروه دارید")
+	case errors.Is(err, services.ErrJoinRequestPending):
+		utils.ErrorResponse(c, http.StatusConflict, "درخواست عضویت شما در انتظار بررسی است")
+	case errors.Is(err, services.ErrJoinRequestClosed):
+		utils.ErrorResponse(c, http.StatusConflict, "این درخواست دیگر در انتظار بررسی نیست")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در پردازش درخواست عضویت")
+	}
+}
+
+// loadJoinRequest - درخواست عضویت گروه برای اعضای دارای اجازه manage_members؛ در صورت خطا پاسخ نوشته می‌شود
+func loadJoinRequest(c *gin.Context) (*models.GroupJoinRequest, bool) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه بررسی درخواست‌های عضویت این گروه را ندارید")
+	if !ok {
+		return nil, false
+	}
+	var request models.GroupJoinRequest
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&request, c.Param("request_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "درخواست عضویت پیدا نشد")
+		return nil, false
+	}
+	return &request, true
+}
+
+// CreateJoinRequest - درخواست عضویت در گروه discoverable یا پیوستن فوری به گروه open
+func CreateJoinRequest(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req JoinRequestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	var result JoinRequestResult
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result.Request, result.Member, err = services.RequestToJoin(tx, StringToUint(c.Param("id")), userID, req.Message)
+		return err
+	})
+	if err != nil {
+		joinRequestError(c, err)
+		return
+	}
+
+	if result.Member != nil {
+		utils.SuccessResponse(c, http.StatusOK, "شما به گروه پیوستید", result)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusCreated, "درخواست عضویت ثبت شد", result)
+}
+
+// CancelJoinRequest - لغو درخواست عضویت در انتظار توسط خود کاربر
+func CancelJoinRequest(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.CancelJoinRequest(tx, StringToUint(c.Param("id")), userID)
+	})
+	if err != nil {
+		joinRequestError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "درخواست عضویت لغو شد", nil)
+}
+
+// GetGroupJoinRequests - درخواست‌های عضویت گروه؛ پیش‌فرض فقط درخواست‌های در انتظار
+func GetGroupJoinRequests(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه بررسی درخواست‌های عضویت این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	status := c.DefaultQuery("status", models.JoinRequestPending)
+	query := config.DB.Where("group_id = ?", member.GroupID)
+	if status != "all" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []models.GroupJoinRequest
+	if err := query.Preload("User").Preload("Reviewer").
+		Order("created_at DESC, id DESC").
+		Find(&requests).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت درخواست‌های عضویت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", requests)
+}
+
+// GetMyJoinRequests - درخواست‌های عضویت کاربر در گروه‌های دیگر
+func GetMyJoinRequests(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var requests []models.GroupJoinRequest
+	if err := config.DB.Where("user_id = ?", userID).
+		Preload("Group").
+		Order("created_at DESC, id DESC").
+		Find(&requests).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت درخواست‌های عضویت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", requests)
+}
+
+// ApproveJoinRequest - تایید درخواست عضویت
+func ApproveJoinRequest(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	request, ok := loadJoinRequest(c)
+	if !ok {
+		return
+	}
+
+	var member *models.GroupMember
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		member, err = services.ApproveJoinRequest(tx, request, userID)
+		return err
+	})
+	if err != nil {
+		joinRequestError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "درخواست عضویت تایید شد", member)
+}
+
+// DenyJoinRequest - رد درخواست عضویت
+func DenyJoinRequest(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	request, ok := loadJoinRequest(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.DenyJoinRequest(tx, request, userID)
+	})
+	if err != nil {
+		joinRequestError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "درخواست عضویت رد شد", request)
+}
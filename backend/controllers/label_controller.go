@@ -0,0 +1,329 @@
+// backend/controllers/label_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Label filter modes for task listings
+const (
+	LabelModeAnd = "and"
+	LabelModeOr  = "or"
+)
+
+type CreateLabelRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Color   string `json:"color" binding:"omitempty,hexcolor"`
+	GroupID *uint  `json:"group_id"` // خالی برای برچسب شخصی
+}
+
+type UpdateLabelRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+type TaskLabelsRequest struct {
+	LabelIDs []uint `json:"label_ids" binding:"required"`
+}
+
+// acceptedGroupIDs - زیرکوئری شناسه گروه‌هایی که کاربر عضو پذیرفته‌شده آن‌هاست
+func acceptedGroupIDs(userID uint) *gorm.DB {
+	return config.DB.Model(&models.GroupMember{}).
+		Select("group_id").
+		Where("user_id = ? AND accepted = ?", userID, true)
+}
+
+// visibleLabels - برچسب‌های شخصی کاربر و برچسب‌های گروه‌های او
+func visibleLabels(userID uint) *gorm.DB {
+	return config.DB.Model(&models.Label{}).
+		Where("user_id = ? OR group_id IN (?)", userID, acceptedGroupIDs(userID))
+}
+
+// canManageLabel - برچسب شخصی توسط صاحبش و برچسب گروهی توسط اعضای دارای اجازه edit_settings مدیریت می‌شود
+func canManageLabel(userID uint, label *models.Label) bool {
+	if label.GroupID == nil {
+		return label.UserID != nil && *label.UserID == userID
+	}
+	return hasGroupPermission(userID, *label.GroupID, models.PermEditSettings)
+}
+
+// labelFitsTask - برچسب شخصی فقط روی تسک شخصی صاحبش و برچسب گروهی فقط روی تسک‌های همان گروه
+func labelFitsTask(label *models.Label, task *models.Task) bool {
+	if label.GroupID != nil {
+		return task.IsGroupTask && task.GroupID != nil && *task.GroupID == *label.GroupID
+	}
+	return !task.IsGroupTask && label.UserID != nil && *label.UserID == task.CreatorID
+}
+
+// applyLabelFilter - فیلتر تسک‌ها بر اساس پارامترهای label و label_mode
+// هر مقدار label یا شناسه برچسب است یا نام آن؛ نام با تمام برچسب‌های قابل مشاهده هم‌نام تطبیق داده می‌شود
+func applyLabelFilter(c *gin.Context, db *gorm.DB) *gorm.DB {
+	raw := c.Query("label")
+	if raw == "" {
+		return db
+	}
+	userID := c.GetUint("userID")
+	mode := strings.ToLower(c.DefaultQuery("label_mode", LabelModeAnd))
+
+	var terms [][]uint
+	for _, value := range strings.Split(raw, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		var ids []uint
+		if id, err := strconv.ParseUint(value, 10, 32); err == nil {
+			visibleLabels(userID).Where("id = ?", id).Pluck("id", &ids)
+		} else {
+			visibleLabels(userID).Where("name = ?", value).Pluck("id", &ids)
+		}
+		terms = append(terms, ids)
+	}
+
+	taskIDsWith := func(labelIDs []uint) *gorm.DB {
+		return config.DB.Table("task_labels").Select("task_id").Where("label_id IN ?", labelIDs)
+	}
+
+	if mode == LabelModeOr {
+		var all []uint
+		for _, ids := range terms {
+			all = append(all, ids...)
+		}
+		if len(all) == 0 {
+			return db.Where("1 = 0")
+		}
+		return db.Where("tasks.id IN (?)", taskIDsWith(all))
+	}
+
+	for _, ids := range terms {
+		if len(ids) == 0 {
+			return db.Where("1 = 0")
+		}
+		db = db.Where("tasks.id IN (?)", taskIDsWith(ids))
+	}
+	return db
+}
+
+// GetLabels - دریافت برچسب‌های قابل استفاده برای کاربر
+func GetLabels(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	query := visibleLabels(userID)
+	if groupID := c.Query("group_id"); groupID != "" {
+		query = query.Where("group_id = ?", groupID)
+	}
+
+	var labels []models.Label
+	if err := query.Order("name ASC").Find(&labels).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت برچسب‌ها")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", labels)
+}
+
+// labelNameTaken - نام برچسب در هر محدوده (شخصی یا گروه) یکتاست؛ خود برچسب در ویرایش حساب نمی‌شود
+func labelNameTaken(label *models.Label) bool {
+	var count int64
+	scope := config.DB.Model(&models.Label{}).Where("name = ? AND id <> ?", label.Name, label.ID)
+	if label.GroupID != nil {
+		scope = scope.Where("group_id = ?", *label.GroupID)
+	} else {
+		scope = scope.Where("user_id = ?", *label.UserID)
+	}
+	scope.Count(&count)
+	return count > 0
+}
+
+// CreateLabel - ایجاد برچسب شخصی یا گروهی
+func CreateLabel(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req CreateLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	label := models.Label{
+		Name:      strings.TrimSpace(req.Name),
+		Color:     req.Color,
+		GroupID:   req.GroupID,
+		CreatedBy: userID,
+	}
+	if label.GroupID == nil {
+		label.UserID = &userID
+	}
+	if label.Color == "" {
+		label.Color = "#808080"
+	}
+
+	if !canManageLabel(userID, &label) {
+		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه می‌توانند برچسب گروهی ایجاد کنند")
+		return
+	}
+	// گروه در بدنه درخواست است و ArchivedGroupGuard آن را نمی‌بیند
+	if label.GroupID != nil {
+		if err := services.EnsureGroupWritable(config.DB, *label.GroupID); err != nil {
+			if errors.Is(err, services.ErrGroupArchived) {
+				utils.ErrorResponse(c, http.StatusForbidden, "این گروه بایگانی شده و فقط خواندنی است")
+			} else {
+				utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ایجاد برچسب")
+			}
+			return
+		}
+	}
+
+	if labelNameTaken(&label) {
+		utils.ErrorResponse(c, http.StatusConflict, "برچسبی با این نام وجود دارد")
+		return
+	}
+
+	if err := config.DB.Create(&label).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ایجاد برچسب")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "برچسب با موفقیت ایجاد شد", label)
+}
+
+// UpdateLabel - بروزرسانی نام یا رنگ برچسب
+func UpdateLabel(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req UpdateLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var label models.Label
+	if err := visibleLabels(userID).First(&label, c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "برچسب پیدا نشد")
+		return
+	}
+	if !canManageLabel(userID, &label) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه ویرایش این برچسب را ندارید")
+		return
+	}
+
+	if req.Name != "" {
+		label.Name = strings.TrimSpace(req.Name)
+		if labelNameTaken(&label) {
+			utils.ErrorResponse(c, http.StatusConflict, "برچسبی با این نام وجود دارد")
+			return
+		}
+	}
+	if req.Color != "" {
+		label.Color = req.Color
+	}
+
+	if err := config.DB.Save(&label).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی برچسب")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "برچسب با موفقیت بروزرسانی شد", label)
+}
+
+// DeleteLabel - حذف برچسب و جدا کردن آن از تمام تسک‌ها
+func DeleteLabel(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var label models.Label
+	if err := visibleLabels(userID).First(&label, c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "برچسب پیدا نشد")
+		return
+	}
+	if !canManageLabel(userID, &label) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه حذف این برچسب را ندارید")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM task_labels WHERE label_id = ?", label.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&label).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف برچسب")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "برچسب با موفقیت حذف شد", nil)
+}
+
+// AddTaskLabels - افزودن برچسب به تسک
+func AddTaskLabels(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req TaskLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if !canManageTask(userID, task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه تغییر برچسب‌های این تسک را ندارید")
+		return
+	}
+
+	// شناسه تکراری در درخواست نباید به خطای «پیدا نشد» برسد
+	labelIDs := uniqueIDs(req.LabelIDs)
+	var labels []models.Label
+	visibleLabels(userID).Where("id IN ?", labelIDs).Find(&labels)
+	if len(labels) != len(labelIDs) {
+		utils.ErrorResponse(c, http.StatusNotFound, "برخی برچسب‌ها پیدا نشدند")
+		return
+	}
+	for i := range labels {
+		if !labelFitsTask(&labels[i], task) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "برچسب "+labels[i].Name+" برای این تسک قابل استفاده نیست")
+			return
+		}
+	}
+
+	if err := config.DB.Model(task).Association("Labels").Append(labels); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در افزودن برچسب")
+		return
+	}
+
+	config.DB.Model(task).Association("Labels").Find(&task.Labels)
+	utils.SuccessResponse(c, http.StatusOK, "برچسب‌ها با موفقیت اضافه شدند", task)
+}
+
+// RemoveTaskLabel - حذف برچسب از تسک
+func RemoveTaskLabel(c *gin.Context) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if !canManageTask(c.GetUint("userID"), task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه تغییر برچسب‌های این تسک را ندارید")
+		return
+	}
+
+	label := models.Label{ID: StringToUint(c.Param("label_id"))}
+	if err := config.DB.Model(task).Association("Labels").Delete(&label); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف برچسب")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "برچسب از تسک حذف شد", nil)
+}
@@ -11,19 +11,41 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// GetNotifications - دریافت تمام اعلان‌های کاربر
+var notificationListSpec = utils.ListSpec{
+	Table: "notifications",
+	Sortable: map[string]string{
+		"created_at": "notifications.created_at",
+		"type":       "notifications.type",
+	},
+	DefaultSort: "-created_at",
+	Searchable:  []string{"notifications.title", "notifications.message"},
+	Filters: map[string]string{
+		"type": "notifications.type",
+	},
+}
+
+// GetNotifications - دریافت اعلان‌های کاربر؛ unread=true فقط خوانده‌نشده‌ها
 func GetNotifications(c *gin.Context) {
 	userID := c.GetUint("userID")
 
+	list, err := utils.ParseListQuery(c, notificationListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := config.DB.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if c.Query("unread") == "true" {
+		query = query.Where("is_read = ?", false)
+	}
+
 	var notifications []models.Notification
-	if err := config.DB.Where("user_id = ?", userID).
-		Order("created_at DESC").
-		Find(&notifications).Error; err != nil {
+	if err := list.Find(query, &notifications); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت اعلان‌ها")
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "OK", notifications)
+	utils.ListResponse(c, notifications, list)
 }
 
 // MarkAsRead - علامت‌گذاری اعلان به عنوان خوانده شده
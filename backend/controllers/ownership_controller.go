@@ -0,0 +1,132 @@
+// backend/controllers/ownership_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TransferOwnershipRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+// membershipError - تبدیل خطای عضویت و مالکیت به پاسخ مناسب
+func membershipError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrOwnerMustTransfer):
+		utils.ErrorResponse(c, http.StatusConflict, "مالک گروه ابتدا باید مالکیت را به عضو دیگری منتقل کند")
+	case errors.Is(err, services.ErrLastAdmin):
+		utils.ErrorResponse(c, http.StatusConflict, "آخرین مدیر گروه ابتدا باید مدیر دیگری تعیین کند")
+	case errors.Is(err, services.ErrNotGroupMember):
+		utils.ErrorResponse(c, http.StatusNotFound, "عضو پذیرفته‌شده‌ای با این شناسه در گروه پیدا نشد")
+	case errors.Is(err, services.ErrNoPendingTransfer):
+		utils.ErrorResponse(c, http.StatusNotFound, "پیشنهاد انتقال مالکیت فعالی پیدا نشد")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetOwnershipTransfer - پیشنهاد انتقال مالکیت در انتظار گروه (یا null)
+func GetOwnershipTransfer(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+	if !ok {
+		return
+	}
+
+	var transfer models.OwnershipTransfer
+	err := config.DB.Where("group_id = ? AND status = ?", member.GroupID, models.TransferPending).
+		Preload("FromUser").Preload("ToUser").
+		First(&transfer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		utils.SuccessResponse(c, http.StatusOK, "OK", nil)
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت پیشنهاد انتقال مالکیت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", transfer)
+}
+
+// TransferOwnership - پیشنهاد انتقال مالکیت توسط مالک؛ تا پذیرش عضو مقصد چیزی تغییر نمی‌کند
+func TransferOwnership(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+	if !ok {
+		return
+	}
+	if member.Role != models.RoleOwner {
+		utils.ErrorResponse(c, http.StatusForbidden, "فقط مالک گروه می‌تواند مالکیت را منتقل کند")
+		return
+	}
+	if req.UserID == userID {
+		utils.ErrorResponse(c, http.StatusBadRequest, "شما در حال حاضر مالک گروه هستید")
+		return
+	}
+
+	var transfer *models.OwnershipTransfer
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transfer, err = services.OfferOwnership(tx, member.GroupID, userID, req.UserID)
+		return err
+	})
+	if err != nil {
+		membershipError(c, err, "خطا در ثبت پیشنهاد انتقال مالکیت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "پیشنهاد انتقال مالکیت ارسال شد", transfer)
+}
+
+// CancelOwnershipTransfer - لغو پیشنهاد انتقال مالکیت توسط مالک
+func CancelOwnershipTransfer(c *gin.Context) {
+	respondOwnershipTransfer(c, services.CancelOwnership, "پیشنهاد انتقال مالکیت لغو شد")
+}
+
+// AcceptOwnershipTransfer - پذیرش مالکیت گروه توسط عضو مقصد
+func AcceptOwnershipTransfer(c *gin.Context) {
+	respondOwnershipTransfer(c, services.AcceptOwnership, "شما مالک گروه شدید")
+}
+
+// DeclineOwnershipTransfer - رد پیشنهاد مالکیت توسط عضو مقصد
+func DeclineOwnershipTransfer(c *gin.Context) {
+	respondOwnershipTransfer(c, services.DeclineOwnership, "پیشنهاد انتقال مالکیت رد شد")
+}
+
+func respondOwnershipTransfer(c *gin.Context, action func(*gorm.DB, uint, uint) (*models.OwnershipTransfer, error), message string) {
+	userID := c.GetUint("userID")
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+	if !ok {
+		return
+	}
+
+	var transfer *models.OwnershipTransfer
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transfer, err = action(tx, member.GroupID, userID)
+		return err
+	})
+	if err != nil {
+		membershipError(c, err, "خطا در پردازش انتقال مالکیت")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, message, transfer)
+}
@@ -3,13 +3,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"task-manager/config"
 	"task-manager/models"
+	"task-manager/services"
 	"task-manager/utils"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type UpdateProgressRequest struct {
@@ -30,6 +33,31 @@ type UpdateGroupProgressByMemberRequest struct {
 	Notes    string `json:"notes"`
 }
 
+// ensureUnblocked - شروع کار روی تسکی که پیش‌نیاز تکمیل‌نشده دارد مجاز نیست
+func ensureUnblocked(c *gin.Context, taskID uint, progress int) bool {
+	if progress == 0 {
+		return true
+	}
+	if err := services.EnsureUnblocked(config.DB, taskID); err != nil {
+		if errors.Is(err, services.ErrTaskBlocked) {
+			utils.ErrorResponse(c, http.StatusConflict, "این تسک منتظر تکمیل پیش‌نیازهایش است")
+		} else {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بررسی وابستگی‌ها")
+		}
+		return false
+	}
+	return true
+}
+
+// manualProgressAllowed - پیشرفت تسکی که از روی چک‌لیست محاسبه می‌شود دستی تغییر نمی‌کند
+func manualProgressAllowed(c *gin.Context, task *models.Task) bool {
+	if task.ProgressFromChecklist {
+		utils.ErrorResponse(c, http.StatusBadRequest, "پیشرفت این تسک از روی چک‌لیست محاسبه می‌شود")
+		return false
+	}
+	return true
+}
+
 // UpdatePersonalProgress - بروزرسانی پیشرفت تسک شخصی
 func UpdatePersonalProgress(c *gin.Context) {
 	userID := c.GetUint("userID")
@@ -48,33 +76,83 @@ func UpdatePersonalProgress(c *gin.Context) {
 		return
 	}
 
-	// یافتن یا ایجاد رکورد پیشرفت
+	// پیشرفت تسک دارای زیرتسک از روی زیرتسک‌ها محاسبه می‌شود
+	if total, _, _ := services.CountSubtasks(config.DB, task.ID); total > 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "پیشرفت این تسک از روی زیرتسک‌ها محاسبه می‌شود")
+		return
+	}
+
+	if !manualProgressAllowed(c, &task) {
+		return
+	}
+
+	if !ensureUnblocked(c, task.ID, req.Progress) {
+		return
+	}
+
 	var progress models.TaskProgress
-	if err := config.DB.Where("task_id = ? AND user_id = ?", taskID, userID).First(&progress).Error; err != nil {
-		progress = models.TaskProgress{
-			TaskID:   StringToUint(taskID),
-			UserID:   userID,
-			Progress: req.Progress,
-			Notes:    req.Notes,
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// یافتن یا ایجاد رکورد پیشرفت
+		previous := 0
+		if err := tx.Where("task_id = ? AND user_id = ?", taskID, userID).First(&progress).Error; err != nil {
+			progress = models.TaskProgress{
+				TaskID: StringToUint(taskID),
+				UserID: userID,
+			}
+		} else {
+			previous = progress.Progress
 		}
-		config.DB.Create(&progress)
-	} else {
 		progress.Progress = req.Progress
 		progress.Notes = req.Notes
-		config.DB.Save(&progress)
-	}
+		// تکمیل پیشرفت همراه با همین ذخیره ثبت می‌شود
+		if req.Progress == 100 {
+			progress.IsCompleted = true
+			now := time.Now()
+			progress.CompletedAt = &now
+		}
+		if err := tx.Save(&progress).Error; err != nil {
+			return err
+		}
+		if err := services.LogTaskActivity(tx, &task, userID, models.ActivityProgressUpdated, map[string]interface{}{
+			"user_id": userID,
+			"from":    previous,
+			"to":      req.Progress,
+		}); err != nil {
+			return err
+		}
+
+		oldStatus := task.Status
+		// بررسی تکمیل شدن تسک
+		if req.Progress == 100 {
+			wasCompleted := task.Status == models.StatusCompleted
+			// آپدیت وضعیت تسک
+			task.Status = models.StatusCompleted
+			if err := tx.Save(&task).Error; err != nil {
+				return err
+			}
+
+			// ساخت رخداد بعدی برای تسک‌های تکرارشونده
+			if !wasCompleted {
+				if _, err := services.GenerateNextOccurrence(tx, &task); err != nil {
+					return err
+				}
+			}
+		} else if req.Progress > 0 {
+			task.Status = models.StatusInProgress
+			if err := tx.Save(&task).Error; err != nil {
+				return err
+			}
+		}
+		if err := services.LogStatusChange(tx, &task, userID, oldStatus, task.Status); err != nil {
+			return err
+		}
 
-	// بررسی تکمیل شدن تسک
-	if req.Progress == 100 {
-		progress.IsCompleted = true
-		now := time.Now()
-		progress.CompletedAt = &now
-		// آپدیت وضعیت تسک
-		task.Status = models.StatusCompleted
-		config.DB.Save(&task)
-	} else if req.Progress > 0 {
-		task.Status = models.StatusInProgress
-		config.DB.Save(&task)
+		// بروزرسانی پیشرفت والد
+		return services.SyncParentProgress(tx, task.ParentID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی پیشرفت")
+		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "پیشرفت با موفقیت بروزرسانی شد", progress)
@@ -98,61 +176,73 @@ func UpdateGroupMemberProgress(c *gin.Context) {
 		return
 	}
 
-	// بررسی اینکه کاربر مدیر گروه است
-	var member models.GroupMember
-	if err := config.DB.Where("group_id = ? AND user_id = ? AND role = ?", task.GroupID, userID, "admin").First(&member).Error; err != nil {
-		utils.ErrorResponse(c, http.StatusForbidden, "فقط مدیران گروه میتوانند پیشرفت را بروزرسانی کنند")
+	// بررسی اجازه تایید پیشرفت اعضا
+	if task.GroupID == nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "این تسک گروهی نیست")
+		return
+	}
+	if _, ok := authorizeGroup(c, *task.GroupID, models.PermApproveFiles, "شما اجازه بروزرسانی پیشرفت اعضا را ندارید"); !ok {
 		return
 	}
 
-	// یافتن یا ایجاد رکورد پیشرفت
-	var progress models.GroupTaskProgress
-	if err := config.DB.Where("task_id = ? AND user_id = ?", taskID, req.UserID).First(&progress).Error; err != nil {
-		progress = models.GroupTaskProgress{
-			TaskID:     StringToUint(taskID),
-			UserID:     req.UserID,
-			AssignedBy: userID,
-			Progress:   req.Progress,
-			Notes:      req.Notes,
-			Approved:   req.Approved,
-		}
-		config.DB.Create(&progress)
-	} else {
-		progress.Progress = req.Progress
-		progress.Notes = req.Notes
-		progress.AssignedBy = userID
-		if req.Approved && !progress.Approved {
-			now := time.Now()
-			progress.Approved = true
-			progress.ApprovedBy = &userID
-			progress.ApprovedAt = &now
-		}
-		config.DB.Save(&progress)
+	if !manualProgressAllowed(c, &task) {
+		return
 	}
 
-	// بررسی تکمیل شدن
-	if req.Progress == 100 {
-		progress.IsCompleted = true
-		now := time.Now()
-		progress.CompletedAt = &now
-		config.DB.Save(&progress)
+	if !ensureUnblocked(c, task.ID, req.Progress) {
+		return
 	}
 
-	// بررسی اینکه آیا همه اعضا تکمیل کردهاند
-	var allProgress []models.GroupTaskProgress
-	config.DB.Where("task_id = ?", taskID).Find(&allProgress)
+	var progress models.GroupTaskProgress
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// یافتن یا ایجاد رکورد پیشرفت
+		previous := 0
+		if err := tx.Where("task_id = ? AND user_id = ?", taskID, req.UserID).First(&progress).Error; err != nil {
+			progress = models.GroupTaskProgress{
+				TaskID:     StringToUint(taskID),
+				UserID:     req.UserID,
+				AssignedBy: userID,
+				Notes:      req.Notes,
+			}
+		} else {
+			previous = progress.Progress
+			progress.Notes = req.Notes
+			progress.AssignedBy = userID
+		}
+		services.ApplyMemberProgress(&progress, req.Progress)
+		if err := tx.Save(&progress).Error; err != nil {
+			return err
+		}
+		approving := req.Approved && !progress.Approved
+		if err := services.LogTaskActivity(tx, &task, userID, models.ActivityProgressUpdated, map[string]interface{}{
+			"user_id":  req.UserID,
+			"from":     previous,
+			"to":       req.Progress,
+			"approved": approving,
+		}); err != nil {
+			return err
+		}
 
-	allCompleted := true
-	for _, p := range allProgress {
-		if p.Progress < 100 {
-			allCompleted = false
-			break
+		// تایید از مسیر بررسی آخرین نسخه ارسالی عضو انجام می‌شود
+		if approving {
+			if err := services.ApproveMemberWork(tx, &task, req.UserID, userID); err != nil {
+				return err
+			}
+			if err := tx.First(&progress, progress.ID).Error; err != nil {
+				return err
+			}
 		}
-	}
 
-	if allCompleted {
-		task.Status = models.StatusCompleted
-		config.DB.Save(&task)
+		// تسک وقتی تکمیل می‌شود که کار همه اعضای اختصاص‌یافته پذیرفته شده باشد
+		return services.SyncGroupTaskCompletion(tx, &task, userID)
+	})
+	if errors.Is(err, services.ErrNotAwaitingReview) {
+		utils.ErrorResponse(c, http.StatusConflict, "این عضو نسخه‌ای منتظر بررسی ندارد؛ تایید پس از ارسال کار ممکن است")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی پیشرفت")
+		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "پیشرفت عضو با موفقیت بروزرسانی شد", progress)
@@ -240,8 +330,14 @@ func GetPersonalProgress(c *gin.Context) {
 func GetGroupProgress(c *gin.Context) {
 	taskID := c.Param("task_id")
 
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+	if !ok {
+		return
+	}
+	groupTasks := config.DB.Model(&models.Task{}).Select("id").Where("group_id = ?", member.GroupID)
+
 	var progress []models.GroupTaskProgress
-	if err := config.DB.Where("task_id = ?", taskID).
+	if err := config.DB.Where("task_id = ? AND task_id IN (?)", taskID, groupTasks).
 		Preload("User").
 		Find(&progress).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت پیشرفت")
@@ -262,23 +358,59 @@ func UpdateGroupProgress(c *gin.Context) {
 		return
 	}
 
-	// دریافت یا ایجاد رکورد پیشرفت
+	if !ensureUnblocked(c, StringToUint(taskID), req.Progress) {
+		return
+	}
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermContribute, "شما اجازه بروزرسانی پیشرفت این تسک را ندارید")
+	if !ok {
+		return
+	}
+
+	var task models.Task
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&task, taskID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "تسک پیدا نشد")
+		return
+	}
+	if !manualProgressAllowed(c, &task) {
+		return
+	}
+
 	var progress models.GroupTaskProgress
-	if err := config.DB.Where("task_id = ? AND user_id = ?", taskID, userID).First(&progress).Error; err != nil {
-		// رکورد جدید است
-		progress = models.GroupTaskProgress{
-			TaskID:     StringToUint(taskID),
-			UserID:     userID,
-			AssignedBy: req.UserID,
-			Progress:   req.Progress,
-			Notes:      req.Notes,
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// دریافت یا ایجاد رکورد پیشرفت
+		previous := 0
+		if err := tx.Where("task_id = ? AND user_id = ?", taskID, userID).First(&progress).Error; err != nil {
+			// رکورد جدید است
+			progress = models.GroupTaskProgress{
+				TaskID:     StringToUint(taskID),
+				UserID:     userID,
+				AssignedBy: req.UserID,
+				Notes:      req.Notes,
+			}
+		} else {
+			// به‌روزرسانی رکورد موجود
+			previous = progress.Progress
+			progress.Notes = req.Notes
 		}
-		config.DB.Create(&progress)
-	} else {
-		// به‌روزرسانی رکورد موجود
-		progress.Progress = req.Progress
-		progress.Notes = req.Notes
-		config.DB.Save(&progress)
+		services.ApplyMemberProgress(&progress, req.Progress)
+		if err := tx.Save(&progress).Error; err != nil {
+			return err
+		}
+		if err := services.LogTaskActivity(tx, &task, userID, models.ActivityProgressUpdated, map[string]interface{}{
+			"user_id": userID,
+			"from":    previous,
+			"to":      req.Progress,
+		}); err != nil {
+			return err
+		}
+
+		// کاهش پیشرفت تسک تکمیل‌شده را باز می‌کند؛ تکمیل دوباره منتظر تایید بررسی‌کننده می‌ماند
+		return services.SyncGroupTaskCompletion(tx, &task, userID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی پیشرفت")
+		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "پیشرفت با موفقیت بروزرسانی شد", progress)
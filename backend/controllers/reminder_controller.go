@@ -0,0 +1,234 @@
+// backend/controllers/reminder_controller.go
+
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CreateReminderRuleRequest struct {
+	Lead   string `json:"lead" binding:"required"` // مثلا "1d"، "2h" یا "30m"
+	TaskID *uint  `json:"task_id"`                 // خالی برای قانون پیش‌فرض کاربر
+}
+
+type SnoozeReminderRequest struct {
+	Duration string     `json:"duration"` // مثلا "1h"
+	Until    *time.Time `json:"until"`
+}
+
+var reminderListSpec = utils.ListSpec{
+	Table: "reminders",
+	Sortable: map[string]string{
+		"remind_at": "reminders.remind_at",
+		"deadline":  "reminders.deadline",
+	},
+	DefaultSort: "remind_at",
+	Filters: map[string]string{
+		"status":  "reminders.status",
+		"task_id": "reminders.task_id",
+	},
+}
+
+// GetReminders - دریافت یادآوری‌های کاربر؛ بدون پارامتر status فقط یادآوری‌های پیش رو
+func GetReminders(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	list, err := utils.ParseListQuery(c, reminderListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := config.DB.Model(&models.Reminder{}).Where("reminders.user_id = ?", userID)
+	if c.Query("status") == "" {
+		query = query.Where("reminders.status = ?", models.ReminderPending)
+	}
+
+	var reminders []models.Reminder
+	if err := list.Find(query, &reminders, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Task")
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت یادآوری‌ها")
+		return
+	}
+
+	utils.ListResponse(c, reminders, list)
+}
+
+// SnoozeReminder - به تعویق انداختن یادآوری؛ یادآوری ارسال‌شده هم دوباره فعال می‌شود
+func SnoozeReminder(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req SnoozeReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var remindAt time.Time
+	switch {
+	case req.Until != nil:
+		remindAt = *req.Until
+	case req.Duration != "":
+		d, err := utils.ParseFriendlyDuration(req.Duration)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		remindAt = time.Now().Add(d)
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "duration یا until الزامی است")
+		return
+	}
+	if !remindAt.After(time.Now()) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "زمان یادآوری باید در آینده باشد")
+		return
+	}
+
+	var reminder models.Reminder
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&reminder).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "یادآوری پیدا نشد")
+		return
+	}
+	if reminder.Status != models.ReminderPending && reminder.Status != models.ReminderSent {
+		utils.ErrorResponse(c, http.StatusConflict, "این یادآوری دیگر فعال نیست")
+		return
+	}
+
+	if err := config.DB.Model(&reminder).Updates(map[string]interface{}{
+		"remind_at": remindAt,
+		"status":    models.ReminderPending,
+		"sent_at":   nil,
+	}).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در به تعویق انداختن یادآوری")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "یادآوری به تعویق افتاد", reminder)
+}
+
+// DismissReminder - رد کردن یادآوری؛ دیگر ارسال نمی‌شود
+func DismissReminder(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var reminder models.Reminder
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&reminder).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "یادآوری پیدا نشد")
+		return
+	}
+
+	if err := config.DB.Model(&reminder).Update("status", models.ReminderDismissed).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در رد کردن یادآوری")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "یادآوری رد شد", reminder)
+}
+
+// GetReminderRules - دریافت قوانین یادآوری کاربر؛ با task_id فقط قوانین همان تسک
+func GetReminderRules(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	query := config.DB.Where("user_id = ?", userID)
+	if taskID := c.Query("task_id"); taskID != "" {
+		query = query.Where("task_id = ?", taskID)
+	} else {
+		query = query.Where("task_id IS NULL")
+	}
+
+	var rules []models.ReminderRule
+	if err := query.Order("lead_minutes DESC").Find(&rules).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت قوانین یادآوری")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", rules)
+}
+
+// CreateReminderRule - افزودن قانون یادآوری پیش‌فرض یا مخصوص یک تسک
+func CreateReminderRule(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req CreateReminderRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lead, err := utils.ParseFriendlyDuration(req.Lead)
+	if err != nil || lead < time.Minute {
+		utils.ErrorResponse(c, http.StatusBadRequest, "فاصله یادآوری نامعتبر است")
+		return
+	}
+
+	if req.TaskID != nil {
+		if _, ok := loadAccessibleTask(c, strconv.FormatUint(uint64(*req.TaskID), 10)); !ok {
+			return
+		}
+	}
+
+	rule := models.ReminderRule{
+		UserID:      userID,
+		TaskID:      req.TaskID,
+		LeadMinutes: int(lead / time.Minute),
+	}
+
+	var count int64
+	scope := config.DB.Model(&models.ReminderRule{}).Where("user_id = ? AND lead_minutes = ?", userID, rule.LeadMinutes)
+	if rule.TaskID != nil {
+		scope = scope.Where("task_id = ?", *rule.TaskID)
+	} else {
+		scope = scope.Where("task_id IS NULL")
+	}
+	scope.Count(&count)
+	if count > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "این قانون یادآوری وجود دارد")
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rule).Error; err != nil {
+			return err
+		}
+		return services.SyncUserReminders(tx, userID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ایجاد قانون یادآوری")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "قانون یادآوری ایجاد شد", rule)
+}
+
+// DeleteReminderRule - حذف قانون یادآوری و لغو یادآوری‌های در انتظار آن
+func DeleteReminderRule(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var rule models.ReminderRule
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&rule).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "قانون یادآوری پیدا نشد")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&rule).Error; err != nil {
+			return err
+		}
+		return services.SyncUserReminders(tx, userID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف قانون یادآوری")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "قانون یادآوری حذف شد", nil)
+}
@@ -0,0 +1,380 @@
+// backend/controllers/role_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type GroupRoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// RoleMatrix ماتریس اجازه‌های نقش‌های پیش‌فرض به همراه نقش‌های سفارشی گروه
+type RoleMatrix struct {
+	Permissions []models.Permission            `json:"permissions"`
+	Builtin     map[string][]models.Permission `json:"builtin"`
+	Custom      []models.GroupRole             `json:"custom"`
+}
+
+var errRoleExists = errors.New("role name already in use")
+
+// actorPermissions - اجازه‌های نقش کاربر فعلی؛ کاربر نمی‌تواند اجازه‌ای بیش از نقش خودش به دیگران بدهد
+func actorPermissions(c *gin.Context, member *models.GroupMember) (map[models.Permission]bool, bool) {
+	perms, err := services.RolePermissions(config.DB, member.GroupID, member.Role)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بررسی دسترسی")
+		return nil, false
+	}
+	return perms, true
+}
+
+// permissionsWithin - آیا تمام اجازه‌های perms در allowed وجود دارند
+func permissionsWithin(perms, allowed map[models.Permission]bool) bool {
+	for perm, granted := range perms {
+		if granted && !allowed[perm] {
+			return false
+		}
+	}
+	return true
+}
+
+// bindGroupRole - اعتبارسنجی نام و اجازه‌های نقش سفارشی؛ در صورت خطا پاسخ نوشته می‌شود
+func bindGroupRole(c *gin.Context, role *models.GroupRole, allowed map[models.Permission]bool) bool {
+	var req GroupRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "نام نقش نمی‌تواند خالی باشد")
+		return false
+	}
+	if _, builtin := models.BuiltinRoles[name]; builtin {
+		utils.ErrorResponse(c, http.StatusConflict, "این نام متعلق به یک نقش پیش‌فرض است")
+		return false
+	}
+
+	known := make(map[models.Permission]bool, len(models.AllPermissions))
+	for _, perm := range models.AllPermissions {
+		known[perm] = true
+	}
+	requested := make(map[models.Permission]bool, len(req.Permissions))
+	var perms []string
+	for _, p := range req.Permissions {
+		perm := models.Permission(strings.TrimSpace(p))
+		if !known[perm] {
+			utils.ErrorResponse(c, http.StatusBadRequest, "اجازه نامعتبر: "+p)
+			return false
+		}
+		if !requested[perm] {
+			requested[perm] = true
+			perms = append(perms, string(perm))
+		}
+	}
+	if !permissionsWithin(requested, allowed) {
+		utils.ErrorResponse(c, http.StatusForbidden, "نمی‌توانید نقشی با اجازه‌های بیشتر از نقش خودتان تعریف کنید")
+		return false
+	}
+
+	role.Name = name
+	role.Permissions = strings.Join(perms, ",")
+	return true
+}
+
+// canGrantRoles - فقط مالک نقش admin را می‌دهد یا می‌گیرد و هیچ‌کس نقشی با اجازه‌های بیشتر از نقش خودش را
+// جابه‌جا نمی‌کند؛ در صورت خطا پاسخ نوشته می‌شود
+func canGrantRoles(c *gin.Context, actor *models.GroupMember, roles ...string) bool {
+	if actor.Role == models.RoleOwner {
+		return true
+	}
+	allowed, ok := actorPermissions(c, actor)
+	if !ok {
+		return false
+	}
+	for _, role := range roles {
+		if role == models.RoleAdmin {
+			utils.ErrorResponse(c, http.StatusForbidden, "فقط مالک گروه می‌تواند نقش مدیر را بدهد یا بگیرد")
+			return false
+		}
+		perms, err := services.RolePermissions(config.DB, actor.GroupID, role)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بررسی دسترسی")
+			return false
+		}
+		if !permissionsWithin(perms, allowed) {
+			utils.ErrorResponse(c, http.StatusForbidden, "نمی‌توانید نقشی با اجازه‌های بیشتر از نقش خودتان را بدهید یا بگیرید")
+			return false
+		}
+	}
+	return true
+}
+
+// ensureRoleNameFree - نام نقش سفارشی در هر گروه یکتاست
+func ensureRoleNameFree(tx *gorm.DB, role *models.GroupRole) error {
+	var count int64
+	if err := tx.Model(&models.GroupRole{}).
+		Where("group_id = ? AND name = ? AND id <> ?", role.GroupID, role.Name, role.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errRoleExists
+	}
+	return nil
+}
+
+// GetGroupRoles - ماتریس اجازه‌ها و نقش‌های سفارشی گروه
+func GetGroupRoles(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+	if !ok {
+		return
+	}
+
+	matrix := RoleMatrix{
+		Permissions: models.AllPermissions,
+		Builtin:     models.BuiltinRoles,
+		Custom:      []models.GroupRole{},
+	}
+	if err := config.DB.Where("group_id = ?", member.GroupID).Order("name ASC").Find(&matrix.Custom).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت نقش‌ها")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", matrix)
+}
+
+// CreateGroupRole - تعریف نقش سفارشی در گروه
+func CreateGroupRole(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageRoles, "شما اجازه مدیریت نقش‌های این گروه را ندارید")
+	if !ok {
+		return
+	}
+	allowed, ok := actorPermissions(c, member)
+	if !ok {
+		return
+	}
+
+	role := models.GroupRole{GroupID: member.GroupID}
+	if !bindGroupRole(c, &role, allowed) {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureRoleNameFree(tx, &role); err != nil {
+			return err
+		}
+		if err := tx.Create(&role).Error; err != nil {
+			return err
+		}
+		return services.LogGroupActivity(tx, role.GroupID, userID, models.ActivityRoleCreated, map[string]interface{}{
+			"name":        role.Name,
+			"permissions": role.PermissionList(),
+		})
+	})
+	if errors.Is(err, errRoleExists) {
+		utils.ErrorResponse(c, http.StatusConflict, "نقشی با این نام در گروه وجود دارد")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ایجاد نقش")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "نقش با موفقیت ایجاد شد", role)
+}
+
+// UpdateGroupRole - ویرایش نام و اجازه‌های نقش سفارشی؛ اعضای دارای این نقش با نام جدید باقی می‌مانند
+func UpdateGroupRole(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageRoles, "شما اجازه مدیریت نقش‌های این گروه را ندارید")
+	if !ok {
+		return
+	}
+	allowed, ok := actorPermissions(c, member)
+	if !ok {
+		return
+	}
+
+	var role models.GroupRole
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&role, c.Param("role_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "نقش پیدا نشد")
+		return
+	}
+	if !permissionsWithin(rolePermissionSet(&role), allowed) {
+		utils.ErrorResponse(c, http.StatusForbidden, "نمی‌توانید نقشی با اجازه‌های بیشتر از نقش خودتان را ویرایش کنید")
+		return
+	}
+	oldName := role.Name
+	if !bindGroupRole(c, &role, allowed) {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureRoleNameFree(tx, &role); err != nil {
+			return err
+		}
+		if err := tx.Save(&role).Error; err != nil {
+			return err
+		}
+		if role.Name != oldName {
+			if err := tx.Model(&models.GroupMember{}).
+				Where("group_id = ? AND role = ?", role.GroupID, oldName).
+				Update("role", role.Name).Error; err != nil {
+				return err
+			}
+		}
+		return services.LogGroupActivity(tx, role.GroupID, userID, models.ActivityRoleUpdated, map[string]interface{}{
+			"name":        role.Name,
+			"old_name":    oldName,
+			"permissions": role.PermissionList(),
+		})
+	})
+	if errors.Is(err, errRoleExists) {
+		utils.ErrorResponse(c, http.StatusConflict, "نقشی با این نام در گروه وجود دارد")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی نقش")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "نقش با موفقیت بروزرسانی شد", role)
+}
+
+// DeleteGroupRole - حذف نقش سفارشی؛ نقشی که هنوز به عضوی داده شده قابل حذف نیست
+func DeleteGroupRole(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageRoles, "شما اجازه مدیریت نقش‌های این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	var role models.GroupRole
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&role, c.Param("role_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "نقش پیدا نشد")
+		return
+	}
+
+	var inUse int64
+	if err := config.DB.Model(&models.GroupMember{}).
+		Where("group_id = ? AND role = ?", role.GroupID, role.Name).
+		Count(&inUse).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف نقش")
+		return
+	}
+	if inUse > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "ابتدا نقش اعضایی که این نقش را دارند تغییر دهید")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&role).Error; err != nil {
+			return err
+		}
+		return services.LogGroupActivity(tx, role.GroupID, userID, models.ActivityRoleDeleted, map[string]interface{}{"name": role.Name})
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف نقش")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "نقش با موفقیت حذف شد", nil)
+}
+
+// UpdateMemberRole - تغییر نقش یک عضو
+// نقش مالک فقط با انتقال مالکیت تغییر می‌کند، نقش admin فقط توسط مالک داده یا گرفته می‌شود
+// و هیچ‌کس نمی‌تواند نقشی با اجازه‌های بیشتر از نقش خودش بدهد یا از عضوی با چنین نقشی بگیرد
+func UpdateMemberRole(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Role = strings.ToLower(strings.TrimSpace(req.Role))
+
+	actor, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageRoles, "شما اجازه تغییر نقش اعضای این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	var target models.GroupMember
+	if err := config.DB.Where("group_id = ? AND user_id = ?", actor.GroupID, c.Param("user_id")).First(&target).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "عضو پیدا نشد")
+		return
+	}
+	if target.Role == models.RoleOwner {
+		utils.ErrorResponse(c, http.StatusForbidden, "نقش مالک گروه فقط با انتقال مالکیت تغییر می‌کند")
+		return
+	}
+	valid, err := services.IsValidRole(config.DB, actor.GroupID, req.Role)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در تغییر نقش")
+		return
+	}
+	if !valid {
+		utils.ErrorResponse(c, http.StatusBadRequest, "نقش نامعتبر است")
+		return
+	}
+	if target.Role == req.Role {
+		utils.SuccessResponse(c, http.StatusOK, "تغییری ایجاد نشد", target)
+		return
+	}
+
+	if !canGrantRoles(c, actor, target.Role, req.Role) {
+		return
+	}
+
+	from := target.Role
+	target.Role = req.Role
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&target).Update("role", target.Role).Error; err != nil {
+			return err
+		}
+		if err := services.LogGroupActivity(tx, target.GroupID, userID, models.ActivityRoleChanged, map[string]interface{}{
+			"user_id": target.UserID,
+			"from":    from,
+			"to":      target.Role,
+		}); err != nil {
+			return err
+		}
+		return services.Notify(tx, []uint{target.UserID}, models.NotificationRoleChanged,
+			"تغییر نقش در گروه", "نقش شما در گروه به "+target.Role+" تغییر کرد", target.GroupID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در تغییر نقش")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "نقش عضو با موفقیت تغییر کرد", target)
+}
+
+// rolePermissionSet - اجازه‌های نقش سفارشی به شکل مجموعه
+func rolePermissionSet(role *models.GroupRole) map[models.Permission]bool {
+	perms := make(map[models.Permission]bool)
+	for _, perm := range role.PermissionList() {
+		perms[perm] = true
+	}
+	return perms
+}
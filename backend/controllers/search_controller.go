@@ -0,0 +1,81 @@
+// backend/controllers/search_controller.go
+
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"task-manager/config"
+	"task-manager/search"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Search - جستجوی یکپارچه در تسک‌ها، گروه‌ها، فایل‌ها و یادداشت‌های پیشرفت
+// پارامترها: q (الزامی)، types (مثلا task,file) و limit
+func Search(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "عبارت جستجو الزامی است")
+		return
+	}
+
+	opts := search.Options{Limit: utils.DefaultPageSize}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "limit نامعتبر است")
+			return
+		}
+		if limit > utils.MaxPageSize {
+			limit = utils.MaxPageSize
+		}
+		opts.Limit = limit
+	}
+	if v := c.Query("types"); v != "" {
+		for _, kind := range strings.Split(v, ",") {
+			kind = strings.TrimSpace(kind)
+			if !isSearchKind(kind) {
+				utils.ErrorResponse(c, http.StatusBadRequest, "نوع جستجوی نامعتبر: "+kind)
+				return
+			}
+			opts.Kinds = append(opts.Kinds, kind)
+		}
+	}
+
+	access, err := search.AccessFor(config.DB, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت گروه‌ها")
+		return
+	}
+
+	index := search.Current()
+	if index == nil {
+		index = search.NewMySQLIndex(config.DB)
+	}
+
+	results, err := index.Search(access, query, opts)
+	if err != nil {
+		utils.LogError("Search failed", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در جستجو")
+		return
+	}
+	if results == nil {
+		results = []search.Result{}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", results)
+}
+
+func isSearchKind(kind string) bool {
+	for _, k := range search.AllKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
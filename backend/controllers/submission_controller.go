@@ -0,0 +1,161 @@
+// backend/controllers/submission_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type SubmissionRequest struct {
+	Notes string `json:"notes"`
+}
+
+type ReviewSubmissionRequest struct {
+	Decision string `json:"decision" binding:"required"` // approve، request_changes یا reject
+	Comment  string `json:"comment"`
+}
+
+// submissionError - تبدیل خطای ارسال و بررسی به پاسخ مناسب
+func submissionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrNotAssignee):
+		utils.ErrorResponse(c, http.StatusForbidden, "این تسک به شما اختصاص داده نشده است")
+	case errors.Is(err, services.ErrSubmissionPending):
+		utils.ErrorResponse(c, http.StatusConflict, "نسخه قبلی شما هنوز منتظر بررسی است")
+	case errors.Is(err, services.ErrSubmissionClosed):
+		utils.ErrorResponse(c, http.StatusConflict, "ارسال شما قبلا تایید یا رد شده است")
+	case errors.Is(err, services.ErrSubmissionEmpty):
+		utils.ErrorResponse(c, http.StatusBadRequest, "این تسک نیاز به فایل دارد؛ ابتدا فایل‌های جدید را آپلود کنید")
+	case errors.Is(err, services.ErrNotAwaitingReview):
+		utils.ErrorResponse(c, http.StatusConflict, "این نسخه قبلا بررسی شده است")
+	case errors.Is(err, services.ErrInvalidDecision):
+		utils.ErrorResponse(c, http.StatusBadRequest, "decision باید یکی از approve، request_changes یا reject باشد")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
+
+// loadMemberGroupTask - تسک گروه برای اعضایی که اجازه perm را دارند؛ در صورت خطا پاسخ نوشته می‌شود
+func loadMemberGroupTask(c *gin.Context, perm models.Permission, message string) (*models.Task, bool) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), perm, message)
+	if !ok {
+		return nil, false
+	}
+	var task models.Task
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&task, c.Param("task_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "تسک پیدا نشد")
+		return nil, false
+	}
+	return &task, true
+}
+
+// respondSubmission - بازگرداندن نسخه ارسالی همراه با فایل‌ها، عضو و بررسی‌کننده
+func respondSubmission(c *gin.Context, status int, message string, submissionID uint) {
+	var submission models.TaskSubmission
+	config.DB.Preload("Files").Preload("User").Preload("Reviewer").First(&submission, submissionID)
+	utils.SuccessResponse(c, status, message, submission)
+}
+
+// SubmitGroupTask - ارسال کار عضو برای بررسی؛ فایل‌های آپلودشده از نسخه قبلی به این نسخه متصل می‌شوند
+func SubmitGroupTask(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req SubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, ok := loadMemberGroupTask(c, models.PermContribute, "شما اجازه انجام تسک‌های این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	var submission *models.TaskSubmission
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		submission, err = services.SubmitWork(tx, task, userID, req.Notes)
+		return err
+	})
+	if err != nil {
+		submissionError(c, err, "خطا در ارسال کار")
+		return
+	}
+
+	respondSubmission(c, http.StatusCreated, "کار شما برای بررسی ارسال شد", submission.ID)
+}
+
+// GetGroupTaskSubmissions - تاریخچه نسخه‌های ارسالی تسک؛ بررسی‌کنندگان همه را می‌بینند و بقیه فقط ارسال‌های خود را
+func GetGroupTaskSubmissions(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	task, ok := loadMemberGroupTask(c, "", "")
+	if !ok {
+		return
+	}
+
+	query := config.DB.Where("task_id = ?", task.ID)
+	if !hasGroupPermission(userID, *task.GroupID, models.PermApproveFiles) {
+		query = query.Where("user_id = ?", userID)
+	} else if memberID := c.Query("user_id"); memberID != "" {
+		query = query.Where("user_id = ?", StringToUint(memberID))
+	}
+
+	var submissions []models.TaskSubmission
+	if err := query.Preload("Files").
+		Preload("User").
+		Preload("Reviewer").
+		Order("user_id ASC, revision DESC").
+		Find(&submissions).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت ارسال‌ها")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", submissions)
+}
+
+// ReviewGroupTaskSubmission - تایید، درخواست تغییرات یا رد نسخه ارسالی؛ برای درخواست تغییرات و رد نظر لازم است
+func ReviewGroupTaskSubmission(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req ReviewSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if req.Decision != models.ReviewApprove && req.Comment == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "برای درخواست تغییرات یا رد، نوشتن نظر الزامی است")
+		return
+	}
+
+	task, ok := loadMemberGroupTask(c, models.PermApproveFiles, "شما اجازه بررسی ارسال‌های این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	var submission models.TaskSubmission
+	if err := config.DB.Where("task_id = ?", task.ID).First(&submission, c.Param("submission_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "نسخه ارسالی پیدا نشد")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.ReviewSubmission(tx, task, &submission, req.Decision, req.Comment, userID)
+	})
+	if err != nil {
+		submissionError(c, err, "خطا در بررسی ارسال")
+		return
+	}
+
+	respondSubmission(c, http.StatusOK, "نتیجه بررسی ثبت شد", submission.ID)
+}
@@ -0,0 +1,156 @@
+// backend/controllers/subtask_controller.go
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findOwnTask loads a task owned by the current user and writes the error response if it can't
+func findOwnTask(c *gin.Context, taskID string) (*models.Task, bool) {
+	userID := c.GetUint("userID")
+
+	var task models.Task
+	if err := config.DB.Where("id = ? AND creator_id = ?", taskID, userID).First(&task).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Task not found")
+		} else {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		}
+		return nil, false
+	}
+	return &task, true
+}
+
+// GetSubtasks - list the direct subtasks of a task
+func GetSubtasks(c *gin.Context) {
+	parent, ok := findOwnTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var subtasks []models.Task
+	config.DB.Where("parent_id = ?", parent.ID).Order("created_at ASC").Find(&subtasks)
+	utils.SuccessResponse(c, http.StatusOK, "OK", subtasks)
+}
+
+// CreateSubtask - create a new task under an existing one
+func CreateSubtask(c *gin.Context) {
+	userID := c.GetUint("userID")
+	parent, ok := findOwnTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var req CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Recurrence != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Subtasks can't be recurring")
+		return
+	}
+
+	subtask := models.Task{
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+		DueDate:     req.DueDate,
+		CreatorID:   userID,
+		Status:      models.StatusPending,
+		ParentID:    &parent.ID,
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&subtask).Error; err != nil {
+			return err
+		}
+		return services.SyncParentProgress(tx, subtask.ParentID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create subtask")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Subtask created successfully", subtask)
+}
+
+// AttachSubtask - move an existing task under another one
+func AttachSubtask(c *gin.Context) {
+	parent, ok := findOwnTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	subtask, ok := findOwnTask(c, c.Param("subtask_id"))
+	if !ok {
+		return
+	}
+
+	oldParentID := subtask.ParentID
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		cycle, err := services.IsAncestor(tx, subtask.ID, parent.ID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return services.ErrTaskCycle
+		}
+
+		subtask.ParentID = &parent.ID
+		if err := tx.Save(subtask).Error; err != nil {
+			return err
+		}
+		if err := services.SyncParentProgress(tx, oldParentID); err != nil {
+			return err
+		}
+		return services.SyncParentProgress(tx, subtask.ParentID)
+	})
+	if errors.Is(err, services.ErrTaskCycle) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to attach subtask")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Subtask attached successfully", subtask)
+}
+
+// DetachSubtask - turn a subtask back into a top-level task
+func DetachSubtask(c *gin.Context) {
+	parent, ok := findOwnTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	subtask, ok := findOwnTask(c, c.Param("subtask_id"))
+	if !ok {
+		return
+	}
+	if subtask.ParentID == nil || *subtask.ParentID != parent.ID {
+		utils.ErrorResponse(c, http.StatusNotFound, "Subtask not found")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		subtask.ParentID = nil
+		if err := tx.Save(subtask).Error; err != nil {
+			return err
+		}
+		return services.SyncParentProgress(tx, &parent.ID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to detach subtask")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Subtask detached successfully", subtask)
+}
@@ -0,0 +1,51 @@
+// backend/controllers/task_access.go
+package controllers
+
+import (
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// canAccessTask - تسک شخصی برای سازنده و تسک گروهی برای اعضای پذیرفته‌شده گروه قابل مشاهده است
+func canAccessTask(userID uint, task *models.Task) bool {
+	if !task.IsGroupTask {
+		return task.CreatorID == userID
+	}
+	var member models.GroupMember
+	return config.DB.Where("group_id = ? AND user_id = ? AND accepted = ?", task.GroupID, userID, true).
+		First(&member).Error == nil
+}
+
+// canManageTask - تسک شخصی توسط سازنده و تسک گروهی توسط اعضای دارای اجازه manage_tasks مدیریت می‌شود
+func canManageTask(userID uint, task *models.Task) bool {
+	if !task.IsGroupTask {
+		return task.CreatorID == userID
+	}
+	return task.GroupID != nil && hasGroupPermission(userID, *task.GroupID, models.PermManageTasks)
+}
+
+// loadAccessibleTask - دریافت تسک با بررسی دسترسی؛ در صورت خطا پاسخ نوشته می‌شود
+func loadAccessibleTask(c *gin.Context, taskID string) (*models.Task, bool) {
+	userID := c.GetUint("userID")
+
+	var task models.Task
+	if err := config.DB.First(&task, taskID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "تسک پیدا نشد")
+		} else {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت تسک")
+		}
+		return nil, false
+	}
+
+	if !canAccessTask(userID, &task) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما به این تسک دسترسی ندارید")
+		return nil, false
+	}
+	return &task, true
+}
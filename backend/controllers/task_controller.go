@@ -2,31 +2,136 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"task-manager/config"
 	"task-manager/models"
+	"task-manager/services"
 	"task-manager/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// Edit scopes for recurring tasks
+const (
+	ScopeThis   = "this"   // only this occurrence
+	ScopeFuture = "future" // this and all future occurrences
+)
+
 type CreateTaskRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
+	Title       string             `json:"title" binding:"required"`
+	Description string             `json:"description"`
+	Priority    string             `json:"priority"`
+	DueDate     *time.Time         `json:"due_date"`
+	Recurrence  *RecurrenceRequest `json:"recurrence"`
+	// EstimateMinutes is the planned effort used by the time reports
+	EstimateMinutes *int `json:"estimate_minutes" binding:"omitempty,min=0"`
+	// ProgressFromChecklist derives progress from the task's checklist
+	ProgressFromChecklist bool `json:"progress_from_checklist"`
+	// SubtaskPolicy is "require" (default) or "auto_complete"
+	SubtaskPolicy string `json:"subtask_policy" binding:"omitempty,oneof=require auto_complete"`
 }
 
 type UpdateTaskRequest struct {
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Status      models.TaskStatus `json:"status"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Status      models.TaskStatus  `json:"status"`
+	Priority    string             `json:"priority"`
+	DueDate     *time.Time         `json:"due_date"`
+	Recurrence  *RecurrenceRequest `json:"recurrence"`
+	Scope       string             `json:"scope"` // "this" (default) or "future"
+
+	EstimateMinutes       *int  `json:"estimate_minutes" binding:"omitempty,min=0"`
+	ProgressFromChecklist *bool `json:"progress_from_checklist"`
+
+	SubtaskPolicy string `json:"subtask_policy" binding:"omitempty,oneof=require auto_complete"`
+}
+
+// taskListSpec is shared by the personal and group task listings
+var taskListSpec = utils.ListSpec{
+	Table: "tasks",
+	Sortable: map[string]string{
+		"created_at": "tasks.created_at",
+		"updated_at": "tasks.updated_at",
+		"due_date":   "COALESCE(tasks.due_date, '9999-12-31 23:59:59')",
+		"priority":   "FIELD(tasks.priority, 'low', 'medium', 'high')",
+		"status":     "tasks.status",
+		"title":      "tasks.title",
+	},
+	DefaultSort: "-created_at",
+	Searchable:  []string{"tasks.title", "tasks.description"},
+	Filters: map[string]string{
+		"status":   "tasks.status",
+		"priority": "tasks.priority",
+	},
+	DateColumn: "tasks.due_date",
+}
+
+// RecurrenceRequest either a raw RRULE or the daily/weekly/monthly shorthand
+type RecurrenceRequest struct {
+	RRule      string     `json:"rrule"`
+	Frequency  string     `json:"frequency"` // daily, weekly, monthly
+	Interval   int        `json:"interval"`
+	ByDay      []string   `json:"by_day"`       // MO, TU, ...
+	ByMonthDay []int      `json:"by_month_day"` // 1..31 or -1 for the last day
+	Until      *time.Time `json:"until"`
+	Count      int        `json:"count"`
+}
+
+// toRRule validates the request and converts it to an RRule
+func (r *RecurrenceRequest) toRRule() (*utils.RRule, error) {
+	if r.RRule != "" {
+		return utils.ParseRRule(r.RRule)
+	}
+	if r.Frequency == "" {
+		return nil, &utils.ValidationError{Field: "recurrence", Message: "frequency or rrule is required"}
+	}
+
+	// the shorthand is turned into an RRULE so both go through the same validation
+	parts := []string{"FREQ=" + r.Frequency}
+	if r.Interval > 0 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		parts = append(parts, "BYDAY="+strings.Join(r.ByDay, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return utils.ParseRRule(strings.Join(parts, ";"))
 }
 
 func GetTasks(c *gin.Context) {
 	userID := c.GetUint("userID")
+	list, err := utils.ParseListQuery(c, taskListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var tasks []models.Task
-	config.DB.Where("creator_id = ?", userID).Order("created_at DESC").Find(&tasks)
-	utils.SuccessResponse(c, http.StatusOK, "OK", tasks)
+	query := applyLabelFilter(c, config.DB.Model(&models.Task{}).Where("creator_id = ?", userID))
+	if err := list.Find(query, &tasks, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Labels")
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Database error")
+		return
+	}
+	utils.ListResponse(c, tasks, list)
 }
 
 func GetTask(c *gin.Context) {
@@ -34,7 +139,7 @@ func GetTask(c *gin.Context) {
 	taskID := c.Param("id")
 
 	var task models.Task
-	if err := config.DB.Where("id = ? AND creator_id = ?", taskID, userID).First(&task).Error; err != nil {
+	if err := config.DB.Preload("Recurrence").Preload("Subtasks").Preload("Labels").Where("id = ? AND creator_id = ?", taskID, userID).First(&task).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			utils.ErrorResponse(c, http.StatusNotFound, "Task not found")
 		} else {
@@ -56,10 +161,76 @@ func CreateTask(c *gin.Context) {
 	task := models.Task{
 		Title:       req.Title,
 		Description: req.Description,
+		Priority:    req.Priority,
+		DueDate:     req.DueDate,
 		CreatorID:   userID,
 		Status:      models.StatusPending,
+
+		EstimateMinutes:       req.EstimateMinutes,
+		ProgressFromChecklist: req.ProgressFromChecklist,
+		SubtaskPolicy:         req.SubtaskPolicy,
+	}
+	if task.SubtaskPolicy == "" {
+		task.SubtaskPolicy = models.SubtaskPolicyRequire
+	}
+
+	if req.Recurrence == nil {
+		err := config.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&task).Error; err != nil {
+				return err
+			}
+			return services.LogTaskActivity(tx, &task, userID, models.ActivityTaskCreated, map[string]interface{}{"title": task.Title})
+		})
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create task")
+			return
+		}
+		utils.SuccessResponse(c, http.StatusCreated, "Task created successfully", task)
+		return
+	}
+
+	rule, err := req.Recurrence.toRRule()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
 	}
-	config.DB.Create(&task)
+
+	// the first occurrence anchors the series; without a due date it starts now
+	if task.DueDate == nil {
+		now := time.Now()
+		task.DueDate = &now
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		series := models.TaskRecurrence{
+			CreatorID:   userID,
+			Rule:        rule.String(),
+			StartsAt:    *task.DueDate,
+			Title:       task.Title,
+			Description: task.Description,
+			Priority:    task.Priority,
+			Occurrences: 1,
+			Active:      true,
+		}
+		if err := tx.Create(&series).Error; err != nil {
+			return err
+		}
+		task.RecurrenceID = &series.ID
+		task.OccurrenceIndex = 1
+		task.Recurrence = &series
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		return services.LogTaskActivity(tx, &task, userID, models.ActivityTaskCreated, map[string]interface{}{
+			"title":      task.Title,
+			"recurrence": series.Rule,
+		})
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create task")
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusCreated, "Task created successfully", task)
 }
 
@@ -73,6 +244,14 @@ func UpdateTask(c *gin.Context) {
 		return
 	}
 
+	if req.Scope == "" {
+		req.Scope = ScopeThis
+	}
+	if req.Scope != ScopeThis && req.Scope != ScopeFuture {
+		utils.ErrorResponse(c, http.StatusBadRequest, "scope must be 'this' or 'future'")
+		return
+	}
+
 	var task models.Task
 	if err := config.DB.Where("id = ? AND creator_id = ?", taskID, userID).First(&task).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -83,6 +262,27 @@ func UpdateTask(c *gin.Context) {
 		return
 	}
 
+	if task.RecurrenceID == nil && (req.Scope == ScopeFuture || req.Recurrence != nil) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Task is not recurring")
+		return
+	}
+
+	var rule *utils.RRule
+	if req.Recurrence != nil {
+		if req.Scope != ScopeFuture {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Changing the recurrence requires scope 'future'")
+			return
+		}
+		var err error
+		if rule, err = req.Recurrence.toRRule(); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	oldStatus := task.Status
+	before := task
+
 	// Update fields if provided
 	if req.Title != "" {
 		task.Title = req.Title
@@ -93,14 +293,147 @@ func UpdateTask(c *gin.Context) {
 	if req.Status != "" {
 		task.Status = req.Status
 	}
+	if req.Priority != "" {
+		task.Priority = req.Priority
+	}
+	if req.DueDate != nil {
+		task.DueDate = req.DueDate
+	}
+	if req.SubtaskPolicy != "" {
+		task.SubtaskPolicy = req.SubtaskPolicy
+	}
+	if req.EstimateMinutes != nil {
+		task.EstimateMinutes = req.EstimateMinutes
+	}
+	if req.ProgressFromChecklist != nil {
+		task.ProgressFromChecklist = *req.ProgressFromChecklist
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if oldStatus != task.Status && services.IsStartingStatus(task.Status) {
+			if err := services.EnsureUnblocked(tx, task.ID); err != nil {
+				return err
+			}
+		}
+		completing := oldStatus != models.StatusCompleted && task.Status == models.StatusCompleted
+		if completing {
+			if task.IsGroupTask {
+				if err := services.EnsureGroupTaskReady(tx, &task); err != nil {
+					return err
+				}
+			}
+			if err := services.PrepareCompletion(tx, &task); err != nil {
+				return err
+			}
+		}
+		if req.Scope == ScopeFuture {
+			if err := applyToFutureOccurrences(tx, &task, rule, req.DueDate != nil); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
+		if changes := services.DiffFields(before, task, "Title", "Description", "Priority", "DueDate", "SubtaskPolicy", "EstimateMinutes", "ProgressFromChecklist"); len(changes) > 0 {
+			if err := services.LogTaskActivity(tx, &task, userID, models.ActivityTaskUpdated, changes); err != nil {
+				return err
+			}
+		}
+		if task.ProgressFromChecklist && !before.ProgressFromChecklist {
+			if err := services.SyncAllChecklistProgress(tx, &task, userID); err != nil {
+				return err
+			}
+		}
+		if err := services.LogStatusChange(tx, &task, userID, oldStatus, task.Status); err != nil {
+			return err
+		}
+		if completing {
+			if _, err := services.GenerateNextOccurrence(tx, &task); err != nil {
+				return err
+			}
+		}
+		if req.DueDate != nil || oldStatus != task.Status {
+			if err := services.SyncTaskReminders(tx, &task); err != nil {
+				return err
+			}
+		}
+		if oldStatus != task.Status {
+			return services.SyncParentProgress(tx, task.ParentID)
+		}
+		return nil
+	})
+	if errors.Is(err, services.ErrSubtasksIncomplete) || errors.Is(err, services.ErrTaskBlocked) || errors.Is(err, services.ErrGroupTaskUnfinished) {
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update task")
+		return
+	}
 
-	config.DB.Save(&task)
 	utils.SuccessResponse(c, http.StatusOK, "Task updated successfully", task)
 }
 
+// applyToFutureOccurrences carries an edit of task over to the rest of its series.
+// A new rule or due date starts a new series from task; other edits update the series template
+// and the not yet completed later occurrences.
+func applyToFutureOccurrences(tx *gorm.DB, task *models.Task, rule *utils.RRule, dueDateChanged bool) error {
+	var series models.TaskRecurrence
+	if err := tx.First(&series, *task.RecurrenceID).Error; err != nil {
+		return err
+	}
+
+	if rule != nil || dueDateChanged {
+		if rule == nil {
+			var err error
+			if rule, err = utils.ParseRRule(series.Rule); err != nil {
+				return err
+			}
+			// COUNT keeps counting the occurrences of the original series
+			if rule.Count > 0 {
+				rule.Count -= task.OccurrenceIndex - 1
+				if rule.Count < 1 {
+					rule.Count = 1
+				}
+			}
+		}
+		_, err := services.SplitSeries(tx, task, rule)
+		return err
+	}
+
+	series.Title = task.Title
+	series.Description = task.Description
+	series.Priority = task.Priority
+	if err := tx.Save(&series).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Task{}).
+		Where("recurrence_id = ? AND occurrence_index > ? AND status <> ?", series.ID, task.OccurrenceIndex, models.StatusCompleted).
+		Updates(map[string]interface{}{
+			"title":       task.Title,
+			"description": task.Description,
+			"priority":    task.Priority,
+		}).Error
+}
+
+// DeleteTask moves a task to the trash, from where it can be restored until
+// the retention period ends. For recurring tasks scope=this skips the occurrence
+// (the next one is generated) and scope=future ends the series.
+// Subtasks move up to the task's parent unless children=delete is given.
 func DeleteTask(c *gin.Context) {
 	userID := c.GetUint("userID")
 	taskID := c.Param("id")
+	scope := c.DefaultQuery("scope", ScopeThis)
+	if scope != ScopeThis && scope != ScopeFuture {
+		utils.ErrorResponse(c, http.StatusBadRequest, "scope must be 'this' or 'future'")
+		return
+	}
+	children := c.DefaultQuery("children", services.SubtasksMove)
+	if children != services.SubtasksMove && children != services.SubtasksDelete {
+		utils.ErrorResponse(c, http.StatusBadRequest, "children must be 'move' or 'delete'")
+		return
+	}
 
 	var task models.Task
 	if err := config.DB.Where("id = ? AND creator_id = ?", taskID, userID).First(&task).Error; err != nil {
@@ -112,6 +445,43 @@ func DeleteTask(c *gin.Context) {
 		return
 	}
 
-	config.DB.Delete(&task)
-	utils.SuccessResponse(c, http.StatusOK, "Task deleted successfully", nil)
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if task.RecurrenceID != nil {
+			if scope == ScopeFuture {
+				if err := tx.Model(&models.TaskRecurrence{}).Where("id = ?", *task.RecurrenceID).
+					Update("active", false).Error; err != nil {
+					return err
+				}
+				// later occurrences go to the trash too, so they can be restored with this one
+				var later []models.Task
+				if err := tx.Where("recurrence_id = ? AND occurrence_index > ? AND status <> ?", *task.RecurrenceID, task.OccurrenceIndex, models.StatusCompleted).
+					Find(&later).Error; err != nil {
+					return err
+				}
+				for i := range later {
+					if err := services.TrashTask(tx, &later[i], children); err != nil {
+						return err
+					}
+				}
+			} else if task.Status != models.StatusCompleted {
+				if _, err := services.GenerateNextOccurrence(tx, &task); err != nil {
+					return err
+				}
+			}
+		}
+		// the task goes to the trash; related data is purged with it once retention ends
+		if err := services.TrashTask(tx, &task, children); err != nil {
+			return err
+		}
+		if err := services.LogTaskActivity(tx, &task, userID, models.ActivityTaskDeleted, map[string]interface{}{"title": task.Title}); err != nil {
+			return err
+		}
+		return services.SyncParentProgress(tx, task.ParentID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete task")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Task moved to trash", nil)
 }
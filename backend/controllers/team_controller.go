@@ -0,0 +1,336 @@
+// backend/controllers/team_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TeamRequest struct {
+	Name        string `json:"name" binding:"required,max=64"`
+	Description string `json:"description"`
+	UserIDs     []uint `json:"user_ids"` // اعضای اولیه تیم، فقط هنگام ایجاد
+}
+
+type TeamMembersRequest struct {
+	UserIDs []uint `json:"user_ids" binding:"required"`
+}
+
+type TaskTeamsRequest struct {
+	TeamIDs []uint `json:"team_ids" binding:"required"`
+}
+
+// teamError - تبدیل خطای تیم به پاسخ مناسب
+func teamError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrTeamNameTaken):
+		utils.ErrorResponse(c, http.StatusConflict, "تیمی با این نام در گروه وجود دارد")
+	case errors.Is(err, services.ErrNotGroupMember):
+		utils.ErrorResponse(c, http.StatusBadRequest, "فقط اعضای پذیرفته‌شده گروه به تیم اضافه می‌شوند")
+	case errors.Is(err, services.ErrAlreadyTeamMember):
+		utils.ErrorResponse(c, http.StatusConflict, "این کاربر عضو تیم است")
+	case errors.Is(err, services.ErrNotTeamMember):
+		utils.ErrorResponse(c, http.StatusNotFound, "این کاربر عضو تیم نیست")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, fallback)
+	}
+}
+
+// loadTeam - تیم گروه برای اعضای دارای اجازه manage_members؛ در صورت خطا پاسخ نوشته می‌شود
+func loadTeam(c *gin.Context) (*models.GroupTeam, bool) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه مدیریت تیم‌های این گروه را ندارید")
+	if !ok {
+		return nil, false
+	}
+	var team models.GroupTeam
+	if err := config.DB.Where("group_id = ?", member.GroupID).First(&team, c.Param("team_id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "تیم پیدا نشد")
+		return nil, false
+	}
+	return &team, true
+}
+
+// findGroupTeams - تیم‌های نام‌برده در گروه؛ اگر یکی پیدا نشود پاسخ نوشته می‌شود
+func findGroupTeams(c *gin.Context, groupID uint, teamIDs []uint) ([]models.GroupTeam, bool) {
+	var teams []models.GroupTeam
+	if len(teamIDs) == 0 {
+		return teams, true
+	}
+	if err := config.DB.Where("group_id = ? AND id IN ?", groupID, teamIDs).Find(&teams).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت تیم‌ها")
+		return nil, false
+	}
+	if len(teams) != len(uniqueIDs(teamIDs)) {
+		utils.ErrorResponse(c, http.StatusNotFound, "برخی تیم‌ها در این گروه پیدا نشدند")
+		return nil, false
+	}
+	return teams, true
+}
+
+// uniqueIDs - حذف شناسه‌های تکراری با حفظ ترتیب
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// respondTeam - بازگرداندن تیم همراه با اعضا
+func respondTeam(c *gin.Context, status int, message string, teamID uint) {
+	var team models.GroupTeam
+	config.DB.Preload("Members.User").First(&team, teamID)
+	utils.SuccessResponse(c, status, message, team)
+}
+
+// GetGroupTeams - تیم‌های گروه همراه با اعضا
+func GetGroupTeams(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), "", "")
+	if !ok {
+		return
+	}
+
+	var teams []models.GroupTeam
+	if err := config.DB.Where("group_id = ?", member.GroupID).
+		Preload("Members.User").
+		Order("name ASC").
+		Find(&teams).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت تیم‌ها")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", teams)
+}
+
+// CreateGroupTeam - ایجاد تیم در گروه، در صورت نیاز همراه با اعضای اولیه
+func CreateGroupTeam(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "نام تیم نمی‌تواند خالی باشد")
+		return
+	}
+
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageMembers, "شما اجازه مدیریت تیم‌های این گروه را ندارید")
+	if !ok {
+		return
+	}
+
+	team := models.GroupTeam{
+		GroupID:     member.GroupID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		CreatedBy:   userID,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.EnsureTeamNameFree(tx, &team); err != nil {
+			return err
+		}
+		if err := tx.Create(&team).Error; err != nil {
+			return err
+		}
+		if err := services.LogGroupActivity(tx, team.GroupID, userID, models.ActivityTeamCreated, map[string]interface{}{"name": team.Name}); err != nil {
+			return err
+		}
+		for _, memberID := range uniqueIDs(req.UserIDs) {
+			if _, err := services.AddTeamMember(tx, &team, memberID, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		teamError(c, err, "خطا در ایجاد تیم")
+		return
+	}
+
+	respondTeam(c, http.StatusCreated, "تیم با موفقیت ایجاد شد", team.ID)
+}
+
+// UpdateGroupTeam - ویرایش نام و توضیحات تیم
+func UpdateGroupTeam(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "نام تیم نمی‌تواند خالی باشد")
+		return
+	}
+
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+	before := *team
+	team.Name = strings.TrimSpace(req.Name)
+	team.Description = req.Description
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.EnsureTeamNameFree(tx, team); err != nil {
+			return err
+		}
+		if err := tx.Save(team).Error; err != nil {
+			return err
+		}
+		changes := services.DiffFields(before, *team, "Name", "Description")
+		if len(changes) == 0 {
+			return nil
+		}
+		return services.LogGroupActivity(tx, team.GroupID, userID, models.ActivityTeamUpdated, changes)
+	})
+	if err != nil {
+		teamError(c, err, "خطا در بروزرسانی تیم")
+		return
+	}
+
+	respondTeam(c, http.StatusOK, "تیم با موفقیت بروزرسانی شد", team.ID)
+}
+
+// DeleteGroupTeam - حذف تیم؛ اعضایی که فقط از طریق این تیم تسکی داشتند از تسک‌های باز کنار می‌روند
+func DeleteGroupTeam(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.DeleteTeam(tx, team, userID)
+	})
+	if err != nil {
+		teamError(c, err, "خطا در حذف تیم")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "تیم با موفقیت حذف شد", nil)
+}
+
+// AddTeamMembers - افزودن اعضای گروه به تیم؛ تسک‌های باز تیم به آن‌ها اختصاص داده می‌شوند
+func AddTeamMembers(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req TeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, memberID := range uniqueIDs(req.UserIDs) {
+			if _, err := services.AddTeamMember(tx, team, memberID, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		teamError(c, err, "خطا در افزودن عضو به تیم")
+		return
+	}
+
+	respondTeam(c, http.StatusOK, "اعضا به تیم اضافه شدند", team.ID)
+}
+
+// RemoveTeamMember - حذف عضو از تیم؛ اختصاص‌هایی که فقط از طریق این تیم داشت برداشته می‌شوند
+func RemoveTeamMember(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.RemoveTeamMember(tx, team, StringToUint(c.Param("user_id")), userID)
+	})
+	if err != nil {
+		teamError(c, err, "خطا در حذف عضو از تیم")
+		return
+	}
+
+	respondTeam(c, http.StatusOK, "عضو از تیم حذف شد", team.ID)
+}
+
+// AssignTaskTeams - اختصاص تسک گروهی به تیم‌ها؛ اعضای آینده تیم‌ها هم تا تکمیل تسک آن را می‌گیرند
+func AssignTaskTeams(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req TaskTeamsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, ok := loadGroupTask(c)
+	if !ok {
+		return
+	}
+	teams, ok := findGroupTeams(c, *task.GroupID, req.TeamIDs)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := services.AssignTaskTeams(tx, task, teams, userID)
+		return err
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در اختصاص تسک به تیم‌ها")
+		return
+	}
+
+	respondGroupTask(c, http.StatusOK, "تسک به تیم‌ها اختصاص داده شد", task.ID)
+}
+
+// UnassignTaskTeam - برداشتن تیم از تسک گروهی
+func UnassignTaskTeam(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	task, ok := loadGroupTask(c)
+	if !ok {
+		return
+	}
+	teams, ok := findGroupTeams(c, *task.GroupID, []uint{StringToUint(c.Param("team_id"))})
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := services.UnassignTaskTeam(tx, task, &teams[0], userID)
+		return err
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در برداشتن تیم از تسک")
+		return
+	}
+
+	respondGroupTask(c, http.StatusOK, "تیم از تسک برداشته شد", task.ID)
+}
@@ -0,0 +1,302 @@
+// backend/controllers/template_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxTemplateInstances سقف تعداد تسک‌هایی که در یک درخواست از الگو ساخته می‌شوند
+const maxTemplateInstances = 52
+
+type TemplateRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	GroupID     *uint    `json:"group_id"` // خالی برای الگوی شخصی
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description"`
+	Priority    string   `json:"priority"`
+	DueIn       string   `json:"due_in"` // مثلا 7d یا 48h
+	Checklist   []string `json:"checklist"`
+
+	RequireFiles bool   `json:"require_files"`
+	MaxFiles     int    `json:"max_files" binding:"min=0"`
+	AllowTypes   string `json:"allow_types"`
+
+	EstimateMinutes       *int `json:"estimate_minutes" binding:"omitempty,min=0"`
+	ProgressFromChecklist bool `json:"progress_from_checklist"`
+}
+
+type TemplateInstanceRequest struct {
+	DueDate   *time.Time        `json:"due_date"`
+	StartTime *time.Time        `json:"start_time"`
+	EndTime   *time.Time        `json:"end_time"`
+	UserIDs   []uint            `json:"user_ids"`
+	Vars      map[string]string `json:"vars"`
+}
+
+type InstantiateTemplateRequest struct {
+	GroupID     *uint                     `json:"group_id"`     // برای ساخت تسک گروهی از الگوی شخصی
+	PerAssignee bool                      `json:"per_assignee"` // یک تسک جدا برای هر عضو
+	Instances   []TemplateInstanceRequest `json:"instances"`    // خالی یعنی یک تسک با مقادیر پیش‌فرض
+}
+
+// visibleTemplates - الگوهای شخصی کاربر و الگوهای گروه‌های او
+func visibleTemplates(userID uint) *gorm.DB {
+	return config.DB.Model(&models.TaskTemplate{}).
+		Where("user_id = ? OR group_id IN (?)", userID, acceptedGroupIDs(userID))
+}
+
+// canManageTemplate - الگوی شخصی توسط صاحبش و الگوی گروهی توسط اعضای دارای اجازه edit_settings مدیریت می‌شود
+func canManageTemplate(userID uint, tpl *models.TaskTemplate) bool {
+	if tpl.GroupID == nil {
+		return tpl.UserID != nil && *tpl.UserID == userID
+	}
+	return hasGroupPermission(userID, *tpl.GroupID, models.PermEditSettings)
+}
+
+// loadTemplate - دریافت الگوی قابل مشاهده همراه با چک‌لیست مرتب‌شده
+func loadTemplate(c *gin.Context) (*models.TaskTemplate, bool) {
+	var tpl models.TaskTemplate
+	if err := visibleTemplates(c.GetUint("userID")).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, id ASC")
+		}).
+		First(&tpl, c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "الگو پیدا نشد")
+		return nil, false
+	}
+	return &tpl, true
+}
+
+// bindTemplate - اعتبارسنجی درخواست و انتقال آن به الگو؛ در صورت خطا پاسخ نوشته می‌شود
+func bindTemplate(c *gin.Context, tpl *models.TaskTemplate) (*TemplateRequest, bool) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.Title) == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "نام و عنوان الگو نمی‌توانند خالی باشند")
+		return nil, false
+	}
+	if req.DueIn != "" {
+		if _, err := utils.ParseFriendlyDuration(req.DueIn); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "due_in نامعتبر است")
+			return nil, false
+		}
+	}
+
+	tpl.Name = strings.TrimSpace(req.Name)
+	tpl.Title = req.Title
+	tpl.Description = req.Description
+	tpl.Priority = req.Priority
+	tpl.DueIn = req.DueIn
+	tpl.RequireFiles = req.RequireFiles
+	tpl.MaxFiles = req.MaxFiles
+	tpl.AllowTypes = req.AllowTypes
+	tpl.EstimateMinutes = req.EstimateMinutes
+	tpl.ProgressFromChecklist = req.ProgressFromChecklist
+
+	tpl.Items = tpl.Items[:0]
+	for _, title := range req.Checklist {
+		if title = strings.TrimSpace(title); title != "" {
+			tpl.Items = append(tpl.Items, models.TaskTemplateItem{Position: len(tpl.Items), Title: title})
+		}
+	}
+	return &req, true
+}
+
+// GetTemplates - دریافت الگوهای قابل استفاده برای کاربر
+func GetTemplates(c *gin.Context) {
+	query := visibleTemplates(c.GetUint("userID"))
+	if groupID := c.Query("group_id"); groupID != "" {
+		query = query.Where("group_id = ?", groupID)
+	}
+
+	var templates []models.TaskTemplate
+	if err := query.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC, id ASC")
+	}).Order("name ASC").Find(&templates).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت الگوها")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", templates)
+}
+
+// GetTemplate - دریافت یک الگو
+func GetTemplate(c *gin.Context) {
+	tpl, ok := loadTemplate(c)
+	if !ok {
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "OK", tpl)
+}
+
+// CreateTemplate - ایجاد الگوی شخصی یا گروهی
+func CreateTemplate(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	tpl := models.TaskTemplate{CreatedBy: userID}
+	req, ok := bindTemplate(c, &tpl)
+	if !ok {
+		return
+	}
+	tpl.GroupID = req.GroupID
+	if tpl.GroupID == nil {
+		tpl.UserID = &userID
+	}
+	if !canManageTemplate(userID, &tpl) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه ایجاد الگو در این گروه را ندارید")
+		return
+	}
+
+	if err := config.DB.Create(&tpl).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ایجاد الگو")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "الگو با موفقیت ایجاد شد", tpl)
+}
+
+// UpdateTemplate - جایگزینی مشخصات و چک‌لیست الگو
+func UpdateTemplate(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	tpl, ok := loadTemplate(c)
+	if !ok {
+		return
+	}
+	if !canManageTemplate(userID, tpl) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه ویرایش این الگو را ندارید")
+		return
+	}
+	if _, ok := bindTemplate(c, tpl); !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", tpl.ID).Delete(&models.TaskTemplateItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(tpl).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بروزرسانی الگو")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "الگو با موفقیت بروزرسانی شد", tpl)
+}
+
+// DeleteTemplate - حذف الگو؛ تسک‌های ساخته‌شده از آن دست‌نخورده می‌مانند
+func DeleteTemplate(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	tpl, ok := loadTemplate(c)
+	if !ok {
+		return
+	}
+	if !canManageTemplate(userID, tpl) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه حذف این الگو را ندارید")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", tpl.ID).Delete(&models.TaskTemplateItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tpl).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف الگو")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "الگو با موفقیت حذف شد", nil)
+}
+
+// InstantiateTemplate - ساخت یک یا چند تسک از الگو؛ همه تسک‌ها در یک تراکنش ساخته می‌شوند یا هیچ‌کدام
+func InstantiateTemplate(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req InstantiateTemplateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if len(req.Instances) > maxTemplateInstances {
+		utils.ErrorResponse(c, http.StatusBadRequest, "تعداد تسک‌های درخواستی بیش از حد مجاز است")
+		return
+	}
+
+	tpl, ok := loadTemplate(c)
+	if !ok {
+		return
+	}
+
+	// الگوی گروهی همیشه در گروه خودش و الگوی شخصی به انتخاب کاربر در یک گروه یا به صورت شخصی
+	target := services.TemplateTarget{GroupID: req.GroupID, PerAssignee: req.PerAssignee}
+	if tpl.GroupID != nil {
+		if req.GroupID != nil && *req.GroupID != *tpl.GroupID {
+			utils.ErrorResponse(c, http.StatusBadRequest, "الگوی گروهی فقط در گروه خودش قابل استفاده است")
+			return
+		}
+		target.GroupID = tpl.GroupID
+	} else if !canManageTemplate(userID, tpl) {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه استفاده از این الگو را ندارید")
+		return
+	}
+	if target.GroupID != nil {
+		if _, ok := authorizeGroup(c, *target.GroupID, models.PermManageTasks, "شما اجازه ایجاد تسک در این گروه را ندارید"); !ok {
+			return
+		}
+	}
+
+	instances := make([]services.TemplateInstance, 0, len(req.Instances))
+	for _, instance := range req.Instances {
+		instances = append(instances, services.TemplateInstance{
+			DueDate:   instance.DueDate,
+			StartTime: instance.StartTime,
+			EndTime:   instance.EndTime,
+			UserIDs:   instance.UserIDs,
+			Vars:      instance.Vars,
+		})
+	}
+	if len(instances) == 0 {
+		instances = append(instances, services.TemplateInstance{})
+	}
+
+	var tasks []models.Task
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		tasks, err = services.InstantiateTemplate(tx, tpl, target, instances, userID)
+		return err
+	})
+	if errors.Is(err, services.ErrGroupArchived) {
+		utils.ErrorResponse(c, http.StatusForbidden, "این گروه بایگانی شده و فقط خواندنی است")
+		return
+	}
+	if errors.Is(err, services.ErrNoAssignees) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "هیچ عضو پذیرفته‌شده‌ای برای اختصاص تسک پیدا نشد")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ساخت تسک از الگو")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "تسک‌ها از الگو ساخته شدند", tasks)
+}
@@ -0,0 +1,258 @@
+// backend/controllers/time_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type StartTimerRequest struct {
+	Note string `json:"note"`
+}
+
+type CreateTimeEntryRequest struct {
+	StartedAt time.Time  `json:"started_at" binding:"required"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Minutes   int        `json:"minutes" binding:"min=0"` // در نبود ended_at
+	Note      string     `json:"note"`
+}
+
+type UpdateTimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      *string    `json:"note"`
+}
+
+var timeEntryListSpec = utils.ListSpec{
+	Table: "time_entries",
+	Sortable: map[string]string{
+		"started_at": "time_entries.started_at",
+		"seconds":    "time_entries.seconds",
+	},
+	DefaultSort: "-started_at",
+	Searchable:  []string{"time_entries.note"},
+	Filters: map[string]string{
+		"user_id": "time_entries.user_id",
+	},
+	DateColumn: "time_entries.started_at",
+}
+
+// timerError - تبدیل خطاهای تایمر به پاسخ مناسب
+func timerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTimerRunning):
+		utils.ErrorResponse(c, http.StatusConflict, "یک تایمر دیگر در حال اجراست؛ ابتدا آن را متوقف کنید")
+	case errors.Is(err, services.ErrNoActiveTimer):
+		utils.ErrorResponse(c, http.StatusNotFound, "تایمر فعالی وجود ندارد")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ثبت زمان")
+	}
+}
+
+// loadOwnTimeEntry - دریافت رکورد زمان متعلق به کاربر؛ در صورت خطا پاسخ نوشته می‌شود
+func loadOwnTimeEntry(c *gin.Context) (*models.TimeEntry, bool) {
+	var entry models.TimeEntry
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).First(&entry).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "رکورد زمان پیدا نشد")
+		return nil, false
+	}
+	return &entry, true
+}
+
+// StartTimer - شروع تایمر روی تسک؛ هر کاربر فقط یک تایمر فعال دارد
+func StartTimer(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req StartTimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	entry, err := services.StartTimer(config.DB, userID, task.ID, req.Note)
+	if err != nil {
+		timerError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "تایمر شروع شد", entry)
+}
+
+// StopTimer - توقف تایمر فعال کاربر
+func StopTimer(c *gin.Context) {
+	entry, err := services.StopTimer(config.DB, c.GetUint("userID"))
+	if err != nil {
+		timerError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "تایمر متوقف شد", entry)
+}
+
+// GetActiveTimer - دریافت تایمر در حال اجرای کاربر
+func GetActiveTimer(c *gin.Context) {
+	entry, err := services.ActiveTimer(config.DB, c.GetUint("userID"))
+	if err != nil {
+		timerError(c, err)
+		return
+	}
+
+	config.DB.Preload("Task").First(entry, entry.ID)
+	utils.SuccessResponse(c, http.StatusOK, "OK", entry)
+}
+
+// GetTaskTimeEntries - دریافت زمان‌های ثبت‌شده روی تسک
+func GetTaskTimeEntries(c *gin.Context) {
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	list, err := utils.ParseListQuery(c, timeEntryListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var entries []models.TimeEntry
+	query := config.DB.Model(&models.TimeEntry{}).Where("time_entries.task_id = ?", task.ID)
+	if err := list.Find(query, &entries, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("User")
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت زمان‌ها")
+		return
+	}
+
+	utils.ListResponse(c, entries, list)
+}
+
+// validTimeRange - بازه زمان ثبت‌شده باید مثبت باشد و در آینده تمام نشود؛ در صورت خطا پاسخ نوشته می‌شود
+func validTimeRange(c *gin.Context, startedAt, endedAt time.Time) bool {
+	if !endedAt.After(startedAt) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "زمان پایان باید بعد از زمان شروع باشد")
+		return false
+	}
+	if endedAt.After(time.Now()) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "زمان پایان نمی‌تواند در آینده باشد")
+		return false
+	}
+	return true
+}
+
+// CreateTimeEntry - ثبت دستی زمان با بازه شروع/پایان یا تعداد دقیقه
+func CreateTimeEntry(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var req CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	endedAt := req.EndedAt
+	if endedAt == nil {
+		if req.Minutes == 0 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "ended_at یا minutes الزامی است")
+			return
+		}
+		end := req.StartedAt.Add(time.Duration(req.Minutes) * time.Minute)
+		endedAt = &end
+	}
+	if !validTimeRange(c, req.StartedAt, *endedAt) {
+		return
+	}
+
+	task, ok := loadAccessibleTask(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	entry := models.TimeEntry{
+		TaskID:    task.ID,
+		UserID:    userID,
+		StartedAt: req.StartedAt,
+		EndedAt:   endedAt,
+		Seconds:   int64(endedAt.Sub(req.StartedAt) / time.Second),
+		Note:      req.Note,
+		Manual:    true,
+	}
+	if err := config.DB.Create(&entry).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ثبت زمان")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "زمان با موفقیت ثبت شد", entry)
+}
+
+// UpdateTimeEntry - ویرایش رکورد زمان متوقف‌شده توسط صاحب آن
+func UpdateTimeEntry(c *gin.Context) {
+	var req UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entry, ok := loadOwnTimeEntry(c)
+	if !ok {
+		return
+	}
+
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+	if entry.EndedAt == nil {
+		if req.StartedAt != nil || req.EndedAt != nil {
+			utils.ErrorResponse(c, http.StatusConflict, "زمان تایمر در حال اجرا قابل ویرایش نیست")
+			return
+		}
+	} else {
+		if req.StartedAt != nil {
+			entry.StartedAt = *req.StartedAt
+		}
+		if req.EndedAt != nil {
+			entry.EndedAt = req.EndedAt
+		}
+		if !validTimeRange(c, entry.StartedAt, *entry.EndedAt) {
+			return
+		}
+		entry.Seconds = int64(entry.EndedAt.Sub(entry.StartedAt) / time.Second)
+	}
+
+	if err := config.DB.Save(entry).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در ویرایش زمان")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "زمان با موفقیت ویرایش شد", entry)
+}
+
+// DeleteTimeEntry - حذف رکورد زمان (یا لغو تایمر در حال اجرا) توسط صاحب آن
+func DeleteTimeEntry(c *gin.Context) {
+	entry, ok := loadOwnTimeEntry(c)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Delete(entry).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در حذف زمان")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "زمان با موفقیت حذف شد", nil)
+}
@@ -0,0 +1,217 @@
+// backend/controllers/trash_controller.go
+
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashView محتوای سطل زباله؛ آیتم‌ها RetentionDays روز پس از حذف برای همیشه پاک می‌شوند
+type TrashView struct {
+	Tasks         []models.Task  `json:"tasks"`
+	Groups        []models.Group `json:"groups,omitempty"`
+	Files         []models.File  `json:"files"`
+	RetentionDays int            `json:"retention_days"`
+}
+
+func newTrashView() *TrashView {
+	return &TrashView{
+		Tasks:         []models.Task{},
+		Files:         []models.File{},
+		RetentionDays: int(services.TrashRetention().Hours() / 24),
+	}
+}
+
+// trashed - کوئری رکوردهای حذف‌شده
+func trashed(model interface{}) *gorm.DB {
+	return config.DB.Unscoped().Model(model).Where("deleted_at IS NOT NULL")
+}
+
+// restoreError - تبدیل خطای بازگردانی به پاسخ مناسب
+func restoreError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrParentTrashed) {
+		utils.ErrorResponse(c, http.StatusConflict, "ابتدا گروه یا تسک مربوط به این آیتم را بازگردانی کنید")
+		return
+	}
+	utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بازگردانی")
+}
+
+// GetTrash - سطل زباله کاربر: تسک‌های شخصی، گروه‌هایی که اجازه حذفشان را دارد و فایل‌های او
+func GetTrash(c *gin.Context) {
+	userID := c.GetUint("userID")
+	view := newTrashView()
+
+	if err := trashed(&models.Task{}).
+		Where("creator_id = ? AND is_group_task = ?", userID, false).
+		Order("deleted_at DESC").
+		Find(&view.Tasks).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت سطل زباله")
+		return
+	}
+
+	// گروه‌هایی که نقش کاربر در آن‌ها اجازه حذف و بازگردانی گروه را دارد
+	var memberships []models.GroupMember
+	if err := config.DB.Where("user_id = ? AND accepted = ?", userID, true).Find(&memberships).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت سطل زباله")
+		return
+	}
+	adminOf := []uint{0}
+	for _, membership := range memberships {
+		perms, err := services.RolePermissions(config.DB, membership.GroupID, membership.Role)
+		if err == nil && perms[models.PermDeleteGroup] {
+			adminOf = append(adminOf, membership.GroupID)
+		}
+	}
+	view.Groups = []models.Group{}
+	if err := trashed(&models.Group{}).
+		Where("id IN ?", adminOf).
+		Order("deleted_at DESC").
+		Find(&view.Groups).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت سطل زباله")
+		return
+	}
+
+	if err := trashed(&models.File{}).
+		Where("user_id = ?", userID).
+		Order("deleted_at DESC").
+		Find(&view.Files).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت سطل زباله")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", view)
+}
+
+// GetGroupTrash - تسک‌ها و فایل‌های حذف‌شده گروه؛ فقط برای اعضای دارای اجازه manage_tasks
+func GetGroupTrash(c *gin.Context) {
+	member, ok := authorizeGroup(c, StringToUint(c.Param("id")), models.PermManageTasks, "شما اجازه مشاهده سطل زباله این گروه را ندارید")
+	if !ok {
+		return
+	}
+	view := newTrashView()
+
+	if err := trashed(&models.Task{}).
+		Where("group_id = ?", member.GroupID).
+		Order("deleted_at DESC").
+		Find(&view.Tasks).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت سطل زباله")
+		return
+	}
+
+	groupTasks := config.DB.Model(&models.Task{}).Select("id").Where("group_id = ?", member.GroupID)
+	if err := trashed(&models.File{}).
+		Where("task_id IN (?)", groupTasks).
+		Order("deleted_at DESC").
+		Preload("User").
+		Find(&view.Files).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در دریافت سطل زباله")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "OK", view)
+}
+
+// RestoreTask - بازگردانی تسک به همراه اختصاص‌ها، پیشرفت، فایل‌ها و زیرتسک‌هایی که با آن حذف شده بودند
+// تسک شخصی توسط سازنده و تسک گروهی توسط مدیران گروه بازگردانی می‌شود
+func RestoreTask(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var task models.Task
+	if err := trashed(&models.Task{}).Where("id = ?", c.Param("id")).First(&task).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "تسک در سطل زباله پیدا نشد")
+		return
+	}
+	allowed := task.CreatorID == userID && !task.IsGroupTask
+	if task.IsGroupTask && task.GroupID != nil {
+		allowed = hasGroupPermission(userID, *task.GroupID, models.PermManageTasks)
+	}
+	if !allowed {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه بازگردانی این تسک را ندارید")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.RestoreTask(tx, &task, userID)
+	})
+	if err != nil {
+		restoreError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "تسک بازگردانی شد", task)
+}
+
+// RestoreGroup - بازگردانی گروه و تسک‌هایی که همراه آن حذف شده بودند
+func RestoreGroup(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var group models.Group
+	if err := trashed(&models.Group{}).Where("id = ?", c.Param("id")).First(&group).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "گروه در سطل زباله پیدا نشد")
+		return
+	}
+	// گروه در سطل زباله است، پس فقط عضویت و اجازه بررسی می‌شود
+	if _, err := services.AuthorizeMember(config.DB, group.ID, userID, models.PermDeleteGroup); err != nil {
+		if errors.Is(err, services.ErrForbidden) {
+			utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه بازگردانی این گروه را ندارید")
+		} else {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "خطا در بررسی دسترسی")
+		}
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.RestoreGroup(tx, &group, userID)
+	})
+	if err != nil {
+		restoreError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "گروه بازگردانی شد", group)
+}
+
+// RestoreFile - بازگردانی فایل توسط صاحب فایل یا سازنده تسک
+func RestoreFile(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var file models.File
+	if err := trashed(&models.File{}).Where("id = ?", c.Param("id")).First(&file).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "فایل در سطل زباله پیدا نشد")
+		return
+	}
+
+	var task models.Task
+	if err := config.DB.First(&task, file.TaskID).Error; err != nil {
+		restoreError(c, services.ErrParentTrashed)
+		return
+	}
+	if file.UserID != userID && task.CreatorID != userID {
+		utils.ErrorResponse(c, http.StatusForbidden, "شما اجازه بازگردانی این فایل را ندارید")
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.RestoreFile(tx, &file); err != nil {
+			return err
+		}
+		return services.LogTaskActivity(tx, &task, userID, models.ActivityFileRestored, map[string]interface{}{
+			"file_id":  file.ID,
+			"filename": file.Filename,
+		})
+	})
+	if err != nil {
+		restoreError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "فایل بازگردانی شد", file)
+}
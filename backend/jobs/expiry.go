@@ -0,0 +1,105 @@
+// backend/jobs/expiry.go
+
+package jobs
+
+import (
+	"fmt"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExpiryJob تسک‌های معوق (pending/in_progress) را پس از گذشت مهلت و زمان ارفاق به expired می‌برد
+// مهلت تسک due_date و در نبود آن end_time است
+type ExpiryJob struct {
+	PersonalGrace time.Duration
+	GroupGrace    time.Duration
+	BatchSize     int
+}
+
+// NewExpiryJob - تنظیمات از EXPIRY_GRACE_PERIOD و GROUP_EXPIRY_GRACE_PERIOD خوانده می‌شود
+func NewExpiryJob() *ExpiryJob {
+	personal := utils.GetEnvDuration("EXPIRY_GRACE_PERIOD", 0)
+	return &ExpiryJob{
+		PersonalGrace: personal,
+		GroupGrace:    utils.GetEnvDuration("GROUP_EXPIRY_GRACE_PERIOD", personal),
+		BatchSize:     100,
+	}
+}
+
+func (j *ExpiryJob) Run() error {
+	now := time.Now()
+	open := services.OpenStatuses
+
+	var tasks []models.Task
+	if err := config.DB.
+		Where("status IN ?", open).
+		Where("group_id IS NULL OR group_id NOT IN (?)", services.ArchivedGroupIDs(config.DB)).
+		Where("(is_group_task = ? AND COALESCE(due_date, end_time) < ?) OR (is_group_task = ? AND COALESCE(due_date, end_time) < ?)",
+			false, now.Add(-j.PersonalGrace), true, now.Add(-j.GroupGrace)).
+		Limit(j.BatchSize).
+		Find(&tasks).Error; err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		if err := config.DB.Transaction(func(tx *gorm.DB) error {
+			return expireTask(tx, &tasks[i], open)
+		}); err != nil {
+			utils.LogErrorWithDetails("Failed to expire task", err, tasks[i].ID)
+		}
+	}
+
+	if len(tasks) > 0 {
+		utils.LogInfo("Expired overdue tasks", "count", len(tasks))
+	}
+	return nil
+}
+
+func expireTask(tx *gorm.DB, task *models.Task, open []models.TaskStatus) error {
+	// شرط وضعیت از تغییر هم‌زمان (مثلا تکمیل تسک در همین لحظه) جلوگیری می‌کند
+	result := tx.Model(&models.Task{}).
+		Where("id = ? AND status IN ?", task.ID, open).
+		Update("status", models.StatusExpired)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	from := task.Status
+	task.Status = models.StatusExpired
+	if err := services.RecordTransition(tx, task.ID, from, models.StatusExpired, "deadline passed", nil); err != nil {
+		return err
+	}
+	if err := services.LogStatusChange(tx, task, 0, from, models.StatusExpired); err != nil {
+		return err
+	}
+
+	recipients := []uint{task.CreatorID}
+	if task.IsGroupTask && task.GroupID != nil {
+		assignees, err := services.TaskAssigneeIDs(tx, task.ID)
+		if err != nil {
+			return err
+		}
+		admins, err := services.GroupAdminIDs(tx, *task.GroupID)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, assignees...)
+		recipients = append(recipients, admins...)
+	}
+
+	if err := services.Notify(tx, recipients, models.NotificationTaskExpired,
+		"مهلت تسک به پایان رسید",
+		fmt.Sprintf("مهلت تسک «%s» به پایان رسید و وضعیت آن منقضی شد", task.Title),
+		task.ID); err != nil {
+		return err
+	}
+
+	// سری تکرارشونده با رخداد منقضی‌شده متوقف نمی‌شود
+	_, err := services.GenerateNextOccurrence(tx, task)
+	return err
+}
@@ -0,0 +1,59 @@
+// backend/jobs/invitations.go
+
+package jobs
+
+import (
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvitationExpiryJob دعوت‌های pending را پس از ExpiresAt منقضی و عضویت در انتظارشان را حذف می‌کند
+// عضویت‌های در انتظار قدیمی که دعوتی ندارند هم با BackfillInvitations مشمول انقضا می‌شوند
+type InvitationExpiryJob struct {
+	BatchSize int
+}
+
+func NewInvitationExpiryJob() *InvitationExpiryJob {
+	return &InvitationExpiryJob{BatchSize: 100}
+}
+
+func (j *InvitationExpiryJob) Run() error {
+	// عضویت‌های در انتظار بدون دعوت ابتدا دعوت می‌گیرند تا همراه بقیه منقضی شوند
+	backfilled, err := services.BackfillInvitations(config.DB, j.BatchSize)
+	if err != nil {
+		return err
+	}
+	if backfilled > 0 {
+		utils.LogInfo("Backfilled invitations for pending memberships", "count", backfilled)
+	}
+
+	var invitations []models.GroupInvitation
+	if err := config.DB.
+		Where("status = ? AND expires_at <= ?", models.InvitationPending, time.Now()).
+		Order("id ASC").
+		Limit(j.BatchSize).
+		Find(&invitations).Error; err != nil {
+		return err
+	}
+
+	expired := 0
+	for i := range invitations {
+		if err := config.DB.Transaction(func(tx *gorm.DB) error {
+			return services.ExpireInvitation(tx, &invitations[i])
+		}); err != nil {
+			utils.LogErrorWithDetails("Failed to expire invitation", err, invitations[i].ID)
+			continue
+		}
+		expired++
+	}
+
+	if expired > 0 {
+		utils.LogInfo("Expired group invitations", "count", expired)
+	}
+	return nil
+}
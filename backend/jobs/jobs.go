@@ -0,0 +1,28 @@
+// backend/jobs/jobs.go
+
+package jobs
+
+import (
+	"task-manager/utils"
+	"time"
+)
+
+// Start - ثبت و اجرای job های پس‌زمینه؛ از main.go فراخوانی می‌شود
+func Start() *Scheduler {
+	s := NewScheduler()
+
+	expiry := NewExpiryJob()
+	s.Every("expire-overdue-tasks", utils.GetEnvPositiveDuration("EXPIRY_CHECK_INTERVAL", 5*time.Minute), expiry.Run)
+
+	reminders := NewReminderJob()
+	s.Every("deliver-reminders", utils.GetEnvPositiveDuration("REMINDER_CHECK_INTERVAL", time.Minute), reminders.Run)
+
+	trash := NewTrashPurgeJob()
+	s.Every("purge-trash", utils.GetEnvPositiveDuration("TRASH_PURGE_INTERVAL", time.Hour), trash.Run)
+
+	invitations := NewInvitationExpiryJob()
+	s.Every("expire-invitations", utils.GetEnvPositiveDuration("INVITATION_EXPIRY_INTERVAL", time.Hour), invitations.Run)
+
+	s.Start()
+	return s
+}
@@ -0,0 +1,90 @@
+// backend/jobs/reminders.go
+
+package jobs
+
+import (
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReminderJob صف یادآوری‌ها را برای تسک‌هایی که مهلتشان نزدیک است پر می‌کند و یادآوری‌های سررسیده را ارسال می‌کند
+// صف در دیتابیس نگه داشته می‌شود، پس یادآوری‌هایی که هنگام خاموش بودن سرور سررسید شده‌اند در اجرای بعدی ارسال می‌شوند
+type ReminderJob struct {
+	BatchSize int
+}
+
+func NewReminderJob() *ReminderJob {
+	return &ReminderJob{BatchSize: 100}
+}
+
+func (j *ReminderJob) Run() error {
+	if err := j.enqueue(); err != nil {
+		return err
+	}
+	return j.deliver()
+}
+
+// enqueue - ساخت یادآوری برای تسک‌های بازی که مهلتشان در بازه بزرگ‌ترین فاصله یادآوری است
+func (j *ReminderJob) enqueue() error {
+	// یادآوری تسک‌هایی که بسته شده‌اند دیگر ارسال نمی‌شود
+	closed := config.DB.Model(&models.Task{}).Select("id").Where("status NOT IN ?", services.OpenStatuses)
+	if err := config.DB.Model(&models.Reminder{}).
+		Where("status = ? AND task_id IN (?)", models.ReminderPending, closed).
+		Update("status", models.ReminderCancelled).Error; err != nil {
+		return err
+	}
+
+	horizon, err := services.MaxReminderLead(config.DB)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	var tasks []models.Task
+	if err := config.DB.
+		Where("status IN ?", services.OpenStatuses).
+		Where("group_id IS NULL OR group_id NOT IN (?)", services.ArchivedGroupIDs(config.DB)).
+		Where(services.DeadlineExpr+" > ? AND "+services.DeadlineExpr+" <= ?", now, now.Add(horizon)).
+		Find(&tasks).Error; err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		if err := config.DB.Transaction(func(tx *gorm.DB) error {
+			return services.SyncTaskReminders(tx, &tasks[i])
+		}); err != nil {
+			utils.LogErrorWithDetails("Failed to sync task reminders", err, tasks[i].ID)
+		}
+	}
+	return nil
+}
+
+// deliver - ارسال یادآوری‌های سررسیده به صورت دسته‌ای
+func (j *ReminderJob) deliver() error {
+	var reminders []models.Reminder
+	if err := config.DB.
+		Where("status = ? AND remind_at <= ?", models.ReminderPending, time.Now()).
+		Order("remind_at ASC").
+		Limit(j.BatchSize).
+		Find(&reminders).Error; err != nil {
+		return err
+	}
+
+	for i := range reminders {
+		if err := config.DB.Transaction(func(tx *gorm.DB) error {
+			return services.DeliverReminder(tx, &reminders[i])
+		}); err != nil {
+			utils.LogErrorWithDetails("Failed to deliver reminder", err, reminders[i].ID)
+		}
+	}
+
+	if len(reminders) > 0 {
+		utils.LogInfo("Processed due reminders", "count", len(reminders))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// backend/jobs/scheduler.go
+
+package jobs
+
+import (
+	"fmt"
+	"sync"
+	"task-manager/utils"
+	"time"
+)
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func() error
+}
+
+// Scheduler زمان‌بند ساده درون‌برنامه‌ای؛ هر job در goroutine جداگانه و با فاصله ثابت اجرا می‌شود
+// اجراهای یک job هیچ‌وقت هم‌پوشانی ندارند
+type Scheduler struct {
+	jobs []job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{stop: make(chan struct{})}
+}
+
+// Every - ثبت job؛ باید قبل از Start فراخوانی شود
+// فاصله صفر یا منفی باعث panic در time.NewTicker می‌شود، پس چنین job ای ثبت نمی‌شود
+func (s *Scheduler) Every(name string, interval time.Duration, run func() error) {
+	if interval <= 0 {
+		utils.LogError("Scheduled job has non-positive interval, skipping", fmt.Errorf("%s: %s", name, interval))
+		return
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Start - اجرای تمام job ها؛ هر job یک بار بلافاصله و سپس در هر interval اجرا می‌شود
+func (s *Scheduler) Start() {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+	utils.LogInfo("Scheduler started", "jobs", len(s.jobs))
+}
+
+// Stop - توقف job ها و انتظار برای پایان اجرای جاری
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(j)
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			utils.LogError("Scheduled job panicked", fmt.Errorf("%s: %v", j.name, r))
+		}
+	}()
+
+	if err := j.run(); err != nil {
+		utils.LogErrorWithDetails("Scheduled job failed", err, j.name)
+	}
+}
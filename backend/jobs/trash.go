@@ -0,0 +1,108 @@
+// backend/jobs/trash.go
+
+package jobs
+
+import (
+	"errors"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrashPurgeJob آیتم‌هایی را که بیش از دوره نگهداری در سطل زباله مانده‌اند برای همیشه پاک می‌کند
+// هر آیتم در تراکنش جداگانه پاک می‌شود و فایل‌های روی دیسک پس از commit حذف می‌شوند
+type TrashPurgeJob struct {
+	Retention time.Duration
+	BatchSize int
+}
+
+// NewTrashPurgeJob - دوره نگهداری از TRASH_RETENTION خوانده می‌شود
+func NewTrashPurgeJob() *TrashPurgeJob {
+	return &TrashPurgeJob{Retention: services.TrashRetention(), BatchSize: 100}
+}
+
+func (j *TrashPurgeJob) Run() error {
+	cutoff := time.Now().Add(-j.Retention)
+	purged := 0
+
+	// گروه‌ها اول، چون تسک‌ها و فایل‌هایشان را هم با خود پاک می‌کنند
+	var groups []models.Group
+	if err := config.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Limit(j.BatchSize).
+		Find(&groups).Error; err != nil {
+		return err
+	}
+	for i := range groups {
+		if j.purge(func(tx *gorm.DB) ([]string, error) {
+			return services.PurgeGroup(tx, &groups[i])
+		}, "group", groups[i].ID) {
+			purged++
+		}
+	}
+
+	var taskIDs []uint
+	if err := config.DB.Unscoped().Model(&models.Task{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("id ASC").
+		Limit(j.BatchSize).
+		Pluck("id", &taskIDs).Error; err != nil {
+		return err
+	}
+	for _, taskID := range taskIDs {
+		taskID := taskID
+		if j.purge(func(tx *gorm.DB) ([]string, error) {
+			// ممکن است همراه والدش پاک شده باشد
+			var task models.Task
+			if err := tx.Unscoped().First(&task, taskID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, nil
+				}
+				return nil, err
+			}
+			return services.PurgeTask(tx, &task)
+		}, "task", taskID) {
+			purged++
+		}
+	}
+
+	var files []models.File
+	if err := config.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Limit(j.BatchSize).
+		Find(&files).Error; err != nil {
+		return err
+	}
+	for i := range files {
+		if j.purge(func(tx *gorm.DB) ([]string, error) {
+			return []string{files[i].Filepath}, tx.Unscoped().Delete(&files[i]).Error
+		}, "file", files[i].ID) {
+			purged++
+		}
+	}
+
+	if purged > 0 {
+		utils.LogInfo("Purged expired trash items", "count", purged)
+	}
+	return nil
+}
+
+// purge - اجرای پاک‌سازی در تراکنش و حذف فایل‌های فیزیکی پس از commit
+func (j *TrashPurgeJob) purge(run func(tx *gorm.DB) ([]string, error), kind string, id uint) bool {
+	var paths []string
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		paths, err = run(tx)
+		return err
+	})
+	if err != nil {
+		utils.LogErrorWithDetails("Failed to purge trashed "+kind, err, id)
+		return false
+	}
+	services.RemoveStoredFiles(paths)
+	return true
+}
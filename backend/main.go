@@ -5,7 +5,9 @@ import (
 	"log"
 	"os"
 	"task-manager/config"
+	"task-manager/jobs"
 	"task-manager/routes"
+	"task-manager/search"
 	"task-manager/utils"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +23,17 @@ func main() {
 	// Initialize database
 	config.ConnectDatabase()
 
+	// Full-text search
+	searchIndex := search.NewMySQLIndex(config.DB)
+	if err := searchIndex.EnsureIndexes(); err != nil {
+		utils.LogError("Failed to create full-text indexes", err)
+	}
+	search.SetIndex(searchIndex)
+
+	// Background jobs
+	scheduler := jobs.Start()
+	defer scheduler.Stop()
+
 	// Set Gin mode
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
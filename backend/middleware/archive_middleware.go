@@ -0,0 +1,119 @@
+// backend/middleware/archive_middleware.go
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"task-manager/config"
+	"task-manager/models"
+	"task-manager/services"
+	"task-manager/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// archiveExempt مسیرهایی که روی گروه بایگانی‌شده هم مجازند
+var archiveExempt = map[string]bool{
+	"POST /api/groups/:id/archive":   true,
+	"DELETE /api/groups/:id/archive": true,
+	"DELETE /api/groups/:id":         true,
+
+	// عضویت و مالکیت گروه بایگانی‌شده همچنان قابل تغییر است
+	"POST /api/groups/:id/reject-invitation": true,
+	"DELETE /api/groups/:id/join-requests":   true,
+	"POST /api/groups/:id/leave":             true,
+	"POST /api/groups/:id/transfer":          true,
+	"DELETE /api/groups/:id/transfer":        true,
+	"POST /api/groups/:id/transfer/accept":   true,
+	"POST /api/groups/:id/transfer/decline":  true,
+}
+
+// ArchivedGroupGuard - رد درخواست‌های تغییر روی گروه بایگانی‌شده، تسک‌ها، فایل‌ها، برچسب‌ها و زمان‌های ثبت‌شده آن
+// درخواست‌های خواندنی همیشه عبور می‌کنند
+func ArchivedGroupGuard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		groupID, ok := archiveTarget(c)
+		if !ok {
+			c.Next()
+			return
+		}
+		if err := services.EnsureGroupWritable(config.DB, groupID); errors.Is(err, services.ErrGroupArchived) {
+			utils.ErrorResponse(c, http.StatusForbidden, "این گروه بایگانی شده و فقط خواندنی است")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// archiveTarget - گروهی که درخواست روی آن تغییر ایجاد می‌کند
+func archiveTarget(c *gin.Context) (uint, bool) {
+	path := c.FullPath()
+	if archiveExempt[c.Request.Method+" "+path] {
+		return 0, false
+	}
+
+	switch {
+	case strings.HasPrefix(path, "/api/groups/:id"):
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		return uint(id), err == nil
+	case strings.HasPrefix(path, "/api/tasks/:id"):
+		return taskGroup(c.Param("id"))
+	case path == "/api/files/:id":
+		return fileGroup(config.DB, c.Param("id"))
+	case path == "/api/trash/files/:id/restore":
+		return fileGroup(config.DB.Unscoped(), c.Param("id"))
+	case path == "/api/trash/tasks/:id/restore":
+		return taskGroupIn(config.DB.Unscoped(), c.Param("id"))
+	case path == "/api/time-entries/:id":
+		var entry models.TimeEntry
+		if err := config.DB.First(&entry, c.Param("id")).Error; err != nil {
+			return 0, false
+		}
+		return taskGroup(strconv.FormatUint(uint64(entry.TaskID), 10))
+	case path == "/api/timer/stop":
+		var entry models.TimeEntry
+		if err := config.DB.Where("active_user_id = ?", c.GetUint("userID")).First(&entry).Error; err != nil {
+			return 0, false
+		}
+		return taskGroup(strconv.FormatUint(uint64(entry.TaskID), 10))
+	case path == "/api/labels/:id":
+		var label models.Label
+		if err := config.DB.First(&label, c.Param("id")).Error; err != nil || label.GroupID == nil {
+			return 0, false
+		}
+		return *label.GroupID, true
+	}
+	return 0, false
+}
+
+func taskGroup(taskID string) (uint, bool) {
+	return taskGroupIn(config.DB, taskID)
+}
+
+// taskGroupIn - گروه تسک گروهی؛ db می‌تواند Unscoped باشد تا تسک‌های سطل زباله هم پیدا شوند
+func taskGroupIn(db *gorm.DB, taskID string) (uint, bool) {
+	var task models.Task
+	if err := db.First(&task, taskID).Error; err != nil || !task.IsGroupTask || task.GroupID == nil {
+		return 0, false
+	}
+	return *task.GroupID, true
+}
+
+func fileGroup(db *gorm.DB, fileID string) (uint, bool) {
+	var file models.File
+	if err := db.First(&file, fileID).Error; err != nil {
+		return 0, false
+	}
+	return taskGroupIn(db, strconv.FormatUint(uint64(file.TaskID), 10))
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Activity actions
+const (
+	ActivityTaskCreated     = "task_created"
+	ActivityTaskUpdated     = "task_updated" // Details: لیست تغییرات فیلدها
+	ActivityTaskDeleted     = "task_deleted"
+	ActivityTaskRestored    = "task_restored"
+	ActivityStatusChanged   = "status_changed"
+	ActivityProgressUpdated = "progress_updated"
+	ActivityFileUploaded    = "file_uploaded"
+	ActivityFileApproved    = "file_approved"
+	ActivityFileDeleted     = "file_deleted"
+	ActivityFileRestored    = "file_restored"
+	ActivityMemberInvited   = "member_invited"
+	ActivityMemberJoined    = "member_joined"
+	ActivityMemberRemoved   = "member_removed"
+	ActivityMemberLeft      = "member_left"
+	ActivityInviteCreated   = "invite_created"
+	ActivityInviteRevoked   = "invite_revoked"
+	ActivityRoleChanged     = "role_changed" // Details: user_id، from، to
+	ActivityRoleCreated     = "role_created"
+	ActivityRoleUpdated     = "role_updated"
+	ActivityRoleDeleted     = "role_deleted"
+	ActivityBoardMoved      = "board_moved"
+	ActivityGroupDeleted    = "group_deleted"
+	ActivityGroupRestored   = "group_restored"
+	ActivityGroupArchived   = "group_archived"
+	ActivityGroupUnarchived = "group_unarchived"
+
+	ActivityOwnershipOffered     = "ownership_offered"
+	ActivityOwnershipTransferred = "ownership_transferred" // Details: from، to
+	ActivityOwnershipDeclined    = "ownership_declined"
+	ActivityOwnershipCancelled   = "ownership_cancelled"
+
+	ActivityInvitationRejected = "invitation_rejected"
+	ActivityInvitationRevoked  = "invitation_revoked"
+	ActivityInvitationExpired  = "invitation_expired"
+	ActivityInvitationResent   = "invitation_resent"
+
+	ActivityJoinRequested = "join_requested"
+	ActivityJoinApproved  = "join_approved"
+	ActivityJoinDenied    = "join_denied"
+
+	ActivityTeamCreated       = "team_created"
+	ActivityTeamUpdated       = "team_updated"
+	ActivityTeamDeleted       = "team_deleted"
+	ActivityTeamMemberAdded   = "team_member_added"   // Details: team_id، user_id، tasks
+	ActivityTeamMemberRemoved = "team_member_removed" // Details: team_id، user_id، tasks
+	ActivityTaskTeamsChanged  = "task_teams_changed"  // Details: added یا removed، assigned یا unassigned
+	ActivityAssigneesChanged  = "assignees_changed"   // Details: added، removed، assign_all
+
+	ActivitySubmissionCreated  = "submission_created"  // Details: submission_id، revision، files
+	ActivitySubmissionReviewed = "submission_reviewed" // Details: submission_id، user_id، revision، status
+)
+
+// ErrActivityAppendOnly رکوردهای فعالیت پس از ثبت قابل تغییر یا حذف نیستند
+var ErrActivityAppendOnly = errors.New("activity log is append-only")
+
+// ActivityLog رویداد ثبت‌شده روی تسک یا گروه؛ ActorID برای رویدادهای سیستمی خالی است
+// فعالیت تسک‌های گروهی GroupID هم دارد تا در فعالیت گروه نمایش داده شود
+type ActivityLog struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	TaskID    *uint           `json:"task_id" gorm:"index"`
+	GroupID   *uint           `json:"group_id" gorm:"index"`
+	ActorID   *uint           `json:"actor_id"`
+	Action    string          `json:"action" gorm:"index"`
+	Details   json.RawMessage `json:"details,omitempty" gorm:"type:json"`
+	CreatedAt time.Time       `json:"created_at" gorm:"index"`
+
+	// Relations
+	Actor *User `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+}
+
+func (a *ActivityLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrActivityAppendOnly
+}
+
+func (a *ActivityLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrActivityAppendOnly
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// BoardColumn ستون بورد کانبان گروه؛ هر ستون به یک وضعیت تسک نگاشت می‌شود
+// و چند ستون می‌توانند وضعیت یکسانی داشته باشند (مثلا «بازبینی» و «در حال انجام»)
+type BoardColumn struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	GroupID   uint       `json:"group_id" gorm:"index"`
+	Name      string     `json:"name"`
+	Status    TaskStatus `json:"status"`
+	Position  int        `json:"position"`
+	WIPLimit  int        `json:"wip_limit"` // صفر یعنی بدون محدودیت
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relations
+	Tasks []Task `json:"tasks" gorm:"foreignKey:BoardColumnID"`
+}
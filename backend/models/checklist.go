@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// ChecklistItem آیتم چک‌لیست تسک؛ هر کاربر (سازنده تسک شخصی یا هر عضو اختصاص‌یافته تسک گروهی)
+// چک‌لیست مستقل خود را دارد و Position ترتیب آیتم‌ها در همان چک‌لیست است
+type ChecklistItem struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TaskID    uint       `json:"task_id" gorm:"index:idx_checklist_owner"`
+	UserID    uint       `json:"user_id" gorm:"index:idx_checklist_owner"`
+	Title     string     `json:"title"`
+	Position  int        `json:"position"`
+	Checked   bool       `json:"checked" gorm:"default:false"`
+	CheckedAt *time.Time `json:"checked_at"`
+	CheckedBy *uint      `json:"checked_by"`
+	CreatedBy uint       `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
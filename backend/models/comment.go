@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// Comment revision actions
+const (
+	CommentActionEdit   = "edit"
+	CommentActionDelete = "delete"
+)
+
+// TaskComment دیدگاه Markdown روی تسک؛ با ParentID پاسخ به دیدگاه دیگر است
+// دیدگاه حذف‌شده برای حفظ ساختار گفتگو با متن خالی باقی می‌ماند
+type TaskComment struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TaskID    uint       `json:"task_id" gorm:"index"`
+	UserID    uint       `json:"user_id" gorm:"index"`
+	ParentID  *uint      `json:"parent_id" gorm:"index"`
+	Body      string     `json:"body" gorm:"type:text"`
+	EditedAt  *time.Time `json:"edited_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	DeletedBy *uint      `json:"deleted_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relations
+	User    *User          `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Replies []*TaskComment `json:"replies,omitempty" gorm:"-"`
+}
+
+// CommentRevision متن قبلی دیدگاه پیش از هر ویرایش یا حذف
+type CommentRevision struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CommentID uint      `json:"comment_id" gorm:"index"`
+	Body      string    `json:"body" gorm:"type:text"`
+	Action    string    `json:"action"`
+	ChangedBy uint      `json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User *User `json:"user,omitempty" gorm:"foreignKey:ChangedBy"`
+}
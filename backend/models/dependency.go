@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// TaskDependency وابستگی پایان-به-شروع بین دو تسک
+// TaskID تا زمانی که DependsOnID تکمیل نشده نمی‌تواند شروع یا تکمیل شود
+type TaskDependency struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TaskID      uint      `json:"task_id" gorm:"uniqueIndex:idx_task_dependency"`
+	DependsOnID uint      `json:"depends_on_id" gorm:"uniqueIndex:idx_task_dependency;index"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relations
+	Task      *Task `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	DependsOn *Task `json:"depends_on,omitempty" gorm:"foreignKey:DependsOnID"`
+}
@@ -2,21 +2,30 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type File struct {
 	ID          uint       `gorm:"primaryKey" json:"id"`
-	TaskID      *uint      `json:"task_id"`
+	TaskID      uint       `json:"task_id" gorm:"index"`
 	GroupTaskID *uint      `json:"group_task_id"`
 	UserID      uint       `json:"user_id" gorm:"index"`
-	FileName    string     `json:"file_name"`
-	FilePath    string     `json:"file_path"`
+	Filename    string     `json:"filename"`
+	Filepath    string     `json:"-"`
 	FileSize    int64      `json:"file_size"`
 	FileType    string     `json:"file_type"`
+	MimeType    string     `json:"mime_type"`
 	UploadedAt  time.Time  `json:"uploaded_at"`
+	Approved    bool       `json:"approved" gorm:"default:false"`
 	ApprovedBy  *uint      `json:"approved_by"`
 	ApprovedAt  *time.Time `json:"approved_at"`
 
+	// SubmissionID - نسخه ارسالی که فایل همراه آن برای بررسی فرستاده شده
+	SubmissionID *uint `json:"submission_id" gorm:"index"`
+
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"` // فایل حذف‌شده تا پاک‌سازی نهایی روی دیسک می‌ماند
+
 	// Relations
 	Task      *Task      `json:"task,omitempty" gorm:"foreignKey:TaskID"`
 	GroupTask *GroupTask `json:"group_task,omitempty" gorm:"foreignKey:GroupTaskID"`
@@ -2,6 +2,15 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Group visibility
+const (
+	GroupPrivate      = "private"      // فقط با دعوت یا لینک دعوت
+	GroupDiscoverable = "discoverable" // در جستجوی گروه‌ها دیده می‌شود و عضویت با درخواست و تایید مدیر است
+	GroupOpen         = "open"         // در جستجو دیده می‌شود و هر کاربری بلافاصله می‌تواند عضو شود
 )
 
 type Group struct {
@@ -12,9 +21,17 @@ type Group struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"` // گروه حذف‌شده در سطل زباله
+
+	Visibility string `json:"visibility" gorm:"size:16;default:'private';index"`
+
+	// Archive - گروه بایگانی‌شده فقط خواندنی است
+	ArchivedAt *time.Time `json:"archived_at"`
+	ArchivedBy *uint      `json:"archived_by"`
+
 	// Relations
 	Members []GroupMember `json:"members,omitempty" gorm:"foreignKey:GroupID"`
-	Tasks   []GroupTask   `json:"tasks,omitempty" gorm:"foreignKey:GroupID"`
+	Tasks   []Task        `json:"tasks,omitempty" gorm:"foreignKey:GroupID"`
 	Creator *User         `json:"creator,omitempty" gorm:"foreignKey:CreatedBy;references:ID"`
 }
 
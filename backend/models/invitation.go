@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Invitation statuses
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRejected = "rejected"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// GroupInvitation تاریخچه دعوت مستقیم کاربر به گروه
+// دعوت pending همراه یک GroupMember با Accepted=false است؛ با رد، لغو یا انقضا آن عضویت حذف می‌شود
+// و ارسال دوباره یک دعوت جدید می‌سازد تا تاریخچه دست‌نخورده بماند
+type GroupInvitation struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	GroupID     uint       `json:"group_id" gorm:"index"`
+	UserID      uint       `json:"user_id" gorm:"index"`
+	InvitedBy   uint       `json:"invited_by"`
+	Role        string     `json:"role" gorm:"default:'member'"`
+	Status      string     `json:"status" gorm:"size:16;default:'pending';index"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"index"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relations
+	User    *User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Inviter *User  `json:"inviter,omitempty" gorm:"foreignKey:InvitedBy"`
+	Group   *Group `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// GroupInvite لینک/کد دعوت امضاشده گروه
+// MaxUses صفر یعنی بدون محدودیت و یک یعنی یک‌بار مصرف؛ لینک باطل‌شده یا منقضی‌شده قابل استفاده نیست
+type GroupInvite struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	GroupID   uint       `json:"group_id" gorm:"index"`
+	Code      string     `json:"code" gorm:"size:64;uniqueIndex"`
+	Role      string     `json:"role" gorm:"default:'member'"`
+	MaxUses   int        `json:"max_uses" gorm:"default:0"`
+	Uses      int        `json:"uses" gorm:"default:0"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	RevokedBy *uint      `json:"revoked_by"`
+	CreatedBy uint       `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relations
+	Creator *User `json:"creator,omitempty" gorm:"foreignKey:CreatedBy"`
+}
+
+// GroupInviteUse ثبت عضویت از طریق لینک دعوت برای بررسی اینکه چه کسی با کدام لینک وارد شده است
+type GroupInviteUse struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	InviteID  uint      `json:"invite_id" gorm:"index"`
+	GroupID   uint      `json:"group_id" gorm:"index"`
+	UserID    uint      `json:"user_id" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
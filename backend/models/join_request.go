@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Join request statuses
+const (
+	JoinRequestPending   = "pending"
+	JoinRequestApproved  = "approved"
+	JoinRequestDenied    = "denied"
+	JoinRequestCancelled = "cancelled"
+)
+
+// GroupJoinRequest درخواست عضویت کاربر در گروه discoverable که مدیر تایید یا رد می‌کند
+type GroupJoinRequest struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	GroupID    uint       `json:"group_id" gorm:"index"`
+	UserID     uint       `json:"user_id" gorm:"index"`
+	Message    string     `json:"message" gorm:"type:text"`
+	Status     string     `json:"status" gorm:"size:16;default:'pending';index"`
+	ReviewedBy *uint      `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Relations
+	User     *User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Reviewer *User  `json:"reviewer,omitempty" gorm:"foreignKey:ReviewedBy"`
+	Group    *Group `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+}
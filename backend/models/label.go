@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// Label برچسب تسک؛ یا متعلق به یک کاربر است (UserID) یا بین اعضای یک گروه مشترک است (GroupID)
+type Label struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `json:"name" gorm:"index"`
+	Color     string    `json:"color" gorm:"default:'#808080'"`
+	UserID    *uint     `json:"user_id" gorm:"index"`
+	GroupID   *uint     `json:"group_id" gorm:"index"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	Group *Group `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+}
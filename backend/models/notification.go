@@ -6,6 +6,57 @@ import (
 	"time"
 )
 
+// Notification types
+const (
+	NotificationTaskExpired  = "task_expired"
+	NotificationTaskReminder = "task_reminder"
+	NotificationMention      = "comment_mention"
+	NotificationRoleChanged  = "group_role_changed"
+
+	NotificationTaskAssigned   = "task_assigned"
+	NotificationTaskUnassigned = "task_unassigned"
+
+	NotificationSubmissionReceived = "submission_received"
+	NotificationSubmissionReviewed = "submission_reviewed"
+
+	NotificationOwnershipOffered  = "ownership_offered"
+	NotificationOwnershipAccepted = "ownership_accepted"
+	NotificationOwnershipDeclined = "ownership_declined"
+
+	NotificationGroupInvitation    = "group_invitation"
+	NotificationInvitationAccepted = "invitation_accepted"
+	NotificationInvitationRejected = "invitation_rejected"
+
+	NotificationJoinRequested = "join_requested"
+	NotificationJoinApproved  = "join_approved"
+	NotificationJoinDenied    = "join_denied"
+)
+
+// TaskNotificationTypes اعلان‌هایی که RelatedID آن‌ها شناسه تسک است
+var TaskNotificationTypes = []string{
+	NotificationTaskExpired,
+	NotificationTaskReminder,
+	NotificationMention,
+	NotificationTaskAssigned,
+	NotificationTaskUnassigned,
+	NotificationSubmissionReceived,
+	NotificationSubmissionReviewed,
+}
+
+// GroupNotificationTypes اعلان‌هایی که RelatedID آن‌ها شناسه گروه است
+var GroupNotificationTypes = []string{
+	NotificationRoleChanged,
+	NotificationOwnershipOffered,
+	NotificationOwnershipAccepted,
+	NotificationOwnershipDeclined,
+	NotificationGroupInvitation,
+	NotificationInvitationAccepted,
+	NotificationInvitationRejected,
+	NotificationJoinRequested,
+	NotificationJoinApproved,
+	NotificationJoinDenied,
+}
+
 type Notification struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	UserID    uint      `json:"user_id" gorm:"index"`
@@ -13,7 +64,7 @@ type Notification struct {
 	Title     string    `json:"title"`
 	Message   string    `json:"message"`
 	RelatedID uint      `json:"related_id"`
-	Read      bool      `json:"read" gorm:"default:false"`
+	IsRead    bool      `json:"is_read" gorm:"default:false;index"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Ownership transfer statuses
+const (
+	TransferPending   = "pending"
+	TransferAccepted  = "accepted"
+	TransferDeclined  = "declined"
+	TransferCancelled = "cancelled"
+)
+
+// OwnershipTransfer پیشنهاد انتقال مالکیت گروه؛ تا پذیرش عضو مقصد، مالک فعلی تغییر نمی‌کند
+// هر گروه حداکثر یک پیشنهاد pending دارد
+type OwnershipTransfer struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	GroupID     uint       `json:"group_id" gorm:"index"`
+	FromUserID  uint       `json:"from_user_id"`
+	ToUserID    uint       `json:"to_user_id" gorm:"index"`
+	Status      string     `json:"status" gorm:"size:16;default:'pending';index"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+
+	// Relations
+	FromUser *User `json:"from_user,omitempty" gorm:"foreignKey:FromUserID"`
+	ToUser   *User `json:"to_user,omitempty" gorm:"foreignKey:ToUserID"`
+}
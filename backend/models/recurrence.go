@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// TaskRecurrence تعریف یک سری تسک تکرارشونده
+// در هر لحظه فقط رخداد جاری ساخته می‌شود و رخداد بعدی هنگام تکمیل آن تولید می‌شود
+type TaskRecurrence struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CreatorID   uint      `json:"creator_id" gorm:"index"`
+	Rule        string    `json:"rule"`      // RRULE مطابق RFC 5545 (زیرمجموعه)
+	StartsAt    time.Time `json:"starts_at"` // زمان اولین رخداد (DTSTART)
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Priority    string    `json:"priority" gorm:"default:'medium'"`
+	Occurrences int       `json:"occurrences"` // تعداد رخدادهای ساخته‌شده
+	Active      bool      `json:"active" gorm:"default:true;index"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// Reminder statuses
+const (
+	ReminderPending   = "pending"
+	ReminderSent      = "sent"
+	ReminderDismissed = "dismissed"
+	ReminderCancelled = "cancelled" // تسک تکمیل شد یا مهلت آن تغییر کرد
+)
+
+// ReminderRule قانون یادآوری «LeadMinutes دقیقه قبل از مهلت»
+// با TaskID خالی، قانون پیش‌فرض کاربر برای تمام تسک‌هاست
+type ReminderRule struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `json:"user_id" gorm:"index"`
+	TaskID      *uint     `json:"task_id" gorm:"index"`
+	LeadMinutes int       `json:"lead_minutes"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Reminder صف پایدار یادآوری‌ها؛ DedupeKey از ساخت دوباره یک یادآوری پس از راه‌اندازی مجدد جلوگیری می‌کند
+type Reminder struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `json:"user_id" gorm:"index:idx_reminders_user_status"`
+	TaskID      uint       `json:"task_id" gorm:"index"`
+	LeadMinutes int        `json:"lead_minutes"`
+	Deadline    time.Time  `json:"deadline"`
+	RemindAt    time.Time  `json:"remind_at" gorm:"index"`
+	Status      string     `json:"status" gorm:"default:'pending';index:idx_reminders_user_status"`
+	SentAt      *time.Time `json:"sent_at"`
+	DedupeKey   string     `json:"-" gorm:"size:191;uniqueIndex"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relations
+	Task *Task `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Built-in group roles
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// Permission اجازه انجام یک دسته کار در گروه
+type Permission string
+
+const (
+	PermManageTasks   Permission = "manage_tasks"   // ایجاد، ویرایش، حذف و اختصاص تسک‌های گروه
+	PermApproveFiles  Permission = "approve_files"  // تایید فایل‌ها و پیشرفت اعضا
+	PermManageMembers Permission = "manage_members" // دعوت و حذف اعضا
+	PermManageRoles   Permission = "manage_roles"   // تغییر نقش اعضا و تعریف نقش‌های سفارشی
+	PermEditSettings  Permission = "edit_settings"  // تنظیمات گروه، بورد، برچسب‌ها، الگوها و بایگانی
+	PermDeleteGroup   Permission = "delete_group"   // حذف و بازگردانی گروه
+	PermViewReports   Permission = "view_reports"   // گزارش‌های زمان گروه
+	PermContribute    Permission = "contribute"     // انجام تسک‌های اختصاص‌یافته: آپلود فایل، دیدگاه، جابه‌جایی در بورد
+)
+
+// AllPermissions تمام اجازه‌ها به ترتیب نمایش در ماتریس
+var AllPermissions = []Permission{
+	PermManageTasks,
+	PermApproveFiles,
+	PermManageMembers,
+	PermManageRoles,
+	PermEditSettings,
+	PermDeleteGroup,
+	PermViewReports,
+	PermContribute,
+}
+
+// BuiltinRoles ماتریس اجازه‌های نقش‌های پیش‌فرض
+var BuiltinRoles = map[string][]Permission{
+	RoleOwner:  AllPermissions,
+	RoleAdmin:  AllPermissions,
+	RoleEditor: {PermManageTasks, PermApproveFiles, PermViewReports, PermContribute},
+	RoleMember: {PermContribute},
+	RoleViewer: {},
+}
+
+// GroupRole نقش سفارشی تعریف‌شده در یک گروه؛ Permissions فهرست اجازه‌ها با کاما جدا شده است
+type GroupRole struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	GroupID     uint      `json:"group_id" gorm:"uniqueIndex:idx_group_role_name"`
+	Name        string    `json:"name" gorm:"size:64;uniqueIndex:idx_group_role_name"`
+	Permissions string    `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PermissionList - اجازه‌های نقش سفارشی
+func (r *GroupRole) PermissionList() []Permission {
+	var perms []Permission
+	for _, p := range strings.Split(r.Permissions, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			perms = append(perms, Permission(p))
+		}
+	}
+	return perms
+}
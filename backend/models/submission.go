@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Submission review states
+const (
+	SubmissionSubmitted        = "submitted"
+	SubmissionChangesRequested = "changes_requested"
+	SubmissionApproved         = "approved"
+	SubmissionRejected         = "rejected"
+)
+
+// Review decisions that move a submitted revision to its next state
+const (
+	ReviewApprove        = "approve"
+	ReviewRequestChanges = "request_changes"
+	ReviewReject         = "reject"
+)
+
+// TaskSubmission ارسال کار یک عضو برای تسک گروهی؛ هر ارسال دوباره پس از درخواست تغییرات یک نسخه جدید است
+// فایل‌هایی که عضو پس از نسخه قبلی آپلود کرده به همین نسخه متصل می‌شوند
+type TaskSubmission struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	TaskID        uint       `json:"task_id" gorm:"index"`
+	UserID        uint       `json:"user_id" gorm:"index"`
+	Revision      int        `json:"revision"`
+	Notes         string     `json:"notes" gorm:"type:text"`
+	Status        string     `json:"status" gorm:"size:24;default:'submitted';index"`
+	ReviewedBy    *uint      `json:"reviewed_by"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+	ReviewComment string     `json:"review_comment" gorm:"type:text"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relations
+	User     *User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Reviewer *User  `json:"reviewer,omitempty" gorm:"foreignKey:ReviewedBy"`
+	Files    []File `json:"files,omitempty" gorm:"foreignKey:SubmissionID"`
+}
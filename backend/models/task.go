@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type TaskStatus string
@@ -13,21 +15,75 @@ const (
 	StatusExpired    TaskStatus = "expired"
 )
 
+// What happens to unfinished subtasks when their parent is completed
+const (
+	SubtaskPolicyRequire      = "require"       // parent can't complete before its subtasks
+	SubtaskPolicyAutoComplete = "auto_complete" // completing the parent completes its subtasks
+)
+
+// How CreateGroupTask picks assignees automatically from the candidate members
+const (
+	StrategyRoundRobin          = "round_robin"           // whoever was assigned a group task longest ago
+	StrategyLeastOpenTasks      = "least_open_tasks"      // fewest unfinished assignments in the group
+	StrategyLeastEstimatedHours = "least_estimated_hours" // least remaining estimated work in the group
+	StrategyRandom              = "random"
+)
+
 type Task struct {
 	ID          uint       `gorm:"primaryKey" json:"id"`
-	UserID      uint       `json:"user_id" gorm:"index"`
+	CreatorID   uint       `json:"creator_id" gorm:"index"`
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
-	Status      string     `json:"status" gorm:"default:'pending'"`
+	Status      TaskStatus `json:"status" gorm:"default:'pending'"`
 	Priority    string     `json:"priority" gorm:"default:'medium'"`
 	DueDate     *time.Time `json:"due_date"`
+	StartTime   *time.Time `json:"start_time"`
+	EndTime     *time.Time `json:"end_time"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 
+	// Group task - تسک گروهی و محدودیت‌های فایل‌های ارسالی اعضا
+	IsGroupTask  bool   `json:"is_group_task" gorm:"default:false;index"`
+	GroupID      *uint  `json:"group_id" gorm:"index"`
+	RequireFiles bool   `json:"require_files" gorm:"default:false"`
+	MaxFiles     int    `json:"max_files"`
+	AllowTypes   string `json:"allow_types"` // پسوندهای مجاز، جداشده با کاما
+
+	// DeletedAt - تسک حذف‌شده تا پایان دوره نگهداری در سطل زباله می‌ماند
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+	// Recurrence - تسک‌های تکرارشونده
+	RecurrenceID    *uint `json:"recurrence_id" gorm:"index"`
+	OccurrenceIndex int   `json:"occurrence_index"` // شماره رخداد در سری، از 1
+
+	// EstimateMinutes - زمان تخمینی انجام تسک
+	EstimateMinutes *int `json:"estimate_minutes"`
+
+	// ProgressFromChecklist - پیشرفت از روی آیتم‌های تیک‌خورده چک‌لیست محاسبه می‌شود
+	ProgressFromChecklist bool `json:"progress_from_checklist" gorm:"default:false"`
+
+	// Board - جایگاه تسک گروهی در بورد کانبان؛ BoardRank رتبه کسری در ستون است
+	BoardColumnID *uint  `json:"board_column_id" gorm:"index"`
+	BoardRank     string `json:"board_rank" gorm:"size:64"`
+
+	// AssignAll - تسک گروهی به تمام اعضای فعلی و آینده گروه اختصاص دارد
+	AssignAll bool `json:"assign_all" gorm:"default:false"`
+
+	// Subtasks - سلسله‌مراتب تسک‌ها
+	ParentID      *uint  `json:"parent_id" gorm:"index"`
+	SubtaskPolicy string `json:"subtask_policy" gorm:"default:'require'"`
+
 	// Relations
-	User     *User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Progress []Progress `json:"progress,omitempty" gorm:"foreignKey:TaskID"`
-	Files    []File     `json:"files,omitempty" gorm:"foreignKey:TaskID"`
+	Creator         *User               `json:"creator,omitempty" gorm:"foreignKey:CreatorID"`
+	Group           *Group              `json:"group,omitempty" gorm:"foreignKey:GroupID"`
+	Progress        []Progress          `json:"progress,omitempty" gorm:"foreignKey:TaskID"`
+	Files           []File              `json:"files,omitempty" gorm:"foreignKey:TaskID"`
+	Recurrence      *TaskRecurrence     `json:"recurrence,omitempty" gorm:"foreignKey:RecurrenceID"`
+	Subtasks        []Task              `json:"subtasks,omitempty" gorm:"foreignKey:ParentID"`
+	Labels          []Label             `json:"labels,omitempty" gorm:"many2many:task_labels;"`
+	Teams           []GroupTeam         `json:"teams,omitempty" gorm:"many2many:task_teams;joinForeignKey:TaskID;joinReferences:TeamID"`
+	TaskAssignments []TaskAssignment    `json:"task_assignments,omitempty" gorm:"foreignKey:TaskID"`
+	GroupProgress   []GroupTaskProgress `json:"group_progress,omitempty" gorm:"foreignKey:TaskID"`
 }
 
 type TaskAssignment struct {
@@ -35,6 +91,7 @@ type TaskAssignment struct {
 	TaskID    uint      `gorm:"not null" json:"task_id"`
 	UserID    uint      `gorm:"not null" json:"user_id"`
 	Completed bool      `gorm:"default:false" json:"completed"`
+	ViaTeam   bool      `gorm:"default:false" json:"via_team"` // فقط از طریق تیم‌های تسک اختصاص یافته، نه مستقیم
 	User      User      `gorm:"foreignKey:UserID" json:"user"`
 	Task      Task      `gorm:"foreignKey:TaskID" json:"task"`
 	CreatedAt time.Time `json:"created_at"`
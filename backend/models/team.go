@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// GroupTeam زیرتیم نام‌دار داخل گروه (مثلا design یا backend) تا تسک به جای فهرست اعضا به تیم اختصاص داده شود
+type GroupTeam struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	GroupID     uint      `json:"group_id" gorm:"index"`
+	Name        string    `json:"name" gorm:"size:64"`
+	Description string    `json:"description"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relations
+	Members []GroupTeamMember `json:"members,omitempty" gorm:"foreignKey:TeamID"`
+}
+
+// GroupTeamMember عضویت یک عضو پذیرفته‌شده گروه در تیم
+type GroupTeamMember struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TeamID    uint      `json:"team_id" gorm:"uniqueIndex:idx_team_member"`
+	GroupID   uint      `json:"group_id" gorm:"index"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_team_member"`
+	AddedBy   uint      `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// TaskTemplate الگوی قابل استفاده مجدد برای ساخت تسک؛ یا شخصی است (UserID) یا بین مدیران یک گروه مشترک است (GroupID)
+// Title و Description می‌توانند جای‌نگهدارهایی مانند {{week}} و {{assignee}} داشته باشند
+type TaskTemplate struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `json:"name"`
+	UserID      *uint  `json:"user_id" gorm:"index"`
+	GroupID     *uint  `json:"group_id" gorm:"index"`
+	CreatedBy   uint   `json:"created_by"`
+	Title       string `json:"title"`
+	Description string `json:"description" gorm:"type:text"`
+	Priority    string `json:"priority"`
+	DueIn       string `json:"due_in"` // مهلت نسبت به زمان ساخت تسک، مثلا 7d؛ خالی یعنی بدون مهلت
+
+	// تنظیمات تسک گروهی
+	RequireFiles bool   `json:"require_files"`
+	MaxFiles     int    `json:"max_files"`
+	AllowTypes   string `json:"allow_types"`
+
+	EstimateMinutes       *int `json:"estimate_minutes"`
+	ProgressFromChecklist bool `json:"progress_from_checklist"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	Items []TaskTemplateItem `json:"checklist" gorm:"foreignKey:TemplateID"`
+}
+
+// TaskTemplateItem آیتم چک‌لیست الگو؛ هنگام ساخت تسک به چک‌لیست هر صاحب تسک کپی می‌شود
+type TaskTemplateItem struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	TemplateID uint   `json:"template_id" gorm:"index"`
+	Position   int    `json:"position"`
+	Title      string `json:"title"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// TimeEntry زمان صرف‌شده روی تسک؛ EndedAt خالی یعنی تایمر در حال اجراست
+// ActiveUserID فقط برای تایمر در حال اجرا مقدار دارد و ایندکس یکتای آن
+// تضمین می‌کند هر کاربر حداکثر یک تایمر فعال داشته باشد
+type TimeEntry struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TaskID       uint       `json:"task_id" gorm:"index"`
+	UserID       uint       `json:"user_id" gorm:"index"`
+	StartedAt    time.Time  `json:"started_at"`
+	EndedAt      *time.Time `json:"ended_at"`
+	Seconds      int64      `json:"seconds"` // برای تایمر در حال اجرا صفر است
+	Note         string     `json:"note"`
+	Manual       bool       `json:"manual" gorm:"default:false"`
+	ActiveUserID *uint      `json:"-" gorm:"uniqueIndex"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relations
+	Task *Task `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// TaskStatusTransition سابقه تغییر وضعیت تسک؛ ChangedBy برای تغییرات سیستمی (مثل انقضا) خالی است
+type TaskStatusTransition struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	TaskID     uint       `json:"task_id" gorm:"index"`
+	FromStatus TaskStatus `json:"from_status"`
+	ToStatus   TaskStatus `json:"to_status"`
+	Reason     string     `json:"reason"`
+	ChangedBy  *uint      `json:"changed_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
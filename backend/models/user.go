@@ -6,25 +6,28 @@ import (
 
 type User struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `json:"name"`
+	Username  string    `json:"username" gorm:"uniqueIndex;size:64"`
 	Email     string    `json:"email" gorm:"uniqueIndex"`
 	Password  string    `json:"-"`
-	Avatar    string    `json:"avatar"`
+	FullName  string    `json:"full_name"`
+	Bio       string    `json:"bio"`
+	AvatarURL string    `json:"avatar_url"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
 	// Relations
 	GroupMembers []GroupMember `json:"group_members,omitempty" gorm:"foreignKey:UserID"`
-	Tasks        []Task        `json:"tasks,omitempty" gorm:"foreignKey:UserID"`
+	Tasks        []Task        `json:"tasks,omitempty" gorm:"foreignKey:CreatorID"`
 }
 
 type Streak struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `json:"user_id" gorm:"index"`
-	Count     int       `json:"count"`
-	LastDate  time.Time `json:"last_date"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	UserID         uint      `json:"user_id" gorm:"uniqueIndex"`
+	CurrentStreak  int       `json:"current_streak"`
+	LongestStreak  int       `json:"longest_streak"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 
 	// Relations
 	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
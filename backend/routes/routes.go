@@ -19,12 +19,13 @@ func SetupRoutes(router *gin.Engine) {
 
 	// Protected routes
 	protected := router.Group("/api")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(), middleware.ArchivedGroupGuard())
 	{
 		// User routes
 		protected.GET("/me", controllers.GetCurrentUser)
 		protected.PUT("/profile", controllers.UpdateProfile)
 		protected.GET("/users/search", controllers.SearchUsers)
+		protected.GET("/search", controllers.Search)
 
 		// Personal Tasks routes
 		protected.GET("/tasks", controllers.GetTasks)
@@ -35,6 +36,71 @@ func SetupRoutes(router *gin.Engine) {
 		protected.PUT("/tasks/:id/progress", controllers.UpdatePersonalProgress)
 		protected.GET("/tasks/:id/progress", controllers.GetPersonalProgress)
 
+		// Subtask routes
+		protected.GET("/tasks/:id/subtasks", controllers.GetSubtasks)
+		protected.POST("/tasks/:id/subtasks", controllers.CreateSubtask)
+		protected.PUT("/tasks/:id/subtasks/:subtask_id", controllers.AttachSubtask)
+		protected.DELETE("/tasks/:id/subtasks/:subtask_id", controllers.DetachSubtask)
+
+		// Template routes
+		protected.GET("/templates", controllers.GetTemplates)
+		protected.POST("/templates", controllers.CreateTemplate)
+		protected.GET("/templates/:id", controllers.GetTemplate)
+		protected.PUT("/templates/:id", controllers.UpdateTemplate)
+		protected.DELETE("/templates/:id", controllers.DeleteTemplate)
+		protected.POST("/templates/:id/instantiate", controllers.InstantiateTemplate)
+
+		// Label routes
+		protected.GET("/labels", controllers.GetLabels)
+		protected.POST("/labels", controllers.CreateLabel)
+		protected.PUT("/labels/:id", controllers.UpdateLabel)
+		protected.DELETE("/labels/:id", controllers.DeleteLabel)
+		protected.POST("/tasks/:id/labels", controllers.AddTaskLabels)
+		protected.DELETE("/tasks/:id/labels/:label_id", controllers.RemoveTaskLabel)
+
+		// Checklist routes
+		protected.GET("/tasks/:id/checklist", controllers.GetChecklist)
+		protected.POST("/tasks/:id/checklist", controllers.CreateChecklistItem)
+		protected.PUT("/tasks/:id/checklist/order", controllers.ReorderChecklist)
+		protected.PUT("/tasks/:id/checklist/:item_id", controllers.UpdateChecklistItem)
+		protected.DELETE("/tasks/:id/checklist/:item_id", controllers.DeleteChecklistItem)
+		protected.PUT("/tasks/:id/checklist/:item_id/check", controllers.CheckChecklistItem)
+		protected.PUT("/tasks/:id/checklist/:item_id/uncheck", controllers.UncheckChecklistItem)
+
+		// Comment routes
+		protected.GET("/tasks/:id/comments", controllers.GetTaskComments)
+		protected.POST("/tasks/:id/comments", controllers.CreateTaskComment)
+		protected.PUT("/tasks/:id/comments/:comment_id", controllers.UpdateTaskComment)
+		protected.DELETE("/tasks/:id/comments/:comment_id", controllers.DeleteTaskComment)
+		protected.GET("/tasks/:id/comments/:comment_id/history", controllers.GetCommentHistory)
+
+		// Activity routes
+		protected.GET("/tasks/:id/activity", controllers.GetTaskActivity)
+		protected.GET("/groups/:id/activity", controllers.GetGroupActivity)
+
+		// Time tracking routes
+		protected.GET("/timer", controllers.GetActiveTimer)
+		protected.POST("/timer/stop", controllers.StopTimer)
+		protected.POST("/tasks/:id/timer", controllers.StartTimer)
+		protected.GET("/tasks/:id/time-entries", controllers.GetTaskTimeEntries)
+		protected.POST("/tasks/:id/time-entries", controllers.CreateTimeEntry)
+		protected.PUT("/time-entries/:id", controllers.UpdateTimeEntry)
+		protected.DELETE("/time-entries/:id", controllers.DeleteTimeEntry)
+
+		// Reminder routes
+		protected.GET("/reminders", controllers.GetReminders)
+		protected.PUT("/reminders/:id/snooze", controllers.SnoozeReminder)
+		protected.PUT("/reminders/:id/dismiss", controllers.DismissReminder)
+		protected.GET("/reminder-rules", controllers.GetReminderRules)
+		protected.POST("/reminder-rules", controllers.CreateReminderRule)
+		protected.DELETE("/reminder-rules/:id", controllers.DeleteReminderRule)
+
+		// Task dependency routes
+		protected.GET("/tasks/blocked-on-me", controllers.GetBlockedOnMe)
+		protected.GET("/tasks/:id/dependencies", controllers.GetTaskDependencies)
+		protected.POST("/tasks/:id/dependencies", controllers.AddTaskDependency)
+		protected.DELETE("/tasks/:id/dependencies/:depends_on_id", controllers.RemoveTaskDependency)
+
 		// File routes
 		protected.POST("/tasks/:id/files", controllers.UploadFile)
 		protected.GET("/tasks/:id/files", controllers.GetTaskFiles)
@@ -44,6 +110,9 @@ func SetupRoutes(router *gin.Engine) {
 		// Analytics routes
 		protected.GET("/analytics/streak", controllers.GetStreak)
 		protected.GET("/analytics/summary", controllers.GetAnalyticsSummary)
+		protected.GET("/analytics/time", controllers.GetMyTimeReport)
+		protected.GET("/analytics/time/tasks/:id", controllers.GetTaskTimeReport)
+		protected.GET("/analytics/time/groups/:id", controllers.GetGroupTimeReport)
 
 		// Notification routes
 		protected.GET("/notifications", controllers.GetNotifications)
@@ -53,27 +122,95 @@ func SetupRoutes(router *gin.Engine) {
 		// ✅ Group routes - اصلاح‌شده
 		protected.GET("/groups", controllers.GetUserGroups)
 		protected.POST("/groups", controllers.CreateGroup)
+		protected.GET("/groups/discover", controllers.SearchGroups)
 		protected.GET("/groups/:id", controllers.GetGroupDetails)
 		protected.PUT("/groups/:id", controllers.UpdateGroup)
 		protected.DELETE("/groups/:id", controllers.DeleteGroup)
+		protected.POST("/groups/:id/archive", controllers.ArchiveGroup)
+		protected.DELETE("/groups/:id/archive", controllers.UnarchiveGroup)
 
 		// Group Members routes
 		protected.POST("/groups/:id/members", controllers.AddGroupMembers)
 		protected.GET("/groups/:id/members", controllers.GetGroupMembers)
 		protected.DELETE("/groups/:id/members/:user_id", controllers.RemoveMember)
+		protected.PUT("/groups/:id/members/:user_id/role", controllers.UpdateMemberRole)
+		protected.POST("/groups/:id/leave", controllers.LeaveGroup)
+
+		// Group Team routes
+		protected.GET("/groups/:id/teams", controllers.GetGroupTeams)
+		protected.POST("/groups/:id/teams", controllers.CreateGroupTeam)
+		protected.PUT("/groups/:id/teams/:team_id", controllers.UpdateGroupTeam)
+		protected.DELETE("/groups/:id/teams/:team_id", controllers.DeleteGroupTeam)
+		protected.POST("/groups/:id/teams/:team_id/members", controllers.AddTeamMembers)
+		protected.DELETE("/groups/:id/teams/:team_id/members/:user_id", controllers.RemoveTeamMember)
+
+		// Group Join Request routes
+		protected.GET("/groups/join-requests", controllers.GetMyJoinRequests)
+		protected.POST("/groups/:id/join-requests", controllers.CreateJoinRequest)
+		protected.DELETE("/groups/:id/join-requests", controllers.CancelJoinRequest)
+		protected.GET("/groups/:id/join-requests", controllers.GetGroupJoinRequests)
+		protected.POST("/groups/:id/join-requests/:request_id/approve", controllers.ApproveJoinRequest)
+		protected.POST("/groups/:id/join-requests/:request_id/deny", controllers.DenyJoinRequest)
+
+		// Group Invite Link routes
+		protected.POST("/groups/join/:code", controllers.JoinGroupByCode)
+		protected.GET("/groups/:id/invites", controllers.GetGroupInvites)
+		protected.POST("/groups/:id/invites", controllers.CreateGroupInvite)
+		protected.DELETE("/groups/:id/invites/:invite_id", controllers.RevokeGroupInvite)
+		protected.GET("/groups/:id/invites/:invite_id/uses", controllers.GetGroupInviteUses)
+
+		// Group Ownership routes
+		protected.GET("/groups/:id/transfer", controllers.GetOwnershipTransfer)
+		protected.POST("/groups/:id/transfer", controllers.TransferOwnership)
+		protected.DELETE("/groups/:id/transfer", controllers.CancelOwnershipTransfer)
+		protected.POST("/groups/:id/transfer/accept", controllers.AcceptOwnershipTransfer)
+		protected.POST("/groups/:id/transfer/decline", controllers.DeclineOwnershipTransfer)
+
+		// Group Roles routes
+		protected.GET("/groups/:id/roles", controllers.GetGroupRoles)
+		protected.POST("/groups/:id/roles", controllers.CreateGroupRole)
+		protected.PUT("/groups/:id/roles/:role_id", controllers.UpdateGroupRole)
+		protected.DELETE("/groups/:id/roles/:role_id", controllers.DeleteGroupRole)
 
 		// ✅ Invitations routes - جدید
 		protected.GET("/groups/invitations", controllers.GetPendingInvitations)
 		protected.POST("/groups/:id/accept-invitation", controllers.AcceptInvitation)
 		protected.POST("/groups/:id/reject-invitation", controllers.RejectInvitation)
+		protected.GET("/groups/:id/invitations", controllers.GetGroupInvitations)
+		protected.DELETE("/groups/:id/invitations/:invitation_id", controllers.RevokeGroupInvitation)
+		protected.POST("/groups/:id/invitations/:invitation_id/resend", controllers.ResendGroupInvitation)
 
 		// Group Tasks routes
 		protected.GET("/groups/:id/tasks", controllers.GetGroupTasks)
 		protected.POST("/groups/:id/tasks", controllers.CreateGroupTask)
+		protected.GET("/groups/:id/assignment-preview", controllers.PreviewGroupTaskAssignment)
 		protected.PUT("/groups/:id/tasks/:task_id", controllers.UpdateGroupTask)
 		protected.DELETE("/groups/:id/tasks/:task_id", controllers.DeleteGroupTask)
 		protected.PUT("/groups/:id/tasks/:task_id/progress", controllers.UpdateGroupProgress)
 		protected.GET("/groups/:id/tasks/:task_id/progress", controllers.GetGroupProgress)
+		protected.POST("/groups/:id/tasks/:task_id/assignees", controllers.AddGroupTaskAssignees)
+		protected.PUT("/groups/:id/tasks/:task_id/assignees", controllers.ReplaceGroupTaskAssignees)
+		protected.DELETE("/groups/:id/tasks/:task_id/assignees/:user_id", controllers.RemoveGroupTaskAssignee)
+		protected.POST("/groups/:id/tasks/:task_id/teams", controllers.AssignTaskTeams)
+		protected.DELETE("/groups/:id/tasks/:task_id/teams/:team_id", controllers.UnassignTaskTeam)
+		protected.POST("/groups/:id/tasks/:task_id/submissions", controllers.SubmitGroupTask)
+		protected.GET("/groups/:id/tasks/:task_id/submissions", controllers.GetGroupTaskSubmissions)
+		protected.POST("/groups/:id/tasks/:task_id/submissions/:submission_id/review", controllers.ReviewGroupTaskSubmission)
+
+		// Board routes
+		protected.GET("/groups/:id/board", controllers.GetBoard)
+		protected.PATCH("/groups/:id/board/move", controllers.MoveBoardTask)
+		protected.POST("/groups/:id/board/columns", controllers.CreateBoardColumn)
+		protected.PUT("/groups/:id/board/columns/order", controllers.ReorderBoardColumns)
+		protected.PUT("/groups/:id/board/columns/:column_id", controllers.UpdateBoardColumn)
+		protected.DELETE("/groups/:id/board/columns/:column_id", controllers.DeleteBoardColumn)
+
+		// Trash routes
+		protected.GET("/trash", controllers.GetTrash)
+		protected.GET("/groups/:id/trash", controllers.GetGroupTrash)
+		protected.POST("/trash/tasks/:id/restore", controllers.RestoreTask)
+		protected.POST("/trash/groups/:id/restore", controllers.RestoreGroup)
+		protected.POST("/trash/files/:id/restore", controllers.RestoreFile)
 
 		// Group Task Files routes
 		protected.GET("/groups/:id/tasks/:task_id/files/:user_id", controllers.GetGroupTaskFilesByUser)
@@ -0,0 +1,132 @@
+// backend/search/memory.go
+
+package search
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Document سند قابل ایندکس در MemoryIndex
+// OwnerID برای منابع شخصی و GroupID برای منابع گروهی تعیین‌کننده دسترسی است؛ برای منابع گروهی OwnerID صفر می‌ماند
+type Document struct {
+	Kind    string
+	ID      uint
+	Title   string
+	Body    string
+	TaskID  *uint
+	GroupID *uint
+	OwnerID uint
+}
+
+func (d Document) key() string {
+	return d.Kind + ":" + strconv.FormatUint(uint64(d.ID), 10)
+}
+
+// MemoryIndex ایندکس معکوس درون‌برنامه‌ای با امتیازدهی TF-IDF
+// برای تست‌ها و اجرای بدون MySQL؛ اسناد باید با Add اضافه شوند
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]Document
+	postings map[string]map[string]int // term -> doc key -> term frequency
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[string]Document),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// Add - افزودن یا جایگزینی سند
+func (m *MemoryIndex) Add(doc Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := doc.key()
+	m.removeLocked(key)
+	m.docs[key] = doc
+	// عنوان وزن بیشتری از متن دارد
+	for _, term := range tokenize(doc.Title) {
+		m.addPosting(term, key, 2)
+	}
+	for _, term := range tokenize(doc.Body) {
+		m.addPosting(term, key, 1)
+	}
+}
+
+// Remove - حذف سند از ایندکس
+func (m *MemoryIndex) Remove(kind string, id uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeLocked(Document{Kind: kind, ID: id}.key())
+}
+
+func (m *MemoryIndex) addPosting(term, key string, weight int) {
+	if m.postings[term] == nil {
+		m.postings[term] = make(map[string]int)
+	}
+	m.postings[term][key] += weight
+}
+
+func (m *MemoryIndex) removeLocked(key string) {
+	if _, ok := m.docs[key]; !ok {
+		return
+	}
+	delete(m.docs, key)
+	for term, docs := range m.postings {
+		delete(docs, key)
+		if len(docs) == 0 {
+			delete(m.postings, term)
+		}
+	}
+}
+
+func (m *MemoryIndex) Search(access Access, query string, opts Options) ([]Result, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scores := make(map[string]float64)
+	total := float64(len(m.docs))
+	for _, term := range tokenize(query) {
+		docs := m.postings[term]
+		if len(docs) == 0 {
+			continue
+		}
+		idf := math.Log(1 + total/float64(len(docs)))
+		for key, tf := range docs {
+			scores[key] += float64(tf) * idf
+		}
+	}
+
+	var results []Result
+	for key, score := range scores {
+		doc := m.docs[key]
+		if !opts.wants(doc.Kind) {
+			continue
+		}
+		if doc.OwnerID != access.UserID && !access.inGroup(doc.GroupID) {
+			continue
+		}
+		results = append(results, Result{
+			Kind:    doc.Kind,
+			ID:      doc.ID,
+			Title:   doc.Title,
+			Snippet: snippet(doc.Body),
+			TaskID:  doc.TaskID,
+			GroupID: doc.GroupID,
+			Score:   score,
+		})
+	}
+	return rank(results, opts.Limit), nil
+}
+
+// tokenize - تبدیل متن به واژه‌های کوچک‌شده؛ حروف غیرلاتین (مثل فارسی) هم پشتیبانی می‌شوند
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
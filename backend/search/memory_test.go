@@ -0,0 +1,137 @@
+// backend/search/memory_test.go
+
+package search
+
+import (
+	"reflect"
+	"sort"
+	"task-manager/config"
+	"task-manager/models"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// resultKeys - نوع و شناسه نتایج به ترتیب برگشتی
+func resultKeys(results []Result) []string {
+	keys := make([]string, len(results))
+	for i, result := range results {
+		keys[i] = Document{Kind: result.Kind, ID: result.ID}.key()
+	}
+	return keys
+}
+
+func TestMemoryIndexRanking(t *testing.T) {
+	memory := NewMemoryIndex()
+	var index Index = memory
+	access := Access{UserID: 1}
+
+	memory.Add(Document{Kind: KindTask, ID: 1, Title: "Quarterly report", Body: "numbers for finance", OwnerID: 1})
+	memory.Add(Document{Kind: KindTask, ID: 2, Title: "Team lunch", Body: "book a table; the report can wait", OwnerID: 1})
+	memory.Add(Document{Kind: KindNote, ID: 3, Title: "Team lunch", Body: "report report report", OwnerID: 1})
+	memory.Add(Document{Kind: KindFile, ID: 4, Title: "گزارش-فصلی.pdf", OwnerID: 1})
+
+	// عنوان وزن دو برابر متن دارد و تکرار واژه امتیاز را بالا می‌برد
+	results, err := index.Search(access, "Report", Options{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got, want := resultKeys(results), []string{"note:3", "task:1", "task:2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ranking = %v, want %v", got, want)
+	}
+	if results[0].Score <= results[1].Score || results[1].Score <= results[2].Score {
+		t.Errorf("scores not descending: %+v", results)
+	}
+
+	// واژه نادر وزن IDF بیشتری از واژه پرتکرار دارد
+	results, _ = index.Search(access, "report finance", Options{})
+	if len(results) == 0 || results[0].ID != 1 {
+		t.Errorf("rare term ranking = %v, want task:1 first", resultKeys(results))
+	}
+
+	results, _ = index.Search(access, "report", Options{Kinds: []string{KindTask}, Limit: 1})
+	if got, want := resultKeys(results), []string{"task:1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("kinds and limit = %v, want %v", got, want)
+	}
+
+	results, _ = index.Search(access, "فصلی", Options{})
+	if got, want := resultKeys(results), []string{"file:4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("persian term = %v, want %v", got, want)
+	}
+
+	// جایگزینی و حذف سند واژه‌های قبلی را از ایندکس پاک می‌کند
+	memory.Add(Document{Kind: KindTask, ID: 1, Title: "Budget", OwnerID: 1})
+	memory.Remove(KindNote, 3)
+	results, _ = index.Search(access, "report", Options{})
+	if got, want := resultKeys(results), []string{"task:2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after replace and remove = %v, want %v", got, want)
+	}
+}
+
+func TestMemoryIndexAccess(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(config.Models()...); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+
+	user := models.User{Username: "user", Email: "user@example.com"}
+	other := models.User{Username: "other", Email: "other@example.com"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	joined := models.Group{Name: "joined", CreatedBy: other.ID}
+	invited := models.Group{Name: "invited", CreatedBy: other.ID}
+	foreign := models.Group{Name: "foreign", CreatedBy: other.ID}
+	for _, group := range []*models.Group{&joined, &invited, &foreign} {
+		if err := db.Create(group).Error; err != nil {
+			t.Fatalf("create group: %v", err)
+		}
+	}
+	members := []models.GroupMember{
+		{GroupID: joined.ID, UserID: user.ID, Role: models.RoleMember, Accepted: true},
+		{GroupID: invited.ID, UserID: user.ID, Role: models.RoleMember, Accepted: false},
+		{GroupID: foreign.ID, UserID: other.ID, Role: models.RoleOwner, Accepted: true},
+	}
+	if err := db.Create(&members).Error; err != nil {
+		t.Fatalf("create members: %v", err)
+	}
+
+	access, err := AccessFor(db, user.ID)
+	if err != nil {
+		t.Fatalf("AccessFor: %v", err)
+	}
+	if !reflect.DeepEqual(access.GroupIDs, []uint{joined.ID}) {
+		t.Fatalf("access groups = %v, want only accepted group %d", access.GroupIDs, joined.ID)
+	}
+
+	index := NewMemoryIndex()
+	index.Add(Document{Kind: KindTask, ID: 1, Title: "plan mine", OwnerID: user.ID})
+	index.Add(Document{Kind: KindTask, ID: 2, Title: "plan theirs", OwnerID: other.ID})
+	index.Add(Document{Kind: KindTask, ID: 3, Title: "plan joined", GroupID: &joined.ID})
+	index.Add(Document{Kind: KindGroup, ID: joined.ID, Title: "plan joined group", GroupID: &joined.ID})
+	index.Add(Document{Kind: KindTask, ID: 4, Title: "plan invited", GroupID: &invited.ID})
+	index.Add(Document{Kind: KindTask, ID: 5, Title: "plan foreign", GroupID: &foreign.ID})
+
+	results, err := index.Search(access, "plan", Options{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	got := resultKeys(results)
+	sort.Strings(got)
+	want := []string{Document{Kind: KindGroup, ID: joined.ID}.key(), "task:1", "task:3"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("visible results = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+// backend/search/mysql.go
+
+package search
+
+import (
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+// fullTextIndexes ایندکس‌های FULLTEXT مورد نیاز MySQLIndex
+var fullTextIndexes = []struct {
+	Table   string
+	Name    string
+	Columns string
+}{
+	{"tasks", "ft_tasks_title_description", "title, description"},
+	{"groups", "ft_groups_name_description", "name, description"},
+	{"files", "ft_files_filename", "filename"},
+	{"task_progresses", "ft_task_progresses_notes", "notes"},
+	{"group_task_progresses", "ft_group_task_progresses_notes", "notes"},
+}
+
+// MySQLIndex جستجو با MATCH ... AGAINST روی ایندکس‌های FULLTEXT
+type MySQLIndex struct {
+	DB *gorm.DB
+}
+
+func NewMySQLIndex(db *gorm.DB) *MySQLIndex {
+	return &MySQLIndex{DB: db}
+}
+
+// EnsureIndexes - ایجاد ایندکس‌های FULLTEXT در صورت نبود
+func (m *MySQLIndex) EnsureIndexes() error {
+	for _, index := range fullTextIndexes {
+		if m.DB.Migrator().HasIndex(index.Table, index.Name) {
+			continue
+		}
+		if err := m.DB.Exec("CREATE FULLTEXT INDEX " + index.Name + " ON `" + index.Table + "` (" + index.Columns + ")").Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type scoredRow struct {
+	ID      uint
+	Title   string
+	Body    string
+	TaskID  *uint
+	GroupID *uint
+	Score   float64
+}
+
+func (m *MySQLIndex) Search(access Access, query string, opts Options) ([]Result, error) {
+	groupIDs := access.GroupIDs
+	if len(groupIDs) == 0 {
+		// IN () در MySQL معتبر نیست
+		groupIDs = []uint{0}
+	}
+
+	// تسک‌هایی که کاربر به آن‌ها دسترسی دارد
+	visibleTasks := m.DB.Model(&models.Task{}).Select("id").
+		Where("(creator_id = ? AND is_group_task = ?) OR group_id IN ?", access.UserID, false, groupIDs)
+
+	var results []Result
+	collect := func(kind string, rows []scoredRow) {
+		for _, row := range rows {
+			results = append(results, Result{
+				Kind:    kind,
+				ID:      row.ID,
+				Title:   row.Title,
+				Snippet: snippet(row.Body),
+				TaskID:  row.TaskID,
+				GroupID: row.GroupID,
+				Score:   row.Score,
+			})
+		}
+	}
+
+	if opts.wants(KindTask) {
+		var rows []scoredRow
+		if err := m.DB.Table("tasks").
+			Select("id, title, description AS body, id AS task_id, group_id, MATCH(title, description) AGAINST (? IN NATURAL LANGUAGE MODE) AS score", query).
+			Where("MATCH(title, description) AGAINST (? IN NATURAL LANGUAGE MODE)", query).
+			Where("id IN (?)", visibleTasks).
+			Order("score DESC").Limit(opts.Limit).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		collect(KindTask, rows)
+	}
+
+	if opts.wants(KindGroup) {
+		var rows []scoredRow
+		if err := m.DB.Table("groups").
+			Select("id, name AS title, description AS body, id AS group_id, MATCH(name, description) AGAINST (? IN NATURAL LANGUAGE MODE) AS score", query).
+			Where("MATCH(name, description) AGAINST (? IN NATURAL LANGUAGE MODE)", query).
+			Where("id IN ? AND deleted_at IS NULL AND archived_at IS NULL", groupIDs).
+			Order("score DESC").Limit(opts.Limit).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		collect(KindGroup, rows)
+	}
+
+	if opts.wants(KindFile) {
+		var rows []scoredRow
+		if err := m.DB.Table("files").
+			Select("files.id, files.filename AS title, files.task_id, tasks.group_id, MATCH(files.filename) AGAINST (? IN NATURAL LANGUAGE MODE) AS score", query).
+			Joins("JOIN tasks ON tasks.id = files.task_id").
+			Where("MATCH(files.filename) AGAINST (? IN NATURAL LANGUAGE MODE)", query).
+			Where("files.task_id IN (?) AND files.deleted_at IS NULL", visibleTasks).
+			Order("score DESC").Limit(opts.Limit).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		collect(KindFile, rows)
+	}
+
+	if opts.wants(KindNote) {
+		var personal []scoredRow
+		if err := m.DB.Table("task_progresses").
+			Select("task_progresses.id, tasks.title, task_progresses.notes AS body, task_progresses.task_id, MATCH(task_progresses.notes) AGAINST (? IN NATURAL LANGUAGE MODE) AS score", query).
+			Joins("JOIN tasks ON tasks.id = task_progresses.task_id AND tasks.deleted_at IS NULL").
+			Where("MATCH(task_progresses.notes) AGAINST (? IN NATURAL LANGUAGE MODE)", query).
+			Where("task_progresses.user_id = ? AND task_progresses.deleted_at IS NULL", access.UserID).
+			Order("score DESC").Limit(opts.Limit).
+			Scan(&personal).Error; err != nil {
+			return nil, err
+		}
+		collect(KindNote, personal)
+
+		var group []scoredRow
+		if err := m.DB.Table("group_task_progresses").
+			Select("group_task_progresses.id, tasks.title, group_task_progresses.notes AS body, group_task_progresses.task_id, tasks.group_id, MATCH(group_task_progresses.notes) AGAINST (? IN NATURAL LANGUAGE MODE) AS score", query).
+			Joins("JOIN tasks ON tasks.id = group_task_progresses.task_id AND tasks.deleted_at IS NULL").
+			Where("MATCH(group_task_progresses.notes) AGAINST (? IN NATURAL LANGUAGE MODE)", query).
+			Where("tasks.group_id IN ? AND group_task_progresses.deleted_at IS NULL", groupIDs).
+			Order("score DESC").Limit(opts.Limit).
+			Scan(&group).Error; err != nil {
+			return nil, err
+		}
+		collect(KindNote, group)
+	}
+
+	return rank(results, opts.Limit), nil
+}
@@ -0,0 +1,115 @@
+// backend/search/search.go
+
+package search
+
+import (
+	"sort"
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+// Result kinds
+const (
+	KindTask  = "task"
+	KindGroup = "group"
+	KindFile  = "file"
+	KindNote  = "note" // یادداشت‌های پیشرفت تسک
+)
+
+var AllKinds = []string{KindTask, KindGroup, KindFile, KindNote}
+
+// Access محدوده دسترسی کاربر جستجوکننده
+// نتایج فقط از منابع متعلق به UserID یا گروه‌هایی که عضو پذیرفته‌شده آن است برگردانده می‌شوند
+type Access struct {
+	UserID   uint
+	GroupIDs []uint
+}
+
+// AccessFor - محدوده دسترسی کاربر: گروه‌هایی که عضویتش در آن‌ها پذیرفته شده است
+// دعوت‌های در انتظار دسترسی جستجو نمی‌دهند
+func AccessFor(db *gorm.DB, userID uint) (Access, error) {
+	access := Access{UserID: userID}
+	err := db.Model(&models.GroupMember{}).
+		Where("user_id = ? AND accepted = ?", userID, true).
+		Pluck("group_id", &access.GroupIDs).Error
+	return access, err
+}
+
+func (a Access) inGroup(groupID *uint) bool {
+	if groupID == nil {
+		return false
+	}
+	for _, id := range a.GroupIDs {
+		if id == *groupID {
+			return true
+		}
+	}
+	return false
+}
+
+type Options struct {
+	Kinds []string
+	Limit int
+}
+
+func (o Options) wants(kind string) bool {
+	if len(o.Kinds) == 0 {
+		return true
+	}
+	for _, k := range o.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+type Result struct {
+	Kind    string  `json:"kind"`
+	ID      uint    `json:"id"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet,omitempty"`
+	TaskID  *uint   `json:"task_id,omitempty"`
+	GroupID *uint   `json:"group_id,omitempty"`
+	Score   float64 `json:"score"`
+}
+
+// Index موتور جستجو؛ پیاده‌سازی پیش‌فرض از FULLTEXT در MySQL استفاده می‌کند
+// و MemoryIndex برای تست‌ها و محیط‌های بدون MySQL در نظر گرفته شده است
+type Index interface {
+	Search(access Access, query string, opts Options) ([]Result, error)
+}
+
+var current Index
+
+// SetIndex - تعیین موتور جستجوی فعال
+func SetIndex(index Index) {
+	current = index
+}
+
+// Current - موتور جستجوی فعال
+func Current() Index {
+	return current
+}
+
+// rank - مرتب‌سازی نزولی بر اساس امتیاز و محدود کردن تعداد نتایج
+func rank(results []Result, limit int) []Result {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// snippet - بخش کوتاهی از متن برای نمایش در نتایج
+func snippet(text string) string {
+	const max = 160
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "…"
+}
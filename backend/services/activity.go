@@ -0,0 +1,113 @@
+// backend/services/activity.go
+
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"task-manager/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FieldChange تغییر یک فیلد؛ Field نام JSON فیلد است
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// DiffFields - مقایسه فیلدهای نام‌برده (نام Go) در دو نسخه از یک struct
+func DiffFields(before, after interface{}, fields ...string) []FieldChange {
+	b := reflect.Indirect(reflect.ValueOf(before))
+	a := reflect.Indirect(reflect.ValueOf(after))
+
+	var changes []FieldChange
+	for _, name := range fields {
+		field, ok := b.Type().FieldByName(name)
+		if !ok {
+			continue
+		}
+		from := indirectValue(b.FieldByName(name))
+		to := indirectValue(a.FieldByName(name))
+		if equalValues(from, to) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: jsonName(field), From: from, To: to})
+	}
+	return changes
+}
+
+func indirectValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+func equalValues(a, b interface{}) bool {
+	// زمان‌ها با Equal مقایسه می‌شوند تا تفاوت منطقه زمانی تغییر حساب نشود
+	ta, okA := a.(time.Time)
+	tb, okB := b.(time.Time)
+	if okA && okB {
+		return ta.Equal(tb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func jsonName(field reflect.StructField) string {
+	if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+		return tag
+	}
+	return field.Name
+}
+
+// LogTaskActivity - ثبت فعالیت تسک در همان تراکنش تغییر؛ actorID صفر یعنی سیستم
+func LogTaskActivity(tx *gorm.DB, task *models.Task, actorID uint, action string, details interface{}) error {
+	taskID := task.ID
+	groupID := task.GroupID
+	if !task.IsGroupTask {
+		groupID = nil
+	}
+	return logActivity(tx, &taskID, groupID, actorID, action, details)
+}
+
+// LogGroupActivity - ثبت فعالیت گروه (مثلا تغییر اعضا) در همان تراکنش تغییر
+func LogGroupActivity(tx *gorm.DB, groupID, actorID uint, action string, details interface{}) error {
+	return logActivity(tx, nil, &groupID, actorID, action, details)
+}
+
+func logActivity(tx *gorm.DB, taskID, groupID *uint, actorID uint, action string, details interface{}) error {
+	entry := models.ActivityLog{
+		TaskID:  taskID,
+		GroupID: groupID,
+		Action:  action,
+	}
+	if actorID != 0 {
+		entry.ActorID = &actorID
+	}
+	if details != nil {
+		raw, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		entry.Details = raw
+	}
+	return tx.Create(&entry).Error
+}
+
+// LogStatusChange - ثبت تغییر وضعیت تسک در فعالیت‌ها
+func LogStatusChange(tx *gorm.DB, task *models.Task, actorID uint, from, to models.TaskStatus) error {
+	if from == to {
+		return nil
+	}
+	return LogTaskActivity(tx, task, actorID, models.ActivityStatusChanged, map[string]interface{}{
+		"from": from,
+		"to":   to,
+	})
+}
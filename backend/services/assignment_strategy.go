@@ -0,0 +1,170 @@
+// backend/services/assignment_strategy.go
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"task-manager/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidStrategy روش اختصاص خودکار ناشناخته
+var ErrInvalidStrategy = errors.New("unknown assignment strategy")
+
+// AssignmentStrategies روش‌های قابل انتخاب برای اختصاص خودکار تسک گروهی
+var AssignmentStrategies = []string{
+	models.StrategyRoundRobin,
+	models.StrategyLeastOpenTasks,
+	models.StrategyLeastEstimatedHours,
+	models.StrategyRandom,
+}
+
+// AssigneeCandidate بار کاری یک عضو در گروه و دلیل انتخاب یا رد او
+// RemainingMinutes تخمین باقی‌مانده تسک‌های باز است: تخمین هر تسک ضرب در درصد انجام‌نشده عضو
+type AssigneeCandidate struct {
+	UserID           uint       `json:"user_id"`
+	Username         string     `json:"username"`
+	OpenTasks        int64      `json:"open_tasks"`
+	RemainingMinutes int64      `json:"remaining_minutes"`
+	LastAssignedAt   *time.Time `json:"last_assigned_at"`
+	Rank             int        `json:"rank"`
+	Selected         bool       `json:"selected"`
+	Reason           string     `json:"reason"`
+}
+
+// IsValidStrategy - آیا روش اختصاص خودکار شناخته‌شده است
+func IsValidStrategy(strategy string) bool {
+	for _, s := range AssignmentStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// RankAssignees - رتبه‌بندی اعضای دارای اجازه انجام تسک با روش strategy و انتخاب count نفر اول
+// pool برابر nil یعنی تمام اعضای گروه؛ بار کاری فقط از تسک‌های همین گروه محاسبه می‌شود
+func RankAssignees(tx *gorm.DB, groupID uint, pool []uint, strategy string, count int) ([]AssigneeCandidate, error) {
+	if !IsValidStrategy(strategy) {
+		return nil, ErrInvalidStrategy
+	}
+	contributors, err := MembersWith(tx, groupID, models.PermContribute)
+	if err != nil {
+		return nil, err
+	}
+	if pool != nil {
+		contributors = contributors.Where("user_id IN ?", pool)
+	}
+	var users []models.User
+	if err := tx.Select("id", "username").Where("id IN (?)", contributors).Order("id ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	candidates := make([]AssigneeCandidate, len(users))
+	index := make(map[uint]*AssigneeCandidate, len(users))
+	userIDs := make([]uint, len(users))
+	for i, user := range users {
+		candidates[i] = AssigneeCandidate{UserID: user.ID, Username: user.Username}
+		index[user.ID] = &candidates[i]
+		userIDs[i] = user.ID
+	}
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+	if err := loadWorkload(tx, groupID, userIDs, index); err != nil {
+		return nil, err
+	}
+
+	switch strategy {
+	case models.StrategyRoundRobin:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			a, b := candidates[i].LastAssignedAt, candidates[j].LastAssignedAt
+			if a == nil || b == nil {
+				return a == nil && b != nil
+			}
+			return a.Before(*b)
+		})
+	case models.StrategyLeastOpenTasks:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].OpenTasks < candidates[j].OpenTasks
+		})
+	case models.StrategyLeastEstimatedHours:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].RemainingMinutes < candidates[j].RemainingMinutes
+		})
+	case models.StrategyRandom:
+		rand.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	}
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		candidate.Rank = i + 1
+		candidate.Selected = i < count
+		candidate.Reason = strategyReason(strategy, candidate)
+	}
+	return candidates, nil
+}
+
+// loadWorkload - تعداد تسک‌های باز، تخمین باقی‌مانده و آخرین زمان اختصاص هر عضو در گروه
+func loadWorkload(tx *gorm.DB, groupID uint, userIDs []uint, index map[uint]*AssigneeCandidate) error {
+	var open []struct {
+		UserID           uint
+		OpenTasks        int64
+		RemainingMinutes int64
+	}
+	if err := tx.Table("task_assignments").
+		Select("task_assignments.user_id, COUNT(*) AS open_tasks, "+
+			"COALESCE(SUM(COALESCE(tasks.estimate_minutes, 0) * (100 - COALESCE(group_task_progresses.progress, 0)) / 100), 0) AS remaining_minutes").
+		Joins("JOIN tasks ON tasks.id = task_assignments.task_id AND tasks.deleted_at IS NULL").
+		Joins("LEFT JOIN group_task_progresses ON group_task_progresses.task_id = task_assignments.task_id "+
+			"AND group_task_progresses.user_id = task_assignments.user_id AND group_task_progresses.deleted_at IS NULL").
+		Where("tasks.group_id = ? AND tasks.status IN ? AND task_assignments.user_id IN ?", groupID, OpenStatuses, userIDs).
+		Where("task_assignments.completed = ? AND COALESCE(group_task_progresses.is_completed, ?) = ?", false, false, false).
+		Group("task_assignments.user_id").
+		Scan(&open).Error; err != nil {
+		return err
+	}
+	for _, row := range open {
+		index[row.UserID].OpenTasks = row.OpenTasks
+		index[row.UserID].RemainingMinutes = row.RemainingMinutes
+	}
+
+	// آخرین اختصاص هر عضو همان اختصاص با بزرگ‌ترین شناسه است
+	latestIDs := tx.Table("task_assignments").
+		Select("MAX(task_assignments.id)").
+		Joins("JOIN tasks ON tasks.id = task_assignments.task_id").
+		Where("tasks.group_id = ? AND task_assignments.user_id IN ?", groupID, userIDs).
+		Group("task_assignments.user_id")
+	var latest []models.TaskAssignment
+	if err := tx.Select("user_id", "created_at").Where("id IN (?)", latestIDs).Find(&latest).Error; err != nil {
+		return err
+	}
+	for _, row := range latest {
+		at := row.CreatedAt
+		index[row.UserID].LastAssignedAt = &at
+	}
+	return nil
+}
+
+// strategyReason - توضیح رتبه عضو بر اساس معیار روش انتخاب‌شده
+func strategyReason(strategy string, c *AssigneeCandidate) string {
+	switch strategy {
+	case models.StrategyRoundRobin:
+		if c.LastAssignedAt == nil {
+			return "تا کنون تسکی در این گروه به او اختصاص داده نشده"
+		}
+		return fmt.Sprintf("آخرین تسک در %s به او اختصاص داده شده", c.LastAssignedAt.Format("2006-01-02 15:04"))
+	case models.StrategyLeastOpenTasks:
+		return fmt.Sprintf("%d تسک باز در این گروه", c.OpenTasks)
+	case models.StrategyLeastEstimatedHours:
+		return fmt.Sprintf("%.1f ساعت کار تخمینی باقی‌مانده در این گروه", float64(c.RemainingMinutes)/60)
+	default:
+		return "انتخاب تصادفی"
+	}
+}
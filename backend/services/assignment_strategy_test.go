@@ -0,0 +1,127 @@
+// backend/services/assignment_strategy_test.go
+
+package services
+
+import (
+	"errors"
+	"reflect"
+	"task-manager/models"
+	"testing"
+	"time"
+)
+
+func TestRankAssignees(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 6)
+	owner, a, b, c, d, outsider := users[0].ID, users[1].ID, users[2].ID, users[3].ID, users[4].ID, users[5].ID
+	group := createGroup(t, db, owner, a, b, c, d)
+	other := createGroup(t, db, outsider, b)
+
+	now := time.Now()
+	minutes := func(n int) *int { return &n }
+	task := func(groupID uint, status models.TaskStatus, estimate int) models.Task {
+		task := models.Task{Title: "task", CreatorID: owner, IsGroupTask: true, GroupID: &groupID, Status: status, EstimateMinutes: minutes(estimate)}
+		mustCreate(t, db, &task)
+		return task
+	}
+	assign := func(task models.Task, userID uint, ago time.Duration) {
+		mustCreate(t, db, &models.TaskAssignment{TaskID: task.ID, UserID: userID, CreatedAt: now.Add(-ago)})
+	}
+
+	// a: دو تسک باز با 300 + 60 دقیقه باقی‌مانده، آخرین اختصاص یک ساعت پیش
+	big := task(group.ID, models.StatusPending, 600)
+	assign(big, a, 3*time.Hour)
+	mustCreate(t, db, &models.GroupTaskProgress{TaskID: big.ID, UserID: a, AssignedBy: owner, Progress: 50})
+	assign(task(group.ID, models.StatusPending, 60), a, time.Hour)
+	// b: یک تسک باز 1000 دقیقه‌ای، آخرین اختصاص در این گروه دو ساعت پیش
+	assign(task(group.ID, models.StatusInProgress, 1000), b, 2*time.Hour)
+	assign(task(other.ID, models.StatusPending, 10), b, time.Minute)
+	// c: فقط تسک تکمیل‌شده و تسک حذف‌شده، آخرین اختصاص نیم ساعت پیش
+	trashed := task(group.ID, models.StatusPending, 500)
+	assign(trashed, c, 40*time.Minute)
+	assign(task(group.ID, models.StatusCompleted, 500), c, 30*time.Minute)
+	if err := TrashTask(db, &trashed, SubtasksMove); err != nil {
+		t.Fatalf("TrashTask: %v", err)
+	}
+	// d: هیچ تسکی ندارد
+
+	pool := []uint{a, b, c, d}
+	tests := []struct {
+		strategy string
+		want     []uint
+	}{
+		{models.StrategyLeastOpenTasks, []uint{c, d, b, a}},
+		{models.StrategyLeastEstimatedHours, []uint{c, d, a, b}},
+		{models.StrategyRoundRobin, []uint{d, b, a, c}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			candidates, err := RankAssignees(db, group.ID, pool, tt.strategy, 2)
+			if err != nil {
+				t.Fatalf("RankAssignees: %v", err)
+			}
+			got := make([]uint, len(candidates))
+			for i, candidate := range candidates {
+				got[i] = candidate.UserID
+				if candidate.Rank != i+1 || candidate.Selected != (i < 2) || candidate.Reason == "" {
+					t.Errorf("candidate %d = %+v", candidate.UserID, candidate)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	candidates, err := RankAssignees(db, group.ID, pool, models.StrategyLeastEstimatedHours, 1)
+	if err != nil {
+		t.Fatalf("RankAssignees: %v", err)
+	}
+	workload := make(map[uint]AssigneeCandidate)
+	for _, candidate := range candidates {
+		workload[candidate.UserID] = candidate
+	}
+	if w := workload[a]; w.OpenTasks != 2 || w.RemainingMinutes != 360 {
+		t.Errorf("a workload = %d tasks, %d minutes; want 2, 360", w.OpenTasks, w.RemainingMinutes)
+	}
+	if w := workload[b]; w.OpenTasks != 1 || w.RemainingMinutes != 1000 {
+		t.Errorf("b workload = %d tasks, %d minutes; want 1, 1000", w.OpenTasks, w.RemainingMinutes)
+	}
+	if w := workload[c]; w.OpenTasks != 0 || w.LastAssignedAt == nil {
+		t.Errorf("c workload = %+v; want no open tasks and a last assignment", w)
+	}
+}
+
+func TestRankAssigneesPoolAndStrategy(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 4)
+	owner, a, b, outsider := users[0].ID, users[1].ID, users[2].ID, users[3].ID
+	group := createGroup(t, db, owner, a, b)
+
+	if _, err := RankAssignees(db, group.ID, nil, "busiest", 1); !errors.Is(err, ErrInvalidStrategy) {
+		t.Errorf("unknown strategy = %v, want ErrInvalidStrategy", err)
+	}
+
+	// اعضای خارج از گروه حتی اگر در pool باشند انتخاب نمی‌شوند
+	candidates, err := RankAssignees(db, group.ID, []uint{b, outsider}, models.StrategyRandom, 5)
+	if err != nil {
+		t.Fatalf("RankAssignees: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].UserID != b || !candidates[0].Selected {
+		t.Errorf("pool candidates = %+v, want only b", candidates)
+	}
+
+	all, err := RankAssignees(db, group.ID, nil, models.StrategyRandom, 2)
+	if err != nil {
+		t.Fatalf("RankAssignees: %v", err)
+	}
+	selected := 0
+	for _, candidate := range all {
+		if candidate.Selected {
+			selected++
+		}
+	}
+	if len(all) != 3 || selected != 2 {
+		t.Errorf("random over whole group = %d candidates, %d selected; want 3, 2", len(all), selected)
+	}
+}
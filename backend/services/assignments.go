@@ -0,0 +1,194 @@
+// backend/services/assignments.go
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotAssignable اختصاص تسک به کاربری که عضو پذیرفته‌شده گروه با اجازه انجام تسک نیست
+var ErrNotAssignable = errors.New("user cannot be assigned tasks in this group")
+
+// assignableIDs - بررسی اینکه همه کاربران عضو پذیرفته‌شده گروه با اجازه انجام تسک‌اند
+func assignableIDs(tx *gorm.DB, groupID uint, userIDs []uint) ([]uint, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	contributors, err := MembersWith(tx, groupID, models.PermContribute)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	if err := contributors.Where("user_id IN ?", userIDs).Order("user_id ASC").Pluck("user_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	seen := make(map[uint]bool, len(userIDs))
+	for _, id := range userIDs {
+		seen[id] = true
+	}
+	if len(ids) != len(seen) {
+		return nil, ErrNotAssignable
+	}
+	return ids, nil
+}
+
+// notifyAssignment - اعلان اختصاص یا برداشتن تسک به کاربران
+func notifyAssignment(tx *gorm.DB, task *models.Task, userIDs []uint, assigned bool) error {
+	if assigned {
+		return Notify(tx, userIDs, models.NotificationTaskAssigned, "تسک جدید",
+			fmt.Sprintf("تسک «%s» به شما اختصاص داده شد", task.Title), task.ID)
+	}
+	return Notify(tx, userIDs, models.NotificationTaskUnassigned, "تسک برداشته شد",
+		fmt.Sprintf("تسک «%s» دیگر به شما اختصاص ندارد", task.Title), task.ID)
+}
+
+// logAssigneeChange - ثبت فعالیت، هم‌گام‌سازی یادآوری‌ها و اعلان به کاربران اضافه‌شده و حذف‌شده
+func logAssigneeChange(tx *gorm.DB, task *models.Task, actorID uint, added, removed []uint) error {
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+	if err := LogTaskActivity(tx, task, actorID, models.ActivityAssigneesChanged, map[string]interface{}{
+		"added":      added,
+		"removed":    removed,
+		"assign_all": task.AssignAll,
+	}); err != nil {
+		return err
+	}
+	if err := SyncTaskReminders(tx, task); err != nil {
+		return err
+	}
+	if err := notifyAssignment(tx, task, added, true); err != nil {
+		return err
+	}
+	return notifyAssignment(tx, task, removed, false)
+}
+
+// AddTaskAssignees - اختصاص مستقیم تسک گروهی به اعضای دیگر؛ شناسه کاربرانی که تازه اضافه شدند برمی‌گردد
+func AddTaskAssignees(tx *gorm.DB, task *models.Task, userIDs []uint, actorID uint) ([]uint, error) {
+	ids, err := assignableIDs(tx, *task.GroupID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	added, err := AssignTaskUsers(tx, task, ids, actorID, false)
+	if err != nil {
+		return nil, err
+	}
+	return added, logAssigneeChange(tx, task, actorID, added, nil)
+}
+
+// RemoveTaskAssignees - برداشتن اختصاص تسک از کاربران، چه مستقیم و چه از طریق تیم؛ پیشرفت آن‌ها بایگانی می‌شود
+// تیم‌های تسک دست‌نخورده می‌مانند و فقط تغییرات بعدی اعضای تیم دوباره اعمال می‌شود
+func RemoveTaskAssignees(tx *gorm.DB, task *models.Task, userIDs []uint, actorID uint) ([]uint, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var removed []uint
+	if err := tx.Model(&models.TaskAssignment{}).
+		Where("task_id = ? AND user_id IN ?", task.ID, userIDs).
+		Pluck("user_id", &removed).Error; err != nil {
+		return nil, err
+	}
+	if err := UnassignTaskUsers(tx, task, removed); err != nil {
+		return nil, err
+	}
+	if err := logAssigneeChange(tx, task, actorID, nil, removed); err != nil {
+		return nil, err
+	}
+	// برداشتن عضوی که کارش رد شده یا ناتمام مانده ممکن است تسک را آماده تکمیل کند
+	_, err := CompleteGroupTaskIfDone(tx, task, actorID)
+	return removed, err
+}
+
+// ReplaceTaskAssignees - جایگزینی کامل فهرست اعضای تسک؛ با assignAll تسک به تمام اعضای فعلی و آینده گروه اختصاص دارد
+func ReplaceTaskAssignees(tx *gorm.DB, task *models.Task, userIDs []uint, assignAll bool, actorID uint) ([]uint, []uint, error) {
+	if assignAll {
+		contributors, err := MembersWith(tx, *task.GroupID, models.PermContribute)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := contributors.Pluck("user_id", &userIDs).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	ids, err := assignableIDs(tx, *task.GroupID, userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if task.AssignAll != assignAll {
+		task.AssignAll = assignAll
+		if err := tx.Model(task).Update("assign_all", assignAll).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var removed []uint
+	query := tx.Model(&models.TaskAssignment{}).Where("task_id = ?", task.ID)
+	if len(ids) > 0 {
+		query = query.Where("user_id NOT IN ?", ids)
+	}
+	if err := query.Pluck("user_id", &removed).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := UnassignTaskUsers(tx, task, removed); err != nil {
+		return nil, nil, err
+	}
+	added, err := AssignTaskUsers(tx, task, ids, actorID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := logAssigneeChange(tx, task, actorID, added, removed); err != nil {
+		return nil, nil, err
+	}
+	_, err = CompleteGroupTaskIfDone(tx, task, actorID)
+	return added, removed, err
+}
+
+// AssignNewMember - اختصاص تسک‌های باز «تمام اعضا» به عضوی که تازه به گروه پیوسته؛ اختصاص به نام سازنده تسک ثبت می‌شود
+func AssignNewMember(tx *gorm.DB, groupID, userID uint) error {
+	if _, err := assignableIDs(tx, groupID, []uint{userID}); err != nil {
+		if errors.Is(err, ErrNotAssignable) {
+			return nil
+		}
+		return err
+	}
+	var tasks []models.Task
+	if err := tx.Where("group_id = ? AND assign_all = ? AND status IN ?", groupID, true, OpenStatuses).Find(&tasks).Error; err != nil {
+		return err
+	}
+	for i := range tasks {
+		added, err := AssignTaskUsers(tx, &tasks[i], []uint{userID}, tasks[i].CreatorID, false)
+		if err != nil {
+			return err
+		}
+		if err := logAssigneeChange(tx, &tasks[i], 0, added, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnassignRemovedMember - برداشتن اختصاص تسک‌های باز گروه از عضوی که گروه را ترک کرده یا حذف شده
+// تسک‌های تکمیل‌شده و پیشرفت ثبت‌شده در آن‌ها برای سابقه باقی می‌مانند
+func UnassignRemovedMember(tx *gorm.DB, groupID, userID, actorID uint) error {
+	var tasks []models.Task
+	if err := tx.Where("group_id = ? AND status IN ? AND id IN (?)", groupID, OpenStatuses,
+		tx.Model(&models.TaskAssignment{}).Select("task_id").Where("user_id = ?", userID)).
+		Find(&tasks).Error; err != nil {
+		return err
+	}
+	for i := range tasks {
+		if err := UnassignTaskUsers(tx, &tasks[i], []uint{userID}); err != nil {
+			return err
+		}
+		if err := LogTaskActivity(tx, &tasks[i], actorID, models.ActivityAssigneesChanged, map[string]interface{}{
+			"removed": []uint{userID},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+// backend/services/board.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"task-manager/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWIPLimitReached = errors.New("column WIP limit reached")
+	ErrBoardChanged    = errors.New("board changed since it was loaded")
+)
+
+// maxRankLength رتبه‌های بلندتر از این باعث بازسازی ترتیب کل ستون می‌شوند
+const maxRankLength = 32
+
+// defaultBoardColumns ستون‌های بوردی که هنوز ستونی ندارد
+var defaultBoardColumns = []models.BoardColumn{
+	{Name: "To do", Status: models.StatusPending},
+	{Name: "In progress", Status: models.StatusInProgress},
+	{Name: "Done", Status: models.StatusCompleted},
+}
+
+// LockBoard - قفل ردیف گروه تا پایان تراکنش؛ تمام تغییرات بورد یک گروه پشت سر هم اجرا می‌شوند
+func LockBoard(tx *gorm.DB, groupID uint) error {
+	return LockGroup(tx, groupID)
+}
+
+// EnsureBoard - ساخت ستون‌های پیش‌فرض و قرار دادن تسک‌هایی که ستون ندارند یا وضعیتشان با ستون نمی‌خواند
+// تسک با وضعیتی که هیچ ستونی ندارد (مثلا expired) بیرون از بورد می‌ماند
+func EnsureBoard(tx *gorm.DB, groupID uint) ([]models.BoardColumn, error) {
+	if err := LockBoard(tx, groupID); err != nil {
+		return nil, err
+	}
+
+	var columns []models.BoardColumn
+	if err := tx.Where("group_id = ?", groupID).Order("position ASC, id ASC").Find(&columns).Error; err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		for i, column := range defaultBoardColumns {
+			column.GroupID = groupID
+			column.Position = i
+			if err := tx.Create(&column).Error; err != nil {
+				return nil, err
+			}
+			columns = append(columns, column)
+		}
+	}
+
+	byID := make(map[uint]*models.BoardColumn, len(columns))
+	for i := range columns {
+		byID[columns[i].ID] = &columns[i]
+	}
+	firstFor := func(status models.TaskStatus) *models.BoardColumn {
+		for i := range columns {
+			if columns[i].Status == status {
+				return &columns[i]
+			}
+		}
+		return nil
+	}
+
+	var tasks []models.Task
+	if err := tx.Select("id", "status", "board_column_id").
+		Where("group_id = ? AND is_group_task = ?", groupID, true).
+		Order("created_at ASC, id ASC").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		if task.BoardColumnID != nil {
+			if column, ok := byID[*task.BoardColumnID]; ok && column.Status == task.Status {
+				continue
+			}
+		}
+
+		target := firstFor(task.Status)
+		if target == nil {
+			if task.BoardColumnID != nil {
+				if err := tx.Model(&models.Task{}).Where("id = ?", task.ID).
+					Updates(map[string]interface{}{"board_column_id": nil, "board_rank": ""}).Error; err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := placeAtEnd(tx, task.ID, target.ID); err != nil {
+			return nil, err
+		}
+	}
+	return columns, nil
+}
+
+// placeAtEnd - انتقال تسک به انتهای ستون
+func placeAtEnd(tx *gorm.DB, taskID, columnID uint) error {
+	last, err := columnBoundary(tx, columnID, taskID, "", false)
+	if err != nil {
+		return err
+	}
+	rank, err := utils.RankBetween(last, "")
+	if err != nil || len(rank) > maxRankLength {
+		if err := rebalanceColumn(tx, columnID, taskID); err != nil {
+			return err
+		}
+		if last, err = columnBoundary(tx, columnID, taskID, "", false); err != nil {
+			return err
+		}
+		if rank, err = utils.RankBetween(last, ""); err != nil {
+			return err
+		}
+	}
+	return tx.Model(&models.Task{}).Where("id = ?", taskID).
+		Updates(map[string]interface{}{"board_column_id": columnID, "board_rank": rank}).Error
+}
+
+// columnBoundary - نزدیک‌ترین رتبه ستون (بدون تسک در حال جابه‌جایی) پس از rank (after=true) یا پیش از آن
+// rank خالی یعنی ابتدای ستون برای after و انتهای ستون برای حالت مقابل؛ نتیجه خالی یعنی چنین تسکی نیست
+func columnBoundary(tx *gorm.DB, columnID, excludeTaskID uint, rank string, after bool) (string, error) {
+	query := tx.Model(&models.Task{}).Where("board_column_id = ? AND id <> ?", columnID, excludeTaskID)
+	if after {
+		if rank != "" {
+			query = query.Where("board_rank > ?", rank)
+		}
+		query = query.Select("MIN(board_rank)")
+	} else {
+		if rank != "" {
+			query = query.Where("board_rank < ?", rank)
+		}
+		query = query.Select("MAX(board_rank)")
+	}
+	var result *string
+	if err := query.Scan(&result).Error; err != nil || result == nil {
+		return "", err
+	}
+	return *result, nil
+}
+
+// rebalanceColumn - رتبه‌دهی مجدد ستون با فاصله‌های یکسان و حفظ ترتیب فعلی
+func rebalanceColumn(tx *gorm.DB, columnID, excludeTaskID uint) error {
+	var ids []uint
+	if err := tx.Model(&models.Task{}).
+		Where("board_column_id = ? AND id <> ?", columnID, excludeTaskID).
+		Order("board_rank ASC, id ASC").
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	for i, rank := range utils.EvenRanks(len(ids)) {
+		if err := tx.Model(&models.Task{}).Where("id = ?", ids[i]).Update("board_rank", rank).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BoardMove جابه‌جایی تسک در بورد؛ BeforeID تسکی است که بالای تسک قرار می‌گیرد و AfterID تسک زیر آن
+// بدون هیچ‌کدام تسک به انتهای ستون می‌رود
+type BoardMove struct {
+	TaskID   uint
+	ColumnID uint
+	BeforeID *uint
+	AfterID  *uint
+}
+
+// MoveTask - جابه‌جایی تسک در بورد گروه با رعایت محدودیت WIP و تغییر وضعیت تسک به وضعیت ستون
+// اگر همسایه‌های اعلام‌شده دیگر کنار هم نباشند ErrBoardChanged برمی‌گردد تا کلاینت بورد را دوباره بخواند
+func MoveTask(tx *gorm.DB, groupID uint, move BoardMove, actorID uint) (*models.Task, error) {
+	columns, err := EnsureBoard(tx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var column *models.BoardColumn
+	for i := range columns {
+		if columns[i].ID == move.ColumnID {
+			column = &columns[i]
+		}
+	}
+	if column == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var task models.Task
+	if err := tx.Where("id = ? AND group_id = ? AND is_group_task = ?", move.TaskID, groupID, true).First(&task).Error; err != nil {
+		return nil, err
+	}
+	fromColumn := task.BoardColumnID
+
+	if column.WIPLimit > 0 && (fromColumn == nil || *fromColumn != column.ID) {
+		var count int64
+		if err := tx.Model(&models.Task{}).Where("board_column_id = ?", column.ID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if int(count) >= column.WIPLimit {
+			return nil, ErrWIPLimitReached
+		}
+	}
+
+	rank, err := rankForMove(tx, column.ID, move)
+	if err != nil {
+		return nil, err
+	}
+
+	oldStatus := task.Status
+	if column.Status != oldStatus {
+		if IsStartingStatus(column.Status) {
+			if err := EnsureUnblocked(tx, task.ID); err != nil {
+				return nil, err
+			}
+		}
+		task.Status = column.Status
+		if column.Status == models.StatusCompleted {
+			// انتقال به Done فقط پس از پذیرش کار تمام اعضا
+			if err := EnsureGroupTaskReady(tx, &task); err != nil {
+				return nil, err
+			}
+			if err := PrepareCompletion(tx, &task); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	task.BoardColumnID = &column.ID
+	task.BoardRank = rank
+	if err := tx.Model(&task).Updates(map[string]interface{}{
+		"board_column_id": column.ID,
+		"board_rank":      rank,
+		"status":          task.Status,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if oldStatus != task.Status {
+		if err := RecordTransition(tx, task.ID, oldStatus, task.Status, "board move", &actorID); err != nil {
+			return nil, err
+		}
+		if err := LogStatusChange(tx, &task, actorID, oldStatus, task.Status); err != nil {
+			return nil, err
+		}
+		if err := SyncTaskReminders(tx, &task); err != nil {
+			return nil, err
+		}
+	}
+	if fromColumn == nil || *fromColumn != column.ID {
+		if err := LogTaskActivity(tx, &task, actorID, models.ActivityBoardMoved, map[string]interface{}{
+			"from_column": fromColumn,
+			"to_column":   column.ID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return &task, nil
+}
+
+// rankForMove - رتبه جدید تسک بین همسایه‌های اعلام‌شده؛ رتبه‌های بیش از حد بلند باعث بازسازی ستون می‌شوند
+func rankForMove(tx *gorm.DB, columnID uint, move BoardMove) (string, error) {
+	for attempt := 0; ; attempt++ {
+		lower, upper, err := moveNeighbours(tx, columnID, move)
+		if err != nil {
+			return "", err
+		}
+		rank, err := utils.RankBetween(lower, upper)
+		if err == nil && len(rank) <= maxRankLength {
+			return rank, nil
+		}
+		if attempt > 0 {
+			if err == nil {
+				return rank, nil
+			}
+			return "", err
+		}
+		if err := rebalanceColumn(tx, columnID, move.TaskID); err != nil {
+			return "", err
+		}
+	}
+}
+
+func moveNeighbours(tx *gorm.DB, columnID uint, move BoardMove) (lower, upper string, err error) {
+	neighbourRank := func(id uint) (string, error) {
+		if id == move.TaskID {
+			return "", ErrBoardChanged
+		}
+		var neighbour models.Task
+		if err := tx.Select("id", "board_column_id", "board_rank").First(&neighbour, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", ErrBoardChanged
+			}
+			return "", err
+		}
+		if neighbour.BoardColumnID == nil || *neighbour.BoardColumnID != columnID {
+			return "", ErrBoardChanged
+		}
+		return neighbour.BoardRank, nil
+	}
+
+	switch {
+	case move.BeforeID != nil && move.AfterID != nil:
+		if lower, err = neighbourRank(*move.BeforeID); err != nil {
+			return
+		}
+		if upper, err = neighbourRank(*move.AfterID); err != nil {
+			return
+		}
+		// همسایه‌ها باید هنوز پشت سر هم باشند
+		var next string
+		if next, err = columnBoundary(tx, columnID, move.TaskID, lower, true); err != nil {
+			return
+		}
+		if next != upper {
+			err = ErrBoardChanged
+		}
+	case move.BeforeID != nil:
+		if lower, err = neighbourRank(*move.BeforeID); err != nil {
+			return
+		}
+		upper, err = columnBoundary(tx, columnID, move.TaskID, lower, true)
+	case move.AfterID != nil:
+		if upper, err = neighbourRank(*move.AfterID); err != nil {
+			return
+		}
+		lower, err = columnBoundary(tx, columnID, move.TaskID, upper, false)
+	default:
+		lower, err = columnBoundary(tx, columnID, move.TaskID, "", false)
+	}
+	return
+}
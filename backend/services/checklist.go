@@ -0,0 +1,130 @@
+// backend/services/checklist.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+// ChecklistOwners - کاربرانی که روی تسک چک‌لیست دارند: سازنده تسک شخصی یا اعضای اختصاص‌یافته تسک گروهی
+func ChecklistOwners(tx *gorm.DB, task *models.Task) ([]uint, error) {
+	if !task.IsGroupTask {
+		return []uint{task.CreatorID}, nil
+	}
+	return TaskAssigneeIDs(tx, task.ID)
+}
+
+// ChecklistPercent - درصد آیتم‌های تیک‌خورده چک‌لیست کاربر؛ چک‌لیست خالی صفر است
+func ChecklistPercent(tx *gorm.DB, taskID, userID uint) (int, error) {
+	var total, checked int64
+	items := tx.Model(&models.ChecklistItem{}).Where("task_id = ? AND user_id = ?", taskID, userID)
+	if err := items.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	if err := items.Session(&gorm.Session{}).Where("checked = ?", true).Count(&checked).Error; err != nil {
+		return 0, err
+	}
+	return int(checked * 100 / total), nil
+}
+
+// SyncChecklistProgress - بروزرسانی پیشرفت کاربر از روی چک‌لیست، اگر تسک در این حالت باشد
+// وضعیت تسک هم مانند بروزرسانی دستی پیشرفت تغییر می‌کند؛ شروع تسک مسدود ErrTaskBlocked برمی‌گرداند
+func SyncChecklistProgress(tx *gorm.DB, task *models.Task, userID, actorID uint) error {
+	if !task.ProgressFromChecklist {
+		return nil
+	}
+	percent, err := ChecklistPercent(tx, task.ID, userID)
+	if err != nil {
+		return err
+	}
+	if percent > 0 {
+		if err := EnsureUnblocked(tx, task.ID); err != nil {
+			return err
+		}
+	}
+
+	if !task.IsGroupTask {
+		return syncPersonalChecklist(tx, task, percent, actorID)
+	}
+	return syncGroupChecklist(tx, task, userID, percent, actorID)
+}
+
+// SyncAllChecklistProgress - بروزرسانی پیشرفت تمام صاحبان چک‌لیست، مثلا پس از فعال شدن حالت چک‌لیست
+func SyncAllChecklistProgress(tx *gorm.DB, task *models.Task, actorID uint) error {
+	owners, err := ChecklistOwners(tx, task)
+	if err != nil {
+		return err
+	}
+	for _, userID := range owners {
+		if err := SyncChecklistProgress(tx, task, userID, actorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func syncPersonalChecklist(tx *gorm.DB, task *models.Task, percent int, actorID uint) error {
+	if err := SetTaskProgress(tx, task.ID, task.CreatorID, percent); err != nil {
+		return err
+	}
+
+	oldStatus := task.Status
+	switch {
+	case percent == 100:
+		task.Status = models.StatusCompleted
+	case percent > 0 || oldStatus == models.StatusCompleted:
+		task.Status = models.StatusInProgress
+	}
+	if task.Status == oldStatus {
+		return nil
+	}
+	// تکمیل از روی چک‌لیست هم مانند تکمیل دستی سیاست زیرتسک‌ها را رعایت می‌کند
+	if task.Status == models.StatusCompleted {
+		if err := PrepareCompletion(tx, task); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(task).Update("status", task.Status).Error; err != nil {
+		return err
+	}
+	if err := LogStatusChange(tx, task, actorID, oldStatus, task.Status); err != nil {
+		return err
+	}
+	if task.Status == models.StatusCompleted {
+		if _, err := GenerateNextOccurrence(tx, task); err != nil {
+			return err
+		}
+	}
+	return SyncParentProgress(tx, task.ParentID)
+}
+
+func syncGroupChecklist(tx *gorm.DB, task *models.Task, userID uint, percent int, actorID uint) error {
+	var progress models.GroupTaskProgress
+	err := tx.Where("task_id = ? AND user_id = ?", task.ID, userID).First(&progress).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		progress = models.GroupTaskProgress{TaskID: task.ID, UserID: userID, AssignedBy: task.CreatorID}
+	}
+
+	ApplyMemberProgress(&progress, percent)
+	if err := tx.Save(&progress).Error; err != nil {
+		return err
+	}
+
+	// تکمیل یا بازگشایی تسک از روی کار پذیرفته‌شده اعضا تعیین می‌شود
+	if task.Status == models.StatusPending && percent > 0 {
+		if err := setGroupTaskStatus(tx, task, models.StatusInProgress, actorID); err != nil {
+			return err
+		}
+	}
+	return SyncGroupTaskCompletion(tx, task, actorID)
+}
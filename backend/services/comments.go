@@ -0,0 +1,18 @@
+// backend/services/comments.go
+
+package services
+
+import (
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+// DeleteTaskComments - حذف دیدگاه‌های تسک همراه با تاریخچه آن‌ها
+func DeleteTaskComments(tx *gorm.DB, taskID uint) error {
+	comments := tx.Model(&models.TaskComment{}).Select("id").Where("task_id = ?", taskID)
+	if err := tx.Where("comment_id IN (?)", comments).Delete(&models.CommentRevision{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("task_id = ?", taskID).Delete(&models.TaskComment{}).Error
+}
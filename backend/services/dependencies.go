@@ -0,0 +1,103 @@
+// backend/services/dependencies.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	ErrTaskBlocked     = errors.New("task is blocked by unfinished dependencies")
+)
+
+// WouldCreateCycle - آیا افزودن یال taskID -> dependsOnID حلقه ایجاد می‌کند
+// از dependsOnID در جهت وابستگی‌ها پیمایش می‌کند؛ رسیدن به taskID یعنی حلقه
+func WouldCreateCycle(tx *gorm.DB, taskID, dependsOnID uint) (bool, error) {
+	if taskID == dependsOnID {
+		return true, nil
+	}
+
+	visited := map[uint]bool{dependsOnID: true}
+	frontier := []uint{dependsOnID}
+	for len(frontier) > 0 {
+		var edges []models.TaskDependency
+		if err := tx.Where("task_id IN ?", frontier).Find(&edges).Error; err != nil {
+			return false, err
+		}
+		frontier = frontier[:0]
+		for _, edge := range edges {
+			if edge.DependsOnID == taskID {
+				return true, nil
+			}
+			if !visited[edge.DependsOnID] {
+				visited[edge.DependsOnID] = true
+				frontier = append(frontier, edge.DependsOnID)
+			}
+		}
+	}
+	return false, nil
+}
+
+// AddDependency - ثبت وابستگی پس از بررسی حلقه
+func AddDependency(tx *gorm.DB, taskID, dependsOnID, userID uint) (*models.TaskDependency, error) {
+	cycle, err := WouldCreateCycle(tx, taskID, dependsOnID)
+	if err != nil {
+		return nil, err
+	}
+	if cycle {
+		return nil, ErrDependencyCycle
+	}
+
+	var dependency models.TaskDependency
+	err = tx.Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).First(&dependency).Error
+	if err == nil {
+		return &dependency, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	dependency = models.TaskDependency{
+		TaskID:      taskID,
+		DependsOnID: dependsOnID,
+		CreatedBy:   userID,
+	}
+	if err := tx.Create(&dependency).Error; err != nil {
+		return nil, err
+	}
+	return &dependency, nil
+}
+
+// OpenBlockers - تسک‌های پیش‌نیازی که هنوز تکمیل نشده‌اند؛ پیش‌نیازهای داخل سطل زباله حساب نمی‌شوند
+func OpenBlockers(tx *gorm.DB, taskID uint) ([]models.Task, error) {
+	var blockers []models.Task
+	err := tx.Joins("JOIN task_dependencies ON task_dependencies.depends_on_id = tasks.id").
+		Where("task_dependencies.task_id = ? AND tasks.status <> ?", taskID, models.StatusCompleted).
+		Find(&blockers).Error
+	return blockers, err
+}
+
+// EnsureUnblocked - خطای ErrTaskBlocked اگر پیش‌نیاز تکمیل‌نشده‌ای وجود داشته باشد
+// پیش‌نیاز حذف‌شده تا زمان بازگردانی مسدود نمی‌کند
+func EnsureUnblocked(tx *gorm.DB, taskID uint) error {
+	var count int64
+	if err := tx.Model(&models.TaskDependency{}).
+		Joins("JOIN tasks ON tasks.id = task_dependencies.depends_on_id AND tasks.deleted_at IS NULL").
+		Where("task_dependencies.task_id = ? AND tasks.status <> ?", taskID, models.StatusCompleted).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrTaskBlocked
+	}
+	return nil
+}
+
+// IsStartingStatus - وضعیت‌هایی که برای تسک مسدود مجاز نیستند
+func IsStartingStatus(status models.TaskStatus) bool {
+	return status == models.StatusInProgress || status == models.StatusCompleted
+}
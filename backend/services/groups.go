@@ -0,0 +1,37 @@
+// backend/services/groups.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrGroupArchived تغییر در گروه بایگانی‌شده که فقط خواندنی است
+var ErrGroupArchived = errors.New("group is archived")
+
+// ArchivedGroupIDs - زیرکوئری شناسه گروه‌های بایگانی‌شده
+func ArchivedGroupIDs(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&models.Group{}).Select("id").Where("archived_at IS NOT NULL")
+}
+
+// EnsureGroupWritable - خطای ErrGroupArchived برای گروه بایگانی‌شده
+func EnsureGroupWritable(tx *gorm.DB, groupID uint) error {
+	var group models.Group
+	if err := tx.Select("id", "archived_at").First(&group, groupID).Error; err != nil {
+		return err
+	}
+	if group.ArchivedAt != nil {
+		return ErrGroupArchived
+	}
+	return nil
+}
+
+// LockGroup - قفل ردیف گروه تا پایان تراکنش تا تغییرات همزمان عضویت و مالکیت پشت سر هم اجرا شوند
+func LockGroup(tx *gorm.DB, groupID uint) error {
+	var group models.Group
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&group, groupID).Error
+}
@@ -0,0 +1,284 @@
+// backend/services/invitations.go
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/models"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvitationPending = errors.New("user already has a pending invitation")
+	ErrInvitationClosed  = errors.New("invitation is no longer pending")
+	ErrInvitationExpired = errors.New("invitation has expired")
+)
+
+// InvitationTTL - مدت اعتبار دعوت مستقیم (INVITATION_TTL، پیش‌فرض 14 روز)
+func InvitationTTL() time.Duration {
+	return utils.GetEnvDuration("INVITATION_TTL", 14*24*time.Hour)
+}
+
+func groupName(tx *gorm.DB, groupID uint) string {
+	var group models.Group
+	tx.Unscoped().Select("id", "name").First(&group, groupID)
+	return group.Name
+}
+
+// InviteMember - دعوت کاربر به گروه: عضویت در انتظار، ثبت در تاریخچه دعوت‌ها و اعلان به کاربر
+func InviteMember(tx *gorm.DB, groupID, userID, inviterID uint, role string) (*models.GroupInvitation, error) {
+	var existing models.GroupMember
+	err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&existing).Error
+	switch {
+	case err == nil && existing.Accepted:
+		return nil, ErrAlreadyMember
+	case err == nil:
+		return nil, ErrInvitationPending
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if err := tx.Create(&models.GroupMember{GroupID: groupID, UserID: userID, Role: role, Accepted: false}).Error; err != nil {
+		return nil, err
+	}
+	invitation := models.GroupInvitation{
+		GroupID:   groupID,
+		UserID:    userID,
+		InvitedBy: inviterID,
+		Role:      role,
+		Status:    models.InvitationPending,
+		ExpiresAt: time.Now().Add(InvitationTTL()),
+	}
+	if err := tx.Create(&invitation).Error; err != nil {
+		return nil, err
+	}
+	if err := LogGroupActivity(tx, groupID, inviterID, models.ActivityMemberInvited, map[string]interface{}{
+		"user_id":       userID,
+		"invitation_id": invitation.ID,
+	}); err != nil {
+		return nil, err
+	}
+	return &invitation, notifyInvitee(tx, &invitation)
+}
+
+func notifyInvitee(tx *gorm.DB, invitation *models.GroupInvitation) error {
+	return Notify(tx, []uint{invitation.UserID}, models.NotificationGroupInvitation,
+		"دعوت به گروه",
+		fmt.Sprintf("شما به گروه «%s» دعوت شده‌اید", groupName(tx, invitation.GroupID)),
+		invitation.GroupID)
+}
+
+// pendingInvitation - آخرین دعوت pending کاربر در گروه؛ عضویت‌های در انتظار قدیمی ممکن است دعوتی نداشته باشند
+func pendingInvitation(tx *gorm.DB, groupID, userID uint) (*models.GroupInvitation, error) {
+	var invitation models.GroupInvitation
+	err := tx.Where("group_id = ? AND user_id = ? AND status = ?", groupID, userID, models.InvitationPending).
+		Order("id DESC").First(&invitation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func closeInvitation(tx *gorm.DB, invitation *models.GroupInvitation, status string) error {
+	now := time.Now()
+	invitation.Status = status
+	invitation.RespondedAt = &now
+	return tx.Model(invitation).Select("status", "responded_at").Updates(invitation).Error
+}
+
+// ClosePendingInvitations - بستن دعوت‌های pending کاربر وقتی عضویت در انتظار از مسیر دیگری تعیین تکلیف می‌شود
+// (مثلا پیوستن با لینک دعوت یا حذف توسط مدیر)
+func ClosePendingInvitations(tx *gorm.DB, groupID, userID uint, status string) error {
+	return tx.Model(&models.GroupInvitation{}).
+		Where("group_id = ? AND user_id = ? AND status = ?", groupID, userID, models.InvitationPending).
+		Updates(map[string]interface{}{"status": status, "responded_at": time.Now()}).Error
+}
+
+// pendingMembership - عضویت در انتظار کاربر
+func pendingMembership(tx *gorm.DB, groupID, userID uint) (*models.GroupMember, error) {
+	var member models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+	if member.Accepted {
+		return &member, ErrAlreadyMember
+	}
+	return &member, nil
+}
+
+// AcceptInvitation - پذیرش دعوت توسط کاربر و اعلان به دعوت‌کننده
+func AcceptInvitation(tx *gorm.DB, groupID, userID uint) (*models.GroupMember, error) {
+	member, err := pendingMembership(tx, groupID, userID)
+	if err != nil {
+		return member, err
+	}
+	invitation, err := pendingInvitation(tx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation != nil && !time.Now().Before(invitation.ExpiresAt) {
+		return nil, ErrInvitationExpired
+	}
+
+	member.Accepted = true
+	if err := tx.Model(member).Update("accepted", true).Error; err != nil {
+		return nil, err
+	}
+	if err := LogGroupActivity(tx, groupID, userID, models.ActivityMemberJoined, nil); err != nil {
+		return nil, err
+	}
+	if err := AssignNewMember(tx, groupID, userID); err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return member, nil
+	}
+	if err := closeInvitation(tx, invitation, models.InvitationAccepted); err != nil {
+		return nil, err
+	}
+	return member, Notify(tx, []uint{invitation.InvitedBy}, models.NotificationInvitationAccepted,
+		"دعوت پذیرفته شد",
+		fmt.Sprintf("دعوت شما به گروه «%s» پذیرفته شد", groupName(tx, groupID)),
+		groupID)
+}
+
+// RejectInvitation - رد دعوت توسط کاربر؛ عضویت در انتظار حذف و دعوت‌کننده مطلع می‌شود
+func RejectInvitation(tx *gorm.DB, groupID, userID uint) error {
+	member, err := pendingMembership(tx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	invitation, err := pendingInvitation(tx, groupID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Delete(member).Error; err != nil {
+		return err
+	}
+	if err := LogGroupActivity(tx, groupID, userID, models.ActivityInvitationRejected, nil); err != nil {
+		return err
+	}
+	if invitation == nil {
+		return nil
+	}
+	if err := closeInvitation(tx, invitation, models.InvitationRejected); err != nil {
+		return err
+	}
+	return Notify(tx, []uint{invitation.InvitedBy}, models.NotificationInvitationRejected,
+		"دعوت رد شد",
+		fmt.Sprintf("دعوت شما به گروه «%s» رد شد", groupName(tx, groupID)),
+		groupID)
+}
+
+// RevokeInvitation - لغو دعوت pending توسط مدیر
+func RevokeInvitation(tx *gorm.DB, invitation *models.GroupInvitation, actorID uint) error {
+	if invitation.Status != models.InvitationPending {
+		return ErrInvitationClosed
+	}
+	if err := tx.Where("group_id = ? AND user_id = ? AND accepted = ?", invitation.GroupID, invitation.UserID, false).
+		Delete(&models.GroupMember{}).Error; err != nil {
+		return err
+	}
+	if err := closeInvitation(tx, invitation, models.InvitationRevoked); err != nil {
+		return err
+	}
+	return LogGroupActivity(tx, invitation.GroupID, actorID, models.ActivityInvitationRevoked, map[string]interface{}{
+		"user_id":       invitation.UserID,
+		"invitation_id": invitation.ID,
+	})
+}
+
+// ResendInvitation - ارسال دوباره دعوت؛ دعوت pending تمدید و دوباره اعلان می‌شود
+// و برای دعوت ردشده، لغوشده یا منقضی‌شده یک دعوت جدید ساخته می‌شود
+func ResendInvitation(tx *gorm.DB, invitation *models.GroupInvitation, actorID uint) (*models.GroupInvitation, error) {
+	switch invitation.Status {
+	case models.InvitationAccepted:
+		return nil, ErrInvitationClosed
+	case models.InvitationPending:
+		invitation.ExpiresAt = time.Now().Add(InvitationTTL())
+		if err := tx.Model(invitation).Update("expires_at", invitation.ExpiresAt).Error; err != nil {
+			return nil, err
+		}
+		if err := notifyInvitee(tx, invitation); err != nil {
+			return nil, err
+		}
+	default:
+		fresh, err := InviteMember(tx, invitation.GroupID, invitation.UserID, actorID, invitation.Role)
+		if err != nil {
+			return nil, err
+		}
+		invitation = fresh
+	}
+	if err := LogGroupActivity(tx, invitation.GroupID, actorID, models.ActivityInvitationResent, map[string]interface{}{
+		"user_id":       invitation.UserID,
+		"invitation_id": invitation.ID,
+	}); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ExpireInvitation - انقضای دعوت pending توسط scheduler؛ عضویت در انتظار حذف می‌شود
+func ExpireInvitation(tx *gorm.DB, invitation *models.GroupInvitation) error {
+	if err := tx.Where("group_id = ? AND user_id = ? AND accepted = ?", invitation.GroupID, invitation.UserID, false).
+		Delete(&models.GroupMember{}).Error; err != nil {
+		return err
+	}
+	if err := closeInvitation(tx, invitation, models.InvitationExpired); err != nil {
+		return err
+	}
+	return LogGroupActivity(tx, invitation.GroupID, 0, models.ActivityInvitationExpired, map[string]interface{}{
+		"user_id":       invitation.UserID,
+		"invitation_id": invitation.ID,
+	})
+}
+
+// BackfillInvitations - ساخت دعوت pending برای عضویت‌های در انتظار قدیمی که پیش از تاریخچه دعوت‌ها ساخته شده‌اند
+// انقضای آن‌ها از زمان ساخت عضویت حساب می‌شود تا job انقضا آن‌ها را هم مانند بقیه منقضی کند؛
+// عضویت باقی‌مانده از گروهی که دیگر وجود ندارد با هشدار در لاگ رد می‌شود و بقیه را متوقف نمی‌کند
+func BackfillInvitations(tx *gorm.DB, limit int) (int, error) {
+	var orphans []models.GroupMember
+	if err := tx.Where("accepted = ?", false).
+		Where("NOT EXISTS (?)", tx.Model(&models.GroupInvitation{}).Select("1").
+			Where("group_invitations.group_id = group_members.group_id AND group_invitations.user_id = group_members.user_id AND group_invitations.status = ?", models.InvitationPending)).
+		Order("id ASC").
+		Limit(limit).
+		Find(&orphans).Error; err != nil {
+		return 0, err
+	}
+	created := 0
+	for _, member := range orphans {
+		var group models.Group
+		if err := tx.Unscoped().Select("id", "created_by").First(&group, member.GroupID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				utils.LogWarn("Skipping pending membership of missing group", "member_id", member.ID, "group_id", member.GroupID)
+				continue
+			}
+			return 0, err
+		}
+		if err := tx.Create(&models.GroupInvitation{
+			GroupID:   member.GroupID,
+			UserID:    member.UserID,
+			InvitedBy: group.CreatedBy,
+			Role:      member.Role,
+			Status:    models.InvitationPending,
+			ExpiresAt: member.CreatedAt.Add(InvitationTTL()),
+			CreatedAt: member.CreatedAt,
+		}).Error; err != nil {
+			return 0, err
+		}
+		created++
+	}
+	return created, nil
+}
@@ -0,0 +1,182 @@
+// backend/services/invitations_test.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// membershipOf - عضویت کاربر در گروه؛ nil اگر وجود نداشته باشد
+func membershipOf(t *testing.T, db *gorm.DB, groupID, userID uint) *models.GroupMember {
+	t.Helper()
+	var member models.GroupMember
+	err := db.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("load membership: %v", err)
+	}
+	return &member
+}
+
+func TestInviteAndAccept(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 3)
+	owner, invitee, member := users[0].ID, users[1].ID, users[2].ID
+	group := createGroup(t, db, owner, member)
+
+	if _, err := InviteMember(db, group.ID, member, owner, models.RoleMember); !errors.Is(err, ErrAlreadyMember) {
+		t.Errorf("invite existing member = %v, want ErrAlreadyMember", err)
+	}
+
+	invitation, err := InviteMember(db, group.ID, invitee, owner, models.RoleMember)
+	if err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+	if invitation.Status != models.InvitationPending || !invitation.ExpiresAt.After(time.Now()) {
+		t.Errorf("new invitation = %+v", invitation)
+	}
+	if pending := membershipOf(t, db, group.ID, invitee); pending == nil || pending.Accepted {
+		t.Fatalf("invitee membership = %+v, want pending", pending)
+	}
+	if _, err := InviteMember(db, group.ID, invitee, owner, models.RoleMember); !errors.Is(err, ErrInvitationPending) {
+		t.Errorf("second invite = %v, want ErrInvitationPending", err)
+	}
+
+	if _, err := AcceptInvitation(db, group.ID, invitee); err != nil {
+		t.Fatalf("AcceptInvitation: %v", err)
+	}
+	if joined := membershipOf(t, db, group.ID, invitee); joined == nil || !joined.Accepted {
+		t.Errorf("invitee membership after accept = %+v", joined)
+	}
+	db.First(invitation, invitation.ID)
+	if invitation.Status != models.InvitationAccepted || invitation.RespondedAt == nil {
+		t.Errorf("invitation after accept = %s", invitation.Status)
+	}
+
+	// دعوت پذیرفته‌شده دوباره ارسال یا لغو نمی‌شود
+	if _, err := ResendInvitation(db, invitation, owner); !errors.Is(err, ErrInvitationClosed) {
+		t.Errorf("resend accepted invitation = %v, want ErrInvitationClosed", err)
+	}
+	if err := RevokeInvitation(db, invitation, owner); !errors.Is(err, ErrInvitationClosed) {
+		t.Errorf("revoke accepted invitation = %v, want ErrInvitationClosed", err)
+	}
+}
+
+func TestRejectAndRevokeInvitation(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 3)
+	owner, rejecting, revoked := users[0].ID, users[1].ID, users[2].ID
+	group := createGroup(t, db, owner)
+
+	first, err := InviteMember(db, group.ID, rejecting, owner, models.RoleMember)
+	if err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+	if err := RejectInvitation(db, group.ID, rejecting); err != nil {
+		t.Fatalf("RejectInvitation: %v", err)
+	}
+	if membershipOf(t, db, group.ID, rejecting) != nil {
+		t.Error("rejected invitation should remove the pending membership")
+	}
+	db.First(first, first.ID)
+	if first.Status != models.InvitationRejected {
+		t.Errorf("invitation after reject = %s", first.Status)
+	}
+
+	second, err := InviteMember(db, group.ID, revoked, owner, models.RoleMember)
+	if err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+	if err := RevokeInvitation(db, second, owner); err != nil {
+		t.Fatalf("RevokeInvitation: %v", err)
+	}
+	if membershipOf(t, db, group.ID, revoked) != nil {
+		t.Error("revoked invitation should remove the pending membership")
+	}
+	if _, err := AcceptInvitation(db, group.ID, revoked); !errors.Is(err, ErrNotGroupMember) {
+		t.Errorf("accept revoked invitation = %v, want ErrNotGroupMember", err)
+	}
+}
+
+func TestExpireAndResendInvitation(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 2)
+	owner, invitee := users[0].ID, users[1].ID
+	group := createGroup(t, db, owner)
+
+	invitation, err := InviteMember(db, group.ID, invitee, owner, models.RoleMember)
+	if err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+	db.Model(invitation).Update("expires_at", time.Now().Add(-time.Minute))
+
+	// دعوت منقضی‌شده حتی پیش از اجرای job انقضا قابل پذیرش نیست
+	if _, err := AcceptInvitation(db, group.ID, invitee); !errors.Is(err, ErrInvitationExpired) {
+		t.Fatalf("accept expired invitation = %v, want ErrInvitationExpired", err)
+	}
+
+	db.First(invitation, invitation.ID)
+	if err := ExpireInvitation(db, invitation); err != nil {
+		t.Fatalf("ExpireInvitation: %v", err)
+	}
+	if membershipOf(t, db, group.ID, invitee) != nil {
+		t.Error("expired invitation should remove the pending membership")
+	}
+	if invitation.Status != models.InvitationExpired {
+		t.Errorf("invitation after expiry = %s", invitation.Status)
+	}
+
+	fresh, err := ResendInvitation(db, invitation, owner)
+	if err != nil {
+		t.Fatalf("ResendInvitation: %v", err)
+	}
+	if fresh.ID == invitation.ID || fresh.Status != models.InvitationPending {
+		t.Errorf("resent invitation = %+v, want a new pending invitation", fresh)
+	}
+	if _, err := AcceptInvitation(db, group.ID, invitee); err != nil {
+		t.Errorf("accept resent invitation: %v", err)
+	}
+}
+
+func TestBackfillInvitations(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 3)
+	owner, legacy, invited := users[0].ID, users[1].ID, users[2].ID
+	group := createGroup(t, db, owner)
+
+	// عضویت در انتظار قدیمی بدون دعوت
+	createdAt := time.Now().Add(-2 * InvitationTTL())
+	mustCreate(t, db, &models.GroupMember{GroupID: group.ID, UserID: legacy, Role: models.RoleMember, CreatedAt: createdAt})
+	// عضویت باقی‌مانده از گروهی که دیگر وجود ندارد رد می‌شود؛ هشدار آن در app.log پوشه موقت نوشته می‌شود
+	t.Chdir(t.TempDir())
+	mustCreate(t, db, &models.GroupMember{GroupID: group.ID + 100, UserID: invited, Role: models.RoleMember, CreatedAt: createdAt})
+	if _, err := InviteMember(db, group.ID, invited, owner, models.RoleMember); err != nil {
+		t.Fatalf("InviteMember: %v", err)
+	}
+
+	created, err := BackfillInvitations(db, 100)
+	if err != nil || created != 1 {
+		t.Fatalf("BackfillInvitations = %d, %v; want 1", created, err)
+	}
+	var invitation models.GroupInvitation
+	if err := db.Where("group_id = ? AND user_id = ?", group.ID, legacy).First(&invitation).Error; err != nil {
+		t.Fatalf("load backfilled invitation: %v", err)
+	}
+	if invitation.InvitedBy != owner || invitation.Status != models.InvitationPending {
+		t.Errorf("backfilled invitation = %+v", invitation)
+	}
+	if !invitation.ExpiresAt.Before(time.Now()) {
+		t.Errorf("backfilled invitation expires at %s, want already expired", invitation.ExpiresAt)
+	}
+
+	if created, err := BackfillInvitations(db, 100); err != nil || created != 0 {
+		t.Errorf("second BackfillInvitations = %d, %v; want 0", created, err)
+	}
+}
@@ -0,0 +1,114 @@
+// backend/services/invites.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// inviteCodePurpose کاربرد امضای کدهای دعوت گروه
+const inviteCodePurpose = "group-invite"
+
+var (
+	ErrInviteInvalid   = errors.New("invite code is invalid")
+	ErrInviteExpired   = errors.New("invite has expired")
+	ErrInviteRevoked   = errors.New("invite has been revoked")
+	ErrInviteExhausted = errors.New("invite has reached its max uses")
+	ErrAlreadyMember   = errors.New("user is already a member of the group")
+)
+
+// InviteLinkTTL - مدت اعتبار پیش‌فرض لینک دعوت (INVITE_LINK_TTL، پیش‌فرض 7 روز)
+func InviteLinkTTL() time.Duration {
+	return utils.GetEnvDuration("INVITE_LINK_TTL", 7*24*time.Hour)
+}
+
+// CreateInvite - ساخت لینک دعوت با کد امضاشده
+func CreateInvite(tx *gorm.DB, invite *models.GroupInvite) error {
+	code, err := utils.NewSignedCode(inviteCodePurpose)
+	if err != nil {
+		return err
+	}
+	invite.Code = code
+	if err := tx.Create(invite).Error; err != nil {
+		return err
+	}
+	return LogGroupActivity(tx, invite.GroupID, invite.CreatedBy, models.ActivityInviteCreated, map[string]interface{}{
+		"invite_id":  invite.ID,
+		"role":       invite.Role,
+		"max_uses":   invite.MaxUses,
+		"expires_at": invite.ExpiresAt,
+	})
+}
+
+// RevokeInvite - باطل کردن لینک دعوت؛ عضویت‌هایی که قبلا با آن ایجاد شده‌اند باقی می‌مانند
+func RevokeInvite(tx *gorm.DB, invite *models.GroupInvite, actorID uint) error {
+	if invite.RevokedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	invite.RevokedAt = &now
+	invite.RevokedBy = &actorID
+	if err := tx.Model(invite).Select("revoked_at", "revoked_by").Updates(invite).Error; err != nil {
+		return err
+	}
+	return LogGroupActivity(tx, invite.GroupID, actorID, models.ActivityInviteRevoked, map[string]interface{}{"invite_id": invite.ID})
+}
+
+// InviteUsable - خطای مناسب برای لینک باطل‌شده، منقضی‌شده یا پرشده
+func InviteUsable(invite *models.GroupInvite, now time.Time) error {
+	switch {
+	case invite.RevokedAt != nil:
+		return ErrInviteRevoked
+	case !now.Before(invite.ExpiresAt):
+		return ErrInviteExpired
+	case invite.MaxUses > 0 && invite.Uses >= invite.MaxUses:
+		return ErrInviteExhausted
+	}
+	return nil
+}
+
+// JoinByCode - عضویت کاربر در گروه با کد دعوت؛ دعوت در انتظار کاربر (در صورت وجود) پذیرفته می‌شود
+// ردیف لینک قفل می‌شود تا شمارنده استفاده در درخواست‌های همزمان از MaxUses بیشتر نشود
+func JoinByCode(tx *gorm.DB, code string, userID uint) (*models.GroupMember, error) {
+	if !utils.VerifySignedCode(inviteCodePurpose, code) {
+		return nil, ErrInviteInvalid
+	}
+	var invite models.GroupInvite
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&invite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteInvalid
+		}
+		return nil, err
+	}
+	if err := InviteUsable(&invite, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := EnsureGroupWritable(tx, invite.GroupID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteInvalid
+		}
+		return nil, err
+	}
+
+	member, err := AddAcceptedMember(tx, invite.GroupID, userID, invite.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(&invite).Update("uses", gorm.Expr("uses + 1")).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Create(&models.GroupInviteUse{InviteID: invite.ID, GroupID: invite.GroupID, UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+	if err := LogGroupActivity(tx, invite.GroupID, userID, models.ActivityMemberJoined, map[string]interface{}{"invite_id": invite.ID}); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
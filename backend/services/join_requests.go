@@ -0,0 +1,167 @@
+// backend/services/join_requests.go
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGroupNotJoinable   = errors.New("group does not accept join requests")
+	ErrJoinRequestPending = errors.New("user already has a pending join request")
+	ErrJoinRequestClosed  = errors.New("join request is no longer pending")
+	ErrHasPendingInvite   = errors.New("user has a pending invitation to the group")
+)
+
+// IsValidVisibility - private، discoverable یا open
+func IsValidVisibility(visibility string) bool {
+	switch visibility {
+	case models.GroupPrivate, models.GroupDiscoverable, models.GroupOpen:
+		return true
+	}
+	return false
+}
+
+// DiscoverableGroups - کوئری گروه‌های قابل مشاهده در جستجو: discoverable یا open، بایگانی‌نشده
+func DiscoverableGroups(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&models.Group{}).
+		Where("groups.visibility IN ?", []string{models.GroupDiscoverable, models.GroupOpen}).
+		Where("groups.archived_at IS NULL")
+}
+
+// RequestToJoin - عضویت فوری در گروه open یا ثبت درخواست عضویت در گروه discoverable و اعلان به مدیران
+// گروه private برای کاربران غیرعضو وجود ندارد و ErrGroupNotJoinable برمی‌گردد
+func RequestToJoin(tx *gorm.DB, groupID, userID uint, message string) (*models.GroupJoinRequest, *models.GroupMember, error) {
+	if err := LockGroup(tx, groupID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrGroupNotJoinable
+		}
+		return nil, nil, err
+	}
+	var group models.Group
+	if err := DiscoverableGroups(tx).Where("groups.id = ?", groupID).First(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrGroupNotJoinable
+		}
+		return nil, nil, err
+	}
+
+	var existing models.GroupMember
+	err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&existing).Error
+	switch {
+	case err == nil && existing.Accepted:
+		return nil, nil, ErrAlreadyMember
+	case err == nil:
+		return nil, nil, ErrHasPendingInvite
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil, err
+	}
+
+	if group.Visibility == models.GroupOpen {
+		member, err := AddAcceptedMember(tx, groupID, userID, models.RoleMember)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, member, LogGroupActivity(tx, groupID, userID, models.ActivityMemberJoined, nil)
+	}
+
+	var pending int64
+	if err := tx.Model(&models.GroupJoinRequest{}).
+		Where("group_id = ? AND user_id = ? AND status = ?", groupID, userID, models.JoinRequestPending).
+		Count(&pending).Error; err != nil {
+		return nil, nil, err
+	}
+	if pending > 0 {
+		return nil, nil, ErrJoinRequestPending
+	}
+
+	request := models.GroupJoinRequest{GroupID: groupID, UserID: userID, Message: message, Status: models.JoinRequestPending}
+	if err := tx.Create(&request).Error; err != nil {
+		return nil, nil, err
+	}
+	if err := LogGroupActivity(tx, groupID, userID, models.ActivityJoinRequested, map[string]interface{}{"join_request_id": request.ID}); err != nil {
+		return nil, nil, err
+	}
+	reviewers, err := MembersWith(tx, groupID, models.PermManageMembers)
+	if err != nil {
+		return nil, nil, err
+	}
+	var reviewerIDs []uint
+	if err := reviewers.Pluck("user_id", &reviewerIDs).Error; err != nil {
+		return nil, nil, err
+	}
+	return &request, nil, Notify(tx, reviewerIDs, models.NotificationJoinRequested,
+		"درخواست عضویت جدید",
+		fmt.Sprintf("درخواست عضویت جدیدی برای گروه «%s» ثبت شد", group.Name),
+		groupID)
+}
+
+func closeJoinRequest(tx *gorm.DB, request *models.GroupJoinRequest, status string, reviewerID *uint) error {
+	if request.Status != models.JoinRequestPending {
+		return ErrJoinRequestClosed
+	}
+	now := time.Now()
+	request.Status = status
+	request.ReviewedBy = reviewerID
+	request.ReviewedAt = &now
+	return tx.Model(request).Select("status", "reviewed_by", "reviewed_at").Updates(request).Error
+}
+
+// ApproveJoinRequest - تایید درخواست عضویت و اعلان به کاربر
+func ApproveJoinRequest(tx *gorm.DB, request *models.GroupJoinRequest, actorID uint) (*models.GroupMember, error) {
+	if err := LockGroup(tx, request.GroupID); err != nil {
+		return nil, err
+	}
+	if err := closeJoinRequest(tx, request, models.JoinRequestApproved, &actorID); err != nil {
+		return nil, err
+	}
+	member, err := AddAcceptedMember(tx, request.GroupID, request.UserID, models.RoleMember)
+	if err != nil {
+		return nil, err
+	}
+	if err := LogGroupActivity(tx, request.GroupID, actorID, models.ActivityJoinApproved, map[string]interface{}{
+		"user_id":         request.UserID,
+		"join_request_id": request.ID,
+	}); err != nil {
+		return nil, err
+	}
+	return member, Notify(tx, []uint{request.UserID}, models.NotificationJoinApproved,
+		"درخواست عضویت تایید شد",
+		fmt.Sprintf("درخواست عضویت شما در گروه «%s» تایید شد", groupName(tx, request.GroupID)),
+		request.GroupID)
+}
+
+// DenyJoinRequest - رد درخواست عضویت و اعلان به کاربر
+func DenyJoinRequest(tx *gorm.DB, request *models.GroupJoinRequest, actorID uint) error {
+	if err := closeJoinRequest(tx, request, models.JoinRequestDenied, &actorID); err != nil {
+		return err
+	}
+	if err := LogGroupActivity(tx, request.GroupID, actorID, models.ActivityJoinDenied, map[string]interface{}{
+		"user_id":         request.UserID,
+		"join_request_id": request.ID,
+	}); err != nil {
+		return err
+	}
+	return Notify(tx, []uint{request.UserID}, models.NotificationJoinDenied,
+		"درخواست عضویت رد شد",
+		fmt.Sprintf("درخواست عضویت شما در گروه «%s» رد شد", groupName(tx, request.GroupID)),
+		request.GroupID)
+}
+
+// CancelJoinRequest - لغو درخواست عضویت توسط خود کاربر
+func CancelJoinRequest(tx *gorm.DB, groupID, userID uint) error {
+	var request models.GroupJoinRequest
+	if err := tx.Where("group_id = ? AND user_id = ? AND status = ?", groupID, userID, models.JoinRequestPending).
+		First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrJoinRequestClosed
+		}
+		return err
+	}
+	return closeJoinRequest(tx, &request, models.JoinRequestCancelled, nil)
+}
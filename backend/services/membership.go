@@ -0,0 +1,241 @@
+// backend/services/membership.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOwnerMustTransfer = errors.New("owner must transfer ownership before leaving")
+	ErrLastAdmin         = errors.New("last admin must appoint a successor")
+	ErrNotGroupMember    = errors.New("user is not an accepted member of the group")
+	ErrNoPendingTransfer = errors.New("no pending ownership transfer")
+)
+
+// EnsureSuccessor - خروج عضو نباید گروه را بی‌مالک یا بدون هیچ عضو دارای اجازه manage_members بگذارد
+func EnsureSuccessor(tx *gorm.DB, member *models.GroupMember) error {
+	if member.Role == models.RoleOwner {
+		return ErrOwnerMustTransfer
+	}
+	perms, err := RolePermissions(tx, member.GroupID, member.Role)
+	if err != nil {
+		return err
+	}
+	if !perms[models.PermManageMembers] {
+		return nil
+	}
+	admins, err := MembersWith(tx, member.GroupID, models.PermManageMembers)
+	if err != nil {
+		return err
+	}
+	var others int64
+	if err := admins.Where("user_id <> ?", member.UserID).Count(&others).Error; err != nil {
+		return err
+	}
+	if others == 0 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// AddAcceptedMember - عضویت پذیرفته‌شده کاربر با نقش role؛ دعوت در انتظار او (در صورت وجود) با نقش خودش پذیرفته می‌شود
+func AddAcceptedMember(tx *gorm.DB, groupID, userID uint, role string) (*models.GroupMember, error) {
+	var member models.GroupMember
+	err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error
+	switch {
+	case err == nil && member.Accepted:
+		return nil, ErrAlreadyMember
+	case err == nil:
+		member.Accepted = true
+		if err := tx.Model(&member).Update("accepted", true).Error; err != nil {
+			return nil, err
+		}
+		if err := ClosePendingInvitations(tx, groupID, userID, models.InvitationAccepted); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		member = models.GroupMember{GroupID: groupID, UserID: userID, Role: role, Accepted: true}
+		if err := tx.Create(&member).Error; err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+	return &member, AssignNewMember(tx, groupID, userID)
+}
+
+// RemoveMembership - خروج یا حذف عضو از گروه با بررسی جانشین؛ عضو از تیم‌ها و تسک‌های باز گروه کنار می‌رود
+// و پیشنهادهای انتقال مالکیت مربوط به او لغو می‌شوند
+// action یکی از ActivityMemberLeft یا ActivityMemberRemoved است
+func RemoveMembership(tx *gorm.DB, groupID, userID, actorID uint, action string) error {
+	if err := LockGroup(tx, groupID); err != nil {
+		return err
+	}
+	var member models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotGroupMember
+		}
+		return err
+	}
+	if member.Accepted {
+		if err := EnsureSuccessor(tx, &member); err != nil {
+			return err
+		}
+	} else if err := ClosePendingInvitations(tx, groupID, userID, models.InvitationRevoked); err != nil {
+		return err
+	}
+
+	if err := tx.Delete(&member).Error; err != nil {
+		return err
+	}
+	if err := LeaveGroupTeams(tx, groupID, userID); err != nil {
+		return err
+	}
+	if member.Accepted {
+		if err := UnassignRemovedMember(tx, groupID, userID, actorID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Model(&models.OwnershipTransfer{}).
+		Where("group_id = ? AND status = ? AND (from_user_id = ? OR to_user_id = ?)", groupID, models.TransferPending, userID, userID).
+		Updates(map[string]interface{}{"status": models.TransferCancelled, "responded_at": time.Now()}).Error; err != nil {
+		return err
+	}
+	return LogGroupActivity(tx, groupID, actorID, action, map[string]interface{}{"user_id": userID})
+}
+
+// OfferOwnership - پیشنهاد انتقال مالکیت به یک عضو پذیرفته‌شده؛ پیشنهاد pending قبلی لغو می‌شود
+func OfferOwnership(tx *gorm.DB, groupID, ownerID, toUserID uint) (*models.OwnershipTransfer, error) {
+	if err := LockGroup(tx, groupID); err != nil {
+		return nil, err
+	}
+	var target models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ? AND accepted = ?", groupID, toUserID, true).First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+
+	if err := tx.Model(&models.OwnershipTransfer{}).
+		Where("group_id = ? AND status = ?", groupID, models.TransferPending).
+		Updates(map[string]interface{}{"status": models.TransferCancelled, "responded_at": time.Now()}).Error; err != nil {
+		return nil, err
+	}
+	transfer := models.OwnershipTransfer{
+		GroupID:    groupID,
+		FromUserID: ownerID,
+		ToUserID:   toUserID,
+		Status:     models.TransferPending,
+	}
+	if err := tx.Create(&transfer).Error; err != nil {
+		return nil, err
+	}
+	if err := LogGroupActivity(tx, groupID, ownerID, models.ActivityOwnershipOffered, map[string]interface{}{"user_id": toUserID}); err != nil {
+		return nil, err
+	}
+	return &transfer, Notify(tx, []uint{toUserID}, models.NotificationOwnershipOffered,
+		"پیشنهاد مالکیت گروه", "مالک گروه پیشنهاد داده مالکیت گروه به شما منتقل شود", groupID)
+}
+
+// pendingTransfer - پیشنهاد pending گروه که کاربر طرف آن است
+func pendingTransfer(tx *gorm.DB, groupID uint, column string, userID uint) (*models.OwnershipTransfer, error) {
+	var transfer models.OwnershipTransfer
+	if err := tx.Where("group_id = ? AND status = ? AND "+column+" = ?", groupID, models.TransferPending, userID).
+		First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoPendingTransfer
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// AcceptOwnership - پذیرش مالکیت توسط عضو مقصد؛ مالک قبلی admin می‌شود و Group.CreatedBy به مالک جدید می‌رسد
+func AcceptOwnership(tx *gorm.DB, groupID, userID uint) (*models.OwnershipTransfer, error) {
+	if err := LockGroup(tx, groupID); err != nil {
+		return nil, err
+	}
+	transfer, err := pendingTransfer(tx, groupID, "to_user_id", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// پیشنهاددهنده باید هنوز مالک و عضو مقصد هنوز عضو پذیرفته‌شده باشد
+	var owner, target models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ? AND role = ?", groupID, transfer.FromUserID, models.RoleOwner).First(&owner).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoPendingTransfer
+		}
+		return nil, err
+	}
+	if err := tx.Where("group_id = ? AND user_id = ? AND accepted = ?", groupID, userID, true).First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+
+	if err := tx.Model(&owner).Update("role", models.RoleAdmin).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&target).Update("role", models.RoleOwner).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&models.Group{}).Where("id = ?", groupID).Update("created_by", userID).Error; err != nil {
+		return nil, err
+	}
+
+	if err := closeTransfer(tx, transfer, models.TransferAccepted); err != nil {
+		return nil, err
+	}
+	if err := LogGroupActivity(tx, groupID, userID, models.ActivityOwnershipTransferred, map[string]interface{}{
+		"from": transfer.FromUserID,
+		"to":   userID,
+	}); err != nil {
+		return nil, err
+	}
+	return transfer, Notify(tx, []uint{transfer.FromUserID}, models.NotificationOwnershipAccepted,
+		"انتقال مالکیت انجام شد", "مالکیت گروه پذیرفته شد و نقش شما به admin تغییر کرد", groupID)
+}
+
+// DeclineOwnership - رد پیشنهاد مالکیت توسط عضو مقصد
+func DeclineOwnership(tx *gorm.DB, groupID, userID uint) (*models.OwnershipTransfer, error) {
+	transfer, err := pendingTransfer(tx, groupID, "to_user_id", userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := closeTransfer(tx, transfer, models.TransferDeclined); err != nil {
+		return nil, err
+	}
+	if err := LogGroupActivity(tx, groupID, userID, models.ActivityOwnershipDeclined, nil); err != nil {
+		return nil, err
+	}
+	return transfer, Notify(tx, []uint{transfer.FromUserID}, models.NotificationOwnershipDeclined,
+		"انتقال مالکیت رد شد", "پیشنهاد انتقال مالکیت گروه رد شد", groupID)
+}
+
+// CancelOwnership - لغو پیشنهاد مالکیت توسط مالک
+func CancelOwnership(tx *gorm.DB, groupID, ownerID uint) (*models.OwnershipTransfer, error) {
+	transfer, err := pendingTransfer(tx, groupID, "from_user_id", ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := closeTransfer(tx, transfer, models.TransferCancelled); err != nil {
+		return nil, err
+	}
+	return transfer, LogGroupActivity(tx, groupID, ownerID, models.ActivityOwnershipCancelled, map[string]interface{}{"user_id": transfer.ToUserID})
+}
+
+func closeTransfer(tx *gorm.DB, transfer *models.OwnershipTransfer, status string) error {
+	now := time.Now()
+	transfer.Status = status
+	transfer.RespondedAt = &now
+	return tx.Model(transfer).Select("status", "responded_at").Updates(transfer).Error
+}
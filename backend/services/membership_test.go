@@ -0,0 +1,152 @@
+// backend/services/membership_test.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// memberRole - نقش فعلی کاربر در گروه
+func memberRole(t *testing.T, db *gorm.DB, groupID, userID uint) string {
+	t.Helper()
+	var member models.GroupMember
+	if err := db.Where("group_id = ? AND user_id = ?", groupID, userID).First(&member).Error; err != nil {
+		t.Fatalf("load member %d: %v", userID, err)
+	}
+	return member.Role
+}
+
+func TestOwnershipTransferAccept(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 3)
+	owner, target, outsider := users[0].ID, users[1].ID, users[2].ID
+	group := createGroup(t, db, owner, target)
+
+	if _, err := OfferOwnership(db, group.ID, owner, outsider); !errors.Is(err, ErrNotGroupMember) {
+		t.Fatalf("offer to non-member = %v, want ErrNotGroupMember", err)
+	}
+
+	transfer, err := OfferOwnership(db, group.ID, owner, target)
+	if err != nil {
+		t.Fatalf("OfferOwnership: %v", err)
+	}
+	// تا پذیرش، مالک فعلی تغییر نمی‌کند و نمی‌تواند گروه را ترک کند
+	if role := memberRole(t, db, group.ID, owner); role != models.RoleOwner {
+		t.Errorf("owner role before accept = %s", role)
+	}
+	if err := RemoveMembership(db, group.ID, owner, owner, models.ActivityMemberLeft); !errors.Is(err, ErrOwnerMustTransfer) {
+		t.Errorf("owner leaving = %v, want ErrOwnerMustTransfer", err)
+	}
+	if _, err := AcceptOwnership(db, group.ID, outsider); !errors.Is(err, ErrNoPendingTransfer) {
+		t.Errorf("accept by someone else = %v, want ErrNoPendingTransfer", err)
+	}
+
+	accepted, err := AcceptOwnership(db, group.ID, target)
+	if err != nil {
+		t.Fatalf("AcceptOwnership: %v", err)
+	}
+	if accepted.ID != transfer.ID || accepted.Status != models.TransferAccepted || accepted.RespondedAt == nil {
+		t.Errorf("accepted transfer = %+v", accepted)
+	}
+	if role := memberRole(t, db, group.ID, target); role != models.RoleOwner {
+		t.Errorf("new owner role = %s, want owner", role)
+	}
+	if role := memberRole(t, db, group.ID, owner); role != models.RoleAdmin {
+		t.Errorf("previous owner role = %s, want admin", role)
+	}
+	var reloaded models.Group
+	db.First(&reloaded, group.ID)
+	if reloaded.CreatedBy != target {
+		t.Errorf("group created_by = %d, want %d", reloaded.CreatedBy, target)
+	}
+
+	// پیشنهاد بسته‌شده دوباره قابل پذیرش نیست
+	if _, err := AcceptOwnership(db, group.ID, target); !errors.Is(err, ErrNoPendingTransfer) {
+		t.Errorf("second accept = %v, want ErrNoPendingTransfer", err)
+	}
+}
+
+func TestOwnershipTransferSupersedeDeclineCancel(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 3)
+	owner, first, second := users[0].ID, users[1].ID, users[2].ID
+	group := createGroup(t, db, owner, first, second)
+
+	old, err := OfferOwnership(db, group.ID, owner, first)
+	if err != nil {
+		t.Fatalf("OfferOwnership(first): %v", err)
+	}
+	if _, err := OfferOwnership(db, group.ID, owner, second); err != nil {
+		t.Fatalf("OfferOwnership(second): %v", err)
+	}
+
+	// پیشنهاد جدید پیشنهاد pending قبلی را لغو می‌کند
+	db.First(old, old.ID)
+	if old.Status != models.TransferCancelled {
+		t.Errorf("superseded transfer status = %s, want cancelled", old.Status)
+	}
+	if _, err := AcceptOwnership(db, group.ID, first); !errors.Is(err, ErrNoPendingTransfer) {
+		t.Errorf("accept superseded offer = %v, want ErrNoPendingTransfer", err)
+	}
+
+	declined, err := DeclineOwnership(db, group.ID, second)
+	if err != nil || declined.Status != models.TransferDeclined {
+		t.Fatalf("DeclineOwnership = %+v, %v", declined, err)
+	}
+	if role := memberRole(t, db, group.ID, owner); role != models.RoleOwner {
+		t.Errorf("owner role after decline = %s", role)
+	}
+	if _, err := CancelOwnership(db, group.ID, owner); !errors.Is(err, ErrNoPendingTransfer) {
+		t.Errorf("cancel after decline = %v, want ErrNoPendingTransfer", err)
+	}
+
+	if _, err := OfferOwnership(db, group.ID, owner, first); err != nil {
+		t.Fatalf("OfferOwnership again: %v", err)
+	}
+	cancelled, err := CancelOwnership(db, group.ID, owner)
+	if err != nil || cancelled.Status != models.TransferCancelled {
+		t.Fatalf("CancelOwnership = %+v, %v", cancelled, err)
+	}
+}
+
+func TestOwnershipTransferCancelledWhenTargetLeaves(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 2)
+	owner, target := users[0].ID, users[1].ID
+	group := createGroup(t, db, owner, target)
+
+	transfer, err := OfferOwnership(db, group.ID, owner, target)
+	if err != nil {
+		t.Fatalf("OfferOwnership: %v", err)
+	}
+	if err := RemoveMembership(db, group.ID, target, target, models.ActivityMemberLeft); err != nil {
+		t.Fatalf("RemoveMembership: %v", err)
+	}
+	db.First(transfer, transfer.ID)
+	if transfer.Status != models.TransferCancelled {
+		t.Errorf("transfer status after target left = %s, want cancelled", transfer.Status)
+	}
+}
+
+func TestEnsureSuccessorLastAdmin(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 2)
+
+	// گروه قدیمی بدون مالک که فقط یک مدیر دارد
+	group := models.Group{Name: "legacy", CreatedBy: users[0].ID}
+	mustCreate(t, db, &group)
+	admin := models.GroupMember{GroupID: group.ID, UserID: users[0].ID, Role: models.RoleAdmin, Accepted: true}
+	member := models.GroupMember{GroupID: group.ID, UserID: users[1].ID, Role: models.RoleMember, Accepted: true}
+	mustCreate(t, db, &admin, &member)
+
+	if err := EnsureSuccessor(db, &admin); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("last admin leaving = %v, want ErrLastAdmin", err)
+	}
+	if err := EnsureSuccessor(db, &member); err != nil {
+		t.Errorf("member leaving = %v, want nil", err)
+	}
+}
@@ -0,0 +1,50 @@
+// backend/services/notifications.go
+
+package services
+
+import (
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+// Notify - ایجاد اعلان برای هر کاربر (یک‌بار برای هر کاربر، حتی اگر تکراری داده شود)
+func Notify(tx *gorm.DB, userIDs []uint, notificationType, title, message string, relatedID uint) error {
+	seen := make(map[uint]bool, len(userIDs))
+	var notifications []models.Notification
+	for _, userID := range userIDs {
+		if userID == 0 || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		notifications = append(notifications, models.Notification{
+			UserID:    userID,
+			Type:      notificationType,
+			Title:     title,
+			Message:   message,
+			RelatedID: relatedID,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+	return tx.Create(&notifications).Error
+}
+
+// GroupAdminIDs - شناسه اعضای پذیرفته‌شده‌ای که اجازه مدیریت تسک‌های گروه را دارند
+func GroupAdminIDs(tx *gorm.DB, groupID uint) ([]uint, error) {
+	members, err := MembersWith(tx, groupID, models.PermManageTasks)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	err = members.Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// TaskAssigneeIDs - شناسه کاربرانی که تسک به آن‌ها اختصاص داده شده
+func TaskAssigneeIDs(tx *gorm.DB, taskID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Model(&models.TaskAssignment{}).Where("task_id = ?", taskID).Pluck("user_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,124 @@
+// backend/services/permissions.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrForbidden کاربر عضو پذیرفته‌شده گروه نیست یا نقش او اجازه لازم را ندارد
+	ErrForbidden = errors.New("permission denied")
+	// ErrGroupNotFound گروه وجود ندارد یا در سطل زباله است
+	ErrGroupNotFound = errors.New("group not found")
+)
+
+// RolePermissions - اجازه‌های یک نقش پیش‌فرض یا سفارشی در گروه؛ نقش ناشناخته هیچ اجازه‌ای ندارد
+func RolePermissions(tx *gorm.DB, groupID uint, role string) (map[models.Permission]bool, error) {
+	perms := make(map[models.Permission]bool)
+	if builtin, ok := models.BuiltinRoles[role]; ok {
+		for _, perm := range builtin {
+			perms[perm] = true
+		}
+		return perms, nil
+	}
+	var custom models.GroupRole
+	if err := tx.Where("group_id = ? AND name = ?", groupID, role).First(&custom).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return perms, nil
+		}
+		return nil, err
+	}
+	for _, perm := range custom.PermissionList() {
+		perms[perm] = true
+	}
+	return perms, nil
+}
+
+// AuthorizeGroup - عضویت پذیرفته‌شده کاربر در صورتی که نقش او اجازه perm را داشته باشد
+// perm خالی فقط عضویت را بررسی می‌کند؛ در غیر این صورت ErrForbidden برمی‌گردد
+// گروه حذف‌شده تا بازگردانی قابل استفاده نیست و ErrGroupNotFound برمی‌گرداند
+func AuthorizeGroup(tx *gorm.DB, groupID, userID uint, perm models.Permission) (*models.GroupMember, error) {
+	var count int64
+	if err := tx.Model(&models.Group{}).Where("id = ?", groupID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrGroupNotFound
+	}
+	return AuthorizeMember(tx, groupID, userID, perm)
+}
+
+// AuthorizeMember - مانند AuthorizeGroup بدون بررسی وضعیت گروه؛ برای بازگردانی گروه از سطل زباله
+func AuthorizeMember(tx *gorm.DB, groupID, userID uint, perm models.Permission) (*models.GroupMember, error) {
+	var member models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ? AND accepted = ?", groupID, userID, true).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrForbidden
+		}
+		return nil, err
+	}
+	if perm == "" {
+		return &member, nil
+	}
+	perms, err := RolePermissions(tx, groupID, member.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !perms[perm] {
+		return nil, ErrForbidden
+	}
+	return &member, nil
+}
+
+// RolesWith - نام نقش‌های پیش‌فرض و سفارشی گروه که اجازه perm را دارند
+func RolesWith(tx *gorm.DB, groupID uint, perm models.Permission) ([]string, error) {
+	var roles []string
+	for _, role := range []string{models.RoleOwner, models.RoleAdmin, models.RoleEditor, models.RoleMember, models.RoleViewer} {
+		for _, p := range models.BuiltinRoles[role] {
+			if p == perm {
+				roles = append(roles, role)
+				break
+			}
+		}
+	}
+	var custom []models.GroupRole
+	if err := tx.Where("group_id = ?", groupID).Find(&custom).Error; err != nil {
+		return nil, err
+	}
+	for _, role := range custom {
+		for _, p := range role.PermissionList() {
+			if p == perm {
+				roles = append(roles, role.Name)
+				break
+			}
+		}
+	}
+	return roles, nil
+}
+
+// MembersWith - کوئری شناسه اعضای پذیرفته‌شده‌ای که نقششان اجازه perm را دارد (برای استفاده در IN)
+func MembersWith(tx *gorm.DB, groupID uint, perm models.Permission) (*gorm.DB, error) {
+	roles, err := RolesWith(tx, groupID, perm)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Model(&models.GroupMember{}).Select("user_id").
+		Where("group_id = ? AND accepted = ? AND role IN ?", groupID, true, roles), nil
+}
+
+// IsValidRole - نقش پیش‌فرض (به جز owner که فقط با انتقال مالکیت داده می‌شود) یا نقش سفارشی تعریف‌شده در گروه
+func IsValidRole(tx *gorm.DB, groupID uint, role string) (bool, error) {
+	if role == models.RoleOwner {
+		return false, nil
+	}
+	if _, ok := models.BuiltinRoles[role]; ok {
+		return true, nil
+	}
+	var count int64
+	err := tx.Model(&models.GroupRole{}).Where("group_id = ? AND name = ?", groupID, role).Count(&count).Error
+	return count > 0, err
+}
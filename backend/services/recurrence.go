@@ -0,0 +1,147 @@
+// backend/services/recurrence.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OccurrenceTime - محاسبه زمان رخداد شماره index یک سری
+// رخدادها همیشه از DTSTART محاسبه می‌شوند تا تغییر یک رخداد منفرد روی بقیه اثر نگذارد
+func OccurrenceTime(series *models.TaskRecurrence, rule *utils.RRule, index int) time.Time {
+	at := series.StartsAt
+	for i := 1; i < index; i++ {
+		at = rule.Next(series.StartsAt, at)
+	}
+	return at
+}
+
+// GenerateNextOccurrence - ساخت رخداد بعدی سری پس از تکمیل یا رد شدن task
+// اگر رخداد بعدی قبلاً ساخته شده باشد همان برگردانده می‌شود؛ پایان سری nil برمی‌گرداند
+func GenerateNextOccurrence(tx *gorm.DB, task *models.Task) (*models.Task, error) {
+	if task.RecurrenceID == nil {
+		return nil, nil
+	}
+
+	var series models.TaskRecurrence
+	if err := tx.First(&series, *task.RecurrenceID).Error; err != nil {
+		return nil, err
+	}
+	if !series.Active {
+		return nil, nil
+	}
+
+	index := task.OccurrenceIndex + 1
+
+	var existing models.Task
+	err := tx.Where("recurrence_id = ? AND occurrence_index = ?", series.ID, index).First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	rule, err := utils.ParseRRule(series.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate := OccurrenceTime(&series, rule, index)
+	if !rule.Permits(index, dueDate) {
+		series.Active = false
+		return nil, tx.Save(&series).Error
+	}
+
+	next := models.Task{
+		Title:           series.Title,
+		Description:     series.Description,
+		Priority:        series.Priority,
+		CreatorID:       series.CreatorID,
+		Status:          models.StatusPending,
+		DueDate:         &dueDate,
+		RecurrenceID:    &series.ID,
+		OccurrenceIndex: index,
+	}
+	if err := tx.Create(&next).Error; err != nil {
+		return nil, err
+	}
+
+	series.Occurrences = index
+	if err := tx.Save(&series).Error; err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+// SplitSeries - شروع سری جدید از task برای ویرایش «این رخداد و رخدادهای بعدی»
+// سری قبلی غیرفعال می‌شود و task اولین رخداد سری جدید خواهد بود
+func SplitSeries(tx *gorm.DB, task *models.Task, rule *utils.RRule) (*models.TaskRecurrence, error) {
+	var old models.TaskRecurrence
+	if err := tx.First(&old, *task.RecurrenceID).Error; err != nil {
+		return nil, err
+	}
+
+	startsAt := task.CreatedAt
+	if task.DueDate != nil {
+		startsAt = *task.DueDate
+	}
+
+	series := models.TaskRecurrence{
+		CreatorID:   old.CreatorID,
+		Rule:        rule.String(),
+		StartsAt:    startsAt,
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		Occurrences: 1,
+		Active:      true,
+	}
+	if err := tx.Create(&series).Error; err != nil {
+		return nil, err
+	}
+
+	// رخدادهای بعدیِ تکمیل‌نشده به سری جدید منتقل می‌شوند؛ رخدادی که خارج از COUNT/UNTIL قاعده جدید بیفتد به سطل زباله می‌رود
+	var later []models.Task
+	if err := tx.Where("recurrence_id = ? AND occurrence_index > ? AND status <> ?", old.ID, task.OccurrenceIndex, models.StatusCompleted).
+		Find(&later).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range later {
+		index := t.OccurrenceIndex - task.OccurrenceIndex + 1
+		dueDate := OccurrenceTime(&series, rule, index)
+		if !rule.Permits(index, dueDate) {
+			if err := TrashTask(tx, &t, SubtasksDelete); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := tx.Model(&t).Updates(map[string]interface{}{
+			"recurrence_id":    series.ID,
+			"occurrence_index": index,
+			"due_date":         dueDate,
+		}).Error; err != nil {
+			return nil, err
+		}
+		if index > series.Occurrences {
+			series.Occurrences = index
+		}
+	}
+	if err := tx.Save(&series).Error; err != nil {
+		return nil, err
+	}
+
+	old.Active = false
+	if err := tx.Save(&old).Error; err != nil {
+		return nil, err
+	}
+
+	task.RecurrenceID = &series.ID
+	task.OccurrenceIndex = 1
+	return &series, nil
+}
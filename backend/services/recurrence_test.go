@@ -0,0 +1,120 @@
+// backend/services/recurrence_test.go
+
+package services
+
+import (
+	"task-manager/models"
+	"task-manager/utils"
+	"testing"
+	"time"
+)
+
+func TestOccurrenceTime(t *testing.T) {
+	series := &models.TaskRecurrence{StartsAt: time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC)}
+	rule, _ := utils.ParseRRule("FREQ=MONTHLY")
+
+	// روز ماه از DTSTART گرفته می‌شود؛ محدود شدن به 28 در فوریه به مارس منتقل نمی‌شود
+	want := time.Date(2025, time.March, 31, 9, 0, 0, 0, time.UTC)
+	if got := OccurrenceTime(series, rule, 3); !got.Equal(want) {
+		t.Errorf("OccurrenceTime(3) = %s, want %s", got, want)
+	}
+	if got := OccurrenceTime(series, rule, 1); !got.Equal(series.StartsAt) {
+		t.Errorf("OccurrenceTime(1) = %s, want DTSTART", got)
+	}
+}
+
+func TestGenerateNextOccurrence(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 1)
+
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	series := models.TaskRecurrence{
+		CreatorID:   users[0].ID,
+		Rule:        "FREQ=WEEKLY;COUNT=2",
+		StartsAt:    start,
+		Title:       "weekly report",
+		Occurrences: 1,
+		Active:      true,
+	}
+	mustCreate(t, db, &series)
+	first := models.Task{
+		Title:           series.Title,
+		CreatorID:       users[0].ID,
+		Status:          models.StatusCompleted,
+		DueDate:         &start,
+		RecurrenceID:    &series.ID,
+		OccurrenceIndex: 1,
+	}
+	mustCreate(t, db, &first)
+
+	second, err := GenerateNextOccurrence(db, &first)
+	if err != nil || second == nil {
+		t.Fatalf("GenerateNextOccurrence = %v, %v", second, err)
+	}
+	if second.OccurrenceIndex != 2 || !second.DueDate.Equal(start.AddDate(0, 0, 7)) {
+		t.Errorf("second occurrence = #%d due %s", second.OccurrenceIndex, second.DueDate)
+	}
+	if second.Status != models.StatusPending {
+		t.Errorf("second occurrence status = %s, want pending", second.Status)
+	}
+
+	// تکمیل دوباره همان رخداد نباید رخداد تکراری بسازد
+	again, err := GenerateNextOccurrence(db, &first)
+	if err != nil || again == nil || again.ID != second.ID {
+		t.Fatalf("GenerateNextOccurrence again = %v, %v; want existing #%d", again, err, second.ID)
+	}
+
+	// COUNT=2 تمام شده است و سری غیرفعال می‌شود
+	third, err := GenerateNextOccurrence(db, second)
+	if err != nil || third != nil {
+		t.Fatalf("GenerateNextOccurrence after COUNT = %v, %v; want nil", third, err)
+	}
+	db.First(&series, series.ID)
+	if series.Active {
+		t.Error("series should be inactive after its last occurrence")
+	}
+	if series.Occurrences != 2 {
+		t.Errorf("series occurrences = %d, want 2", series.Occurrences)
+	}
+}
+
+func TestSplitSeries(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 1)
+
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	old := models.TaskRecurrence{CreatorID: users[0].ID, Rule: "FREQ=WEEKLY", StartsAt: start, Title: "standup", Occurrences: 4, Active: true}
+	mustCreate(t, db, &old)
+	tasks := make([]models.Task, 4)
+	for i := range tasks {
+		due := start.AddDate(0, 0, 7*i)
+		tasks[i] = models.Task{Title: old.Title, CreatorID: users[0].ID, Status: models.StatusPending, DueDate: &due, RecurrenceID: &old.ID, OccurrenceIndex: i + 1}
+		mustCreate(t, db, &tasks[i])
+	}
+
+	// از رخداد دوم به بعد روزانه و فقط دو رخداد؛ رخداد چهارم خارج از COUNT می‌افتد
+	rule, _ := utils.ParseRRule("FREQ=DAILY;COUNT=2")
+	series, err := SplitSeries(db, &tasks[1], rule)
+	if err != nil {
+		t.Fatalf("SplitSeries: %v", err)
+	}
+	if tasks[1].RecurrenceID == nil || *tasks[1].RecurrenceID != series.ID || tasks[1].OccurrenceIndex != 1 {
+		t.Errorf("split task = series %v #%d, want series %d #1", tasks[1].RecurrenceID, tasks[1].OccurrenceIndex, series.ID)
+	}
+
+	var moved models.Task
+	db.First(&moved, tasks[2].ID)
+	if *moved.RecurrenceID != series.ID || moved.OccurrenceIndex != 2 || !moved.DueDate.Equal(tasks[1].DueDate.AddDate(0, 0, 1)) {
+		t.Errorf("moved occurrence = series %d #%d due %s", *moved.RecurrenceID, moved.OccurrenceIndex, moved.DueDate)
+	}
+	if taskExists(t, db, tasks[3].ID) {
+		t.Error("occurrence outside the new COUNT should be in the trash")
+	}
+	if series.Occurrences != 2 {
+		t.Errorf("series occurrences = %d, want 2", series.Occurrences)
+	}
+	db.First(&old, old.ID)
+	if old.Active {
+		t.Error("old series should be inactive after the split")
+	}
+}
@@ -0,0 +1,223 @@
+// backend/services/reminders.go
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"task-manager/models"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeadlineExpr مهلت تسک در SQL: due_date و در نبود آن end_time
+const DeadlineExpr = "COALESCE(tasks.due_date, tasks.end_time)"
+
+// OpenStatuses وضعیت‌هایی که تسک هنوز باید انجام شود
+var OpenStatuses = []models.TaskStatus{models.StatusPending, models.StatusInProgress}
+
+// TaskDeadline - مهلت تسک (due_date و در نبود آن end_time)
+func TaskDeadline(task *models.Task) *time.Time {
+	if task.DueDate != nil {
+		return task.DueDate
+	}
+	return task.EndTime
+}
+
+func isOpen(status models.TaskStatus) bool {
+	for _, s := range OpenStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultReminderLeads - فاصله‌های پیش‌فرض (دقیقه) برای کاربرانی که قانون یادآوری ندارند
+// از REMINDER_DEFAULT_LEADS خوانده می‌شود، مثلا "1d,2h"؛ مقدار خالی یعنی فقط 1d
+func DefaultReminderLeads() []int {
+	raw := os.Getenv("REMINDER_DEFAULT_LEADS")
+	if raw == "" {
+		raw = "1d"
+	}
+	var leads []int
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part == "" || part == "none" {
+			continue
+		}
+		d, err := utils.ParseFriendlyDuration(part)
+		if err != nil {
+			utils.LogWarn("Invalid reminder lead in environment, ignoring", "REMINDER_DEFAULT_LEADS", part)
+			continue
+		}
+		leads = append(leads, int(d/time.Minute))
+	}
+	return leads
+}
+
+// ReminderRecipients - سازنده تسک شخصی یا اعضای اختصاص‌یافته‌ای که هنوز تسک گروهی را تمام نکرده‌اند
+func ReminderRecipients(tx *gorm.DB, task *models.Task) ([]uint, error) {
+	if !task.IsGroupTask {
+		return []uint{task.CreatorID}, nil
+	}
+	var ids []uint
+	err := tx.Model(&models.TaskAssignment{}).
+		Where("task_id = ? AND completed = ?", task.ID, false).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// ReminderLeads - قوانین مخصوص تسک در اولویت‌اند، سپس قوانین پیش‌فرض کاربر و در نهایت پیش‌فرض سیستم
+func ReminderLeads(tx *gorm.DB, userID, taskID uint) ([]int, error) {
+	var leads []int
+	if err := tx.Model(&models.ReminderRule{}).
+		Where("user_id = ? AND task_id = ?", userID, taskID).
+		Pluck("lead_minutes", &leads).Error; err != nil {
+		return nil, err
+	}
+	if len(leads) > 0 {
+		return leads, nil
+	}
+	if err := tx.Model(&models.ReminderRule{}).
+		Where("user_id = ? AND task_id IS NULL", userID).
+		Pluck("lead_minutes", &leads).Error; err != nil {
+		return nil, err
+	}
+	if len(leads) > 0 {
+		return leads, nil
+	}
+	return DefaultReminderLeads(), nil
+}
+
+// MaxReminderLead - بزرگ‌ترین فاصله یادآوری تعریف‌شده؛ تسک‌هایی که مهلتشان دورتر است هنوز یادآوری ندارند
+func MaxReminderLead(tx *gorm.DB) (time.Duration, error) {
+	max := 0
+	for _, lead := range DefaultReminderLeads() {
+		if lead > max {
+			max = lead
+		}
+	}
+	var stored *int
+	if err := tx.Model(&models.ReminderRule{}).Select("MAX(lead_minutes)").Scan(&stored).Error; err != nil {
+		return 0, err
+	}
+	if stored != nil && *stored > max {
+		max = *stored
+	}
+	return time.Duration(max) * time.Minute, nil
+}
+
+func reminderKey(taskID, userID uint, lead int, deadline time.Time) string {
+	return fmt.Sprintf("%d:%d:%d:%d", taskID, userID, lead, deadline.Unix())
+}
+
+// SyncTaskReminders - هم‌گام‌سازی صف یادآوری تسک با مهلت، گیرندگان و قوانین فعلی
+// یادآوری‌های در انتظاری که دیگر معتبر نیستند لغو و یادآوری‌های جاافتاده اضافه می‌شوند؛
+// DedupeKey یکتاست، پس اجرای دوباره (مثلا پس از راه‌اندازی مجدد) رکورد تکراری نمی‌سازد
+func SyncTaskReminders(tx *gorm.DB, task *models.Task) error {
+	deadline := TaskDeadline(task)
+	pending := tx.Model(&models.Reminder{}).Where("task_id = ? AND status = ?", task.ID, models.ReminderPending)
+	if deadline == nil || !isOpen(task.Status) {
+		return pending.Update("status", models.ReminderCancelled).Error
+	}
+
+	recipients, err := ReminderRecipients(tx, task)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	var reminders []models.Reminder
+	for _, userID := range recipients {
+		leads, err := ReminderLeads(tx, userID, task.ID)
+		if err != nil {
+			return err
+		}
+		for _, lead := range leads {
+			key := reminderKey(task.ID, userID, lead, *deadline)
+			keys = append(keys, key)
+
+			remindAt := deadline.Add(-time.Duration(lead) * time.Minute)
+			// یادآوری قبل از ساخت تسک معنایی ندارد
+			if remindAt.Before(task.CreatedAt) {
+				continue
+			}
+			reminders = append(reminders, models.Reminder{
+				UserID:      userID,
+				TaskID:      task.ID,
+				LeadMinutes: lead,
+				Deadline:    *deadline,
+				RemindAt:    remindAt,
+				Status:      models.ReminderPending,
+				DedupeKey:   key,
+			})
+		}
+	}
+
+	stale := pending.Session(&gorm.Session{})
+	if len(keys) > 0 {
+		stale = stale.Where("dedupe_key NOT IN ?", keys)
+	}
+	if err := stale.Update("status", models.ReminderCancelled).Error; err != nil {
+		return err
+	}
+
+	if len(reminders) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reminders).Error
+}
+
+// SyncUserReminders - هم‌گام‌سازی یادآوری تمام تسک‌های باز کاربر، مثلا پس از تغییر قوانین یادآوری
+func SyncUserReminders(tx *gorm.DB, userID uint) error {
+	assigned := tx.Model(&models.TaskAssignment{}).Select("task_id").
+		Where("user_id = ? AND completed = ?", userID, false)
+
+	var tasks []models.Task
+	if err := tx.Where("status IN ?", OpenStatuses).
+		Where(DeadlineExpr+" > ?", time.Now()).
+		Where("(creator_id = ? AND is_group_task = ?) OR (is_group_task = ? AND id IN (?))", userID, false, true, assigned).
+		Find(&tasks).Error; err != nil {
+		return err
+	}
+	for i := range tasks {
+		if err := SyncTaskReminders(tx, &tasks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverReminder - ارسال یادآوری به صورت اعلان
+// تغییر وضعیت مشروط به pending است، بنابراین هر یادآوری فقط یک بار ارسال می‌شود
+func DeliverReminder(tx *gorm.DB, reminder *models.Reminder) error {
+	var task models.Task
+	if err := tx.First(&task, reminder.TaskID).Error; err != nil {
+		return err
+	}
+
+	// تسک تمام شده یا مهلت آن از زمان ساخت یادآوری تغییر کرده است
+	deadline := TaskDeadline(&task)
+	if !isOpen(task.Status) || deadline == nil || !deadline.Equal(reminder.Deadline) {
+		return tx.Model(&models.Reminder{}).
+			Where("id = ? AND status = ?", reminder.ID, models.ReminderPending).
+			Update("status", models.ReminderCancelled).Error
+	}
+
+	now := time.Now()
+	result := tx.Model(&models.Reminder{}).
+		Where("id = ? AND status = ? AND remind_at <= ?", reminder.ID, models.ReminderPending, now).
+		Updates(map[string]interface{}{"status": models.ReminderSent, "sent_at": now})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	return Notify(tx, []uint{reminder.UserID}, models.NotificationTaskReminder,
+		"یادآوری مهلت تسک",
+		fmt.Sprintf("مهلت تسک «%s» در %s به پایان می‌رسد", task.Title, deadline.Format("2006-01-02 15:04")),
+		task.ID)
+}
@@ -0,0 +1,335 @@
+// backend/services/reviews.go
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotAssignee         = errors.New("user is not assigned to the task")
+	ErrSubmissionPending   = errors.New("latest submission is awaiting review")
+	ErrSubmissionClosed    = errors.New("submission was already approved or rejected")
+	ErrSubmissionEmpty     = errors.New("task requires files with each submission")
+	ErrNotAwaitingReview   = errors.New("submission is not awaiting review")
+	ErrInvalidDecision     = errors.New("unknown review decision")
+	ErrGroupTaskUnfinished = errors.New("group task has assignee work that is not finished or approved")
+)
+
+// reviewOutcomes وضعیت نهایی نسخه ارسالی برای هر تصمیم بررسی‌کننده
+var reviewOutcomes = map[string]string{
+	models.ReviewApprove:        models.SubmissionApproved,
+	models.ReviewRequestChanges: models.SubmissionChangesRequested,
+	models.ReviewReject:         models.SubmissionRejected,
+}
+
+// LatestSubmission - آخرین نسخه ارسالی عضو برای تسک؛ nil اگر هنوز چیزی نفرستاده
+func LatestSubmission(tx *gorm.DB, taskID, userID uint) (*models.TaskSubmission, error) {
+	var submission models.TaskSubmission
+	err := tx.Where("task_id = ? AND user_id = ?", taskID, userID).Order("revision DESC").First(&submission).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// SubmitWork - ارسال کار عضو برای بررسی؛ پس از درخواست تغییرات نسخه جدید ساخته می‌شود
+// فایل‌هایی که عضو پس از نسخه قبلی آپلود کرده به این نسخه متصل می‌شوند و بررسی‌کنندگان مطلع می‌شوند
+func SubmitWork(tx *gorm.DB, task *models.Task, userID uint, notes string) (*models.TaskSubmission, error) {
+	// قفل اختصاص عضو تا دو ارسال همزمان یک شماره نسخه نگیرند
+	var assignment models.TaskAssignment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("task_id = ? AND user_id = ?", task.ID, userID).
+		First(&assignment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotAssignee
+		}
+		return nil, err
+	}
+
+	latest, err := LatestSubmission(tx, task.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	revision := 1
+	if latest != nil {
+		switch latest.Status {
+		case models.SubmissionSubmitted:
+			return nil, ErrSubmissionPending
+		case models.SubmissionApproved, models.SubmissionRejected:
+			return nil, ErrSubmissionClosed
+		}
+		revision = latest.Revision + 1
+	}
+
+	submission := models.TaskSubmission{
+		TaskID:   task.ID,
+		UserID:   userID,
+		Revision: revision,
+		Notes:    notes,
+		Status:   models.SubmissionSubmitted,
+	}
+	if err := tx.Create(&submission).Error; err != nil {
+		return nil, err
+	}
+	attached := tx.Model(&models.File{}).
+		Where("task_id = ? AND user_id = ? AND submission_id IS NULL", task.ID, userID).
+		Update("submission_id", submission.ID)
+	if attached.Error != nil {
+		return nil, attached.Error
+	}
+	if task.RequireFiles && attached.RowsAffected == 0 {
+		return nil, ErrSubmissionEmpty
+	}
+
+	if err := LogTaskActivity(tx, task, userID, models.ActivitySubmissionCreated, map[string]interface{}{
+		"submission_id": submission.ID,
+		"revision":      revision,
+		"files":         attached.RowsAffected,
+	}); err != nil {
+		return nil, err
+	}
+	reviewers, err := MembersWith(tx, *task.GroupID, models.PermApproveFiles)
+	if err != nil {
+		return nil, err
+	}
+	var reviewerIDs []uint
+	if err := reviewers.Where("user_id <> ?", userID).Pluck("user_id", &reviewerIDs).Error; err != nil {
+		return nil, err
+	}
+	return &submission, Notify(tx, reviewerIDs, models.NotificationSubmissionReceived,
+		"ارسال جدید برای بررسی",
+		fmt.Sprintf("نسخه %d کار یکی از اعضا برای تسک «%s» منتظر بررسی است", revision, task.Title),
+		task.ID)
+}
+
+// ReviewSubmission - تصمیم بررسی‌کننده درباره نسخه ارسالی: approve، request_changes یا reject
+// پیشرفت عضو با تصمیم همگام می‌شود، عضو مطلع می‌شود و تسک در صورت تایید همه ارسال‌های لازم تکمیل می‌شود
+func ReviewSubmission(tx *gorm.DB, task *models.Task, submission *models.TaskSubmission, decision, comment string, actorID uint) error {
+	status, ok := reviewOutcomes[decision]
+	if !ok {
+		return ErrInvalidDecision
+	}
+	if submission.Status != models.SubmissionSubmitted {
+		return ErrNotAwaitingReview
+	}
+
+	now := time.Now()
+	submission.Status = status
+	submission.ReviewedBy = &actorID
+	submission.ReviewedAt = &now
+	submission.ReviewComment = comment
+	if err := tx.Model(submission).
+		Select("status", "reviewed_by", "reviewed_at", "review_comment").
+		Updates(submission).Error; err != nil {
+		return err
+	}
+
+	progress := map[string]interface{}{"approved": false, "approved_by": nil, "approved_at": nil, "is_completed": false, "completed_at": nil}
+	if status == models.SubmissionApproved {
+		progress = map[string]interface{}{"approved": true, "approved_by": actorID, "approved_at": now, "is_completed": true, "completed_at": now, "progress": 100}
+		if err := tx.Model(&models.File{}).Where("submission_id = ?", submission.ID).
+			Updates(map[string]interface{}{"approved_by": actorID, "approved_at": now}).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Model(&models.GroupTaskProgress{}).
+		Where("task_id = ? AND user_id = ?", task.ID, submission.UserID).
+		Updates(progress).Error; err != nil {
+		return err
+	}
+
+	if err := LogTaskActivity(tx, task, actorID, models.ActivitySubmissionReviewed, map[string]interface{}{
+		"submission_id": submission.ID,
+		"user_id":       submission.UserID,
+		"revision":      submission.Revision,
+		"status":        status,
+	}); err != nil {
+		return err
+	}
+	if err := notifyReview(tx, task, submission); err != nil {
+		return err
+	}
+	return SyncGroupTaskCompletion(tx, task, actorID)
+}
+
+// notifyReview - اعلان تصمیم بررسی به عضو همراه با نظر بررسی‌کننده
+func notifyReview(tx *gorm.DB, task *models.Task, submission *models.TaskSubmission) error {
+	var title, message string
+	switch submission.Status {
+	case models.SubmissionApproved:
+		title = "ارسال شما تایید شد"
+		message = fmt.Sprintf("نسخه %d ارسالی شما برای تسک «%s» تایید شد", submission.Revision, task.Title)
+	case models.SubmissionChangesRequested:
+		title = "درخواست تغییرات"
+		message = fmt.Sprintf("برای نسخه %d ارسالی شما در تسک «%s» تغییرات درخواست شده است", submission.Revision, task.Title)
+	default:
+		title = "ارسال شما رد شد"
+		message = fmt.Sprintf("نسخه %d ارسالی شما برای تسک «%s» رد شد", submission.Revision, task.Title)
+	}
+	if submission.ReviewComment != "" {
+		message += ": " + submission.ReviewComment
+	}
+	return Notify(tx, []uint{submission.UserID}, models.NotificationSubmissionReviewed, title, message, task.ID)
+}
+
+// ApproveMemberWork - تایید کار عضو از مسیر بررسی: نسخه منتظر بررسی او تایید می‌شود
+// تایید مستقیم بدون ارسال فقط برای تسکی مجاز است که فایل الزامی ندارد و عضو هنوز چیزی نفرستاده؛
+// اگر نسخه آخر قبلا تایید شده ولی افت پیشرفت تایید عضو را باطل کرده، تایید دوباره ثبت می‌شود.
+// مانند تایید نسخه، پیشرفت عضو هم 100 و تکمیل‌شده ثبت می‌شود
+func ApproveMemberWork(tx *gorm.DB, task *models.Task, userID, actorID uint) error {
+	latest, err := LatestSubmission(tx, task.ID, userID)
+	if err != nil {
+		return err
+	}
+	if latest != nil && latest.Status != models.SubmissionApproved {
+		return ReviewSubmission(tx, task, latest, models.ReviewApprove, "", actorID)
+	}
+	if latest == nil && task.RequireFiles {
+		return ErrNotAwaitingReview
+	}
+	now := time.Now()
+	if err := tx.Model(&models.GroupTaskProgress{}).
+		Where("task_id = ? AND user_id = ? AND approved = ?", task.ID, userID, false).
+		Updates(map[string]interface{}{"approved": true, "approved_by": actorID, "approved_at": now, "is_completed": true, "completed_at": now, "progress": 100}).Error; err != nil {
+		return err
+	}
+	return SyncGroupTaskCompletion(tx, task, actorID)
+}
+
+// ApplyMemberProgress - ثبت درصد جدید روی پیشرفت عضو در تسک گروهی
+// رسیدن به 100 عضو را تکمیل‌شده می‌کند و افت به زیر 100 تکمیل و تایید قبلی او را باطل می‌کند
+func ApplyMemberProgress(progress *models.GroupTaskProgress, percent int) {
+	progress.Progress = percent
+	if percent < 100 {
+		progress.IsCompleted = false
+		progress.CompletedAt = nil
+		progress.Approved = false
+		progress.ApprovedBy = nil
+		progress.ApprovedAt = nil
+		return
+	}
+	if !progress.IsCompleted {
+		now := time.Now()
+		progress.IsCompleted = true
+		progress.CompletedAt = &now
+	}
+}
+
+// groupWorkDone - قاعده تکمیل تسک گروهی روی پیشرفت، تایید و وضعیت آخرین نسخه ارسالی هر عضو
+// هر عضو اختصاص‌یافته باید پیشرفت 100 و تایید بررسی‌کننده داشته باشد؛ رسیدن به 100 به‌تنهایی کافی نیست.
+// اگر عضو ارسالی دارد یا تسک فایل الزامی دارد، آخرین نسخه‌اش هم باید تایید شده باشد.
+// عضوی که آخرین نسخه‌اش رد شده تا وقتی از تسک برداشته نشود تکمیل تسک را نگه می‌دارد
+func groupWorkDone(assignees []uint, progress map[uint]int, approved map[uint]bool, latest map[uint]string, requireFiles bool) bool {
+	for _, userID := range assignees {
+		if progress[userID] < 100 || !approved[userID] {
+			return false
+		}
+		status, submitted := latest[userID]
+		if (submitted || requireFiles) && status != models.SubmissionApproved {
+			return false
+		}
+	}
+	return len(assignees) > 0
+}
+
+// GroupTaskReady - آیا کار اعضای اختصاص‌یافته تسک گروهی طبق groupWorkDone پذیرفته شده است
+func GroupTaskReady(tx *gorm.DB, task *models.Task) (bool, error) {
+	assignees, err := TaskAssigneeIDs(tx, task.ID)
+	if err != nil || len(assignees) == 0 {
+		return false, err
+	}
+
+	var rows []models.GroupTaskProgress
+	if err := tx.Where("task_id = ? AND user_id IN ?", task.ID, assignees).Find(&rows).Error; err != nil {
+		return false, err
+	}
+	progress := make(map[uint]int, len(rows))
+	approved := make(map[uint]bool, len(rows))
+	for _, p := range rows {
+		progress[p.UserID] = p.Progress
+		approved[p.UserID] = p.Approved
+	}
+
+	var submissions []models.TaskSubmission
+	if err := tx.Where("task_id = ? AND user_id IN ?", task.ID, assignees).
+		Order("revision DESC").
+		Find(&submissions).Error; err != nil {
+		return false, err
+	}
+	latest := make(map[uint]string, len(submissions))
+	for _, s := range submissions {
+		if _, seen := latest[s.UserID]; !seen {
+			latest[s.UserID] = s.Status
+		}
+	}
+
+	return groupWorkDone(assignees, progress, approved, latest, task.RequireFiles), nil
+}
+
+// EnsureGroupTaskReady - تکمیل مستقیم تسک گروهی (تغییر وضعیت یا انتقال به ستون Done) فقط پس از پذیرش کار اعضا
+func EnsureGroupTaskReady(tx *gorm.DB, task *models.Task) error {
+	ready, err := GroupTaskReady(tx, task)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		return ErrGroupTaskUnfinished
+	}
+	return nil
+}
+
+// CompleteGroupTaskIfDone - تکمیل تسک گروهی وقتی کار تمام اعضای اختصاص‌یافته پذیرفته شده باشد
+// تمام مسیرهای تکمیل خودکار تسک گروهی از اینجا می‌گذرند؛ با زیرتسک ناتمام و سیاست require تسک باز می‌ماند
+func CompleteGroupTaskIfDone(tx *gorm.DB, task *models.Task, actorID uint) (bool, error) {
+	if task.Status == models.StatusCompleted {
+		return false, nil
+	}
+	ready, err := GroupTaskReady(tx, task)
+	if err != nil || !ready {
+		return false, err
+	}
+	if err := PrepareCompletion(tx, task); err != nil {
+		if errors.Is(err, ErrSubtasksIncomplete) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, setGroupTaskStatus(tx, task, models.StatusCompleted, actorID)
+}
+
+// SyncGroupTaskCompletion - ارزیابی دوباره وضعیت تسک گروهی پس از تغییر پیشرفت یا بررسی ارسال
+// تسک آماده تکمیل می‌شود و تسک تکمیل‌شده‌ای که کار اعضایش دیگر پذیرفته نیست به in_progress برمی‌گردد
+func SyncGroupTaskCompletion(tx *gorm.DB, task *models.Task, actorID uint) error {
+	if task.Status != models.StatusCompleted {
+		_, err := CompleteGroupTaskIfDone(tx, task, actorID)
+		return err
+	}
+	ready, err := GroupTaskReady(tx, task)
+	if err != nil || ready {
+		return err
+	}
+	return setGroupTaskStatus(tx, task, models.StatusInProgress, actorID)
+}
+
+// setGroupTaskStatus - تغییر وضعیت تسک گروهی همراه با ثبت فعالیت و هم‌گام‌سازی یادآوری‌ها
+func setGroupTaskStatus(tx *gorm.DB, task *models.Task, status models.TaskStatus, actorID uint) error {
+	oldStatus := task.Status
+	task.Status = status
+	if err := tx.Model(task).Update("status", status).Error; err != nil {
+		return err
+	}
+	if err := LogStatusChange(tx, task, actorID, oldStatus, status); err != nil {
+		return err
+	}
+	return SyncTaskReminders(tx, task)
+}
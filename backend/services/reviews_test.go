@@ -0,0 +1,286 @@
+// backend/services/reviews_test.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestGroupWorkDone(t *testing.T) {
+	const a, b uint = 1, 2
+	both := map[uint]bool{a: true, b: true}
+	tests := []struct {
+		name         string
+		progress     map[uint]int
+		approved     map[uint]bool
+		latest       map[uint]string
+		requireFiles bool
+		want         bool
+	}{
+		{"all finished but not approved", map[uint]int{a: 100, b: 100}, nil, nil, false, false},
+		{"all finished and approved without submissions", map[uint]int{a: 100, b: 100}, both, nil, false, true},
+		{"one member unfinished", map[uint]int{a: 100, b: 90}, both, nil, false, false},
+		{"files required but nothing submitted", map[uint]int{a: 100, b: 100}, both, nil, true, false},
+		{"submission awaiting review", map[uint]int{a: 100, b: 100}, map[uint]bool{a: true}, map[uint]string{a: models.SubmissionApproved, b: models.SubmissionSubmitted}, false, false},
+		{"changes requested", map[uint]int{a: 100, b: 100}, map[uint]bool{a: true}, map[uint]string{a: models.SubmissionApproved, b: models.SubmissionChangesRequested}, true, false},
+		{"all approved", map[uint]int{a: 100, b: 100}, both, map[uint]string{a: models.SubmissionApproved, b: models.SubmissionApproved}, true, true},
+		{"approval cleared by a progress drop", map[uint]int{a: 100, b: 100}, map[uint]bool{a: true}, map[uint]string{a: models.SubmissionApproved, b: models.SubmissionApproved}, true, false},
+		{"rejected member blocks completion", map[uint]int{a: 100, b: 0}, map[uint]bool{a: true}, map[uint]string{a: models.SubmissionApproved, b: models.SubmissionRejected}, true, false},
+	}
+	for _, tt := range tests {
+		if got := groupWorkDone([]uint{a, b}, tt.progress, tt.approved, tt.latest, tt.requireFiles); got != tt.want {
+			t.Errorf("%s: groupWorkDone = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if groupWorkDone(nil, nil, nil, nil, false) {
+		t.Error("groupWorkDone without assignees = true, want false")
+	}
+}
+
+func TestApplyMemberProgress(t *testing.T) {
+	actor := uint(1)
+	var progress models.GroupTaskProgress
+
+	ApplyMemberProgress(&progress, 100)
+	if !progress.IsCompleted || progress.CompletedAt == nil {
+		t.Fatalf("progress at 100 = %+v, want completed", progress)
+	}
+	completedAt := progress.CompletedAt
+	progress.Approved, progress.ApprovedBy = true, &actor
+
+	// ثبت دوباره 100 زمان تکمیل و تایید را تغییر نمی‌دهد
+	ApplyMemberProgress(&progress, 100)
+	if progress.CompletedAt != completedAt || !progress.Approved {
+		t.Errorf("progress after repeated 100 = %+v", progress)
+	}
+
+	ApplyMemberProgress(&progress, 60)
+	if progress.Progress != 60 || progress.IsCompleted || progress.CompletedAt != nil || progress.Approved || progress.ApprovedBy != nil {
+		t.Errorf("progress after drop = %+v, want completion and approval cleared", progress)
+	}
+}
+
+// groupTaskFixture - تسک گروهی اختصاص‌یافته به اعضا همراه با ردیف پیشرفت هر عضو
+func groupTaskFixture(t *testing.T, db *gorm.DB, requireFiles bool, assignees int) (models.Task, uint, []uint) {
+	t.Helper()
+	users := createUsers(t, db, assignees+1)
+	owner := users[0].ID
+	var members []uint
+	for _, user := range users[1:] {
+		members = append(members, user.ID)
+	}
+	group := createGroup(t, db, owner, members...)
+
+	task := models.Task{
+		Title:        "report",
+		CreatorID:    owner,
+		IsGroupTask:  true,
+		GroupID:      &group.ID,
+		Status:       models.StatusInProgress,
+		RequireFiles: requireFiles,
+	}
+	mustCreate(t, db, &task)
+	for _, userID := range members {
+		mustCreate(t, db,
+			&models.TaskAssignment{TaskID: task.ID, UserID: userID},
+			&models.GroupTaskProgress{TaskID: task.ID, UserID: userID, AssignedBy: owner},
+		)
+	}
+	return task, owner, members
+}
+
+// uploadFile - فایل آپلودشده عضو که هنوز به نسخه‌ای متصل نشده
+func uploadFile(t *testing.T, db *gorm.DB, task models.Task, userID uint) models.File {
+	t.Helper()
+	file := models.File{TaskID: task.ID, UserID: userID, Filename: "work.pdf", Filepath: "uploads/work.pdf"}
+	mustCreate(t, db, &file)
+	return file
+}
+
+func taskStatus(t *testing.T, db *gorm.DB, id uint) models.TaskStatus {
+	t.Helper()
+	var task models.Task
+	if err := db.First(&task, id).Error; err != nil {
+		t.Fatalf("load task: %v", err)
+	}
+	return task.Status
+}
+
+func TestSubmissionReview(t *testing.T) {
+	db := newTestDB(t)
+	task, owner, members := groupTaskFixture(t, db, true, 2)
+	a, b := members[0], members[1]
+
+	if _, err := SubmitWork(db, &task, owner, ""); !errors.Is(err, ErrNotAssignee) {
+		t.Errorf("submit by non-assignee = %v, want ErrNotAssignee", err)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		_, err := SubmitWork(tx, &task, a, "")
+		return err
+	})
+	if !errors.Is(err, ErrSubmissionEmpty) {
+		t.Fatalf("submit without files = %v, want ErrSubmissionEmpty", err)
+	}
+
+	file := uploadFile(t, db, task, a)
+	first, err := SubmitWork(db, &task, a, "draft")
+	if err != nil {
+		t.Fatalf("SubmitWork: %v", err)
+	}
+	if first.Revision != 1 || first.Status != models.SubmissionSubmitted {
+		t.Errorf("first submission = %+v", first)
+	}
+	db.First(&file, file.ID)
+	if file.SubmissionID == nil || *file.SubmissionID != first.ID {
+		t.Errorf("uploaded file not attached to submission %d", first.ID)
+	}
+	if _, err := SubmitWork(db, &task, a, ""); !errors.Is(err, ErrSubmissionPending) {
+		t.Errorf("submit while awaiting review = %v, want ErrSubmissionPending", err)
+	}
+
+	if err := ReviewSubmission(db, &task, first, "maybe", "", owner); !errors.Is(err, ErrInvalidDecision) {
+		t.Errorf("unknown decision = %v, want ErrInvalidDecision", err)
+	}
+	if err := ReviewSubmission(db, &task, first, models.ReviewRequestChanges, "add totals", owner); err != nil {
+		t.Fatalf("request changes: %v", err)
+	}
+	if first.Status != models.SubmissionChangesRequested || first.ReviewedBy == nil {
+		t.Errorf("submission after request changes = %+v", first)
+	}
+	if err := ReviewSubmission(db, &task, first, models.ReviewApprove, "", owner); !errors.Is(err, ErrNotAwaitingReview) {
+		t.Errorf("review twice = %v, want ErrNotAwaitingReview", err)
+	}
+
+	uploadFile(t, db, task, a)
+	second, err := SubmitWork(db, &task, a, "fixed")
+	if err != nil || second.Revision != 2 {
+		t.Fatalf("resubmit = %+v, %v; want revision 2", second, err)
+	}
+	if err := ReviewSubmission(db, &task, second, models.ReviewApprove, "", owner); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	var progress models.GroupTaskProgress
+	db.Where("task_id = ? AND user_id = ?", task.ID, a).First(&progress)
+	if progress.Progress != 100 || !progress.IsCompleted || !progress.Approved {
+		t.Errorf("approved member progress = %+v", progress)
+	}
+	if status := taskStatus(t, db, task.ID); status == models.StatusCompleted {
+		t.Fatal("task completed while another assignee has not submitted")
+	}
+	if _, err := SubmitWork(db, &task, a, ""); !errors.Is(err, ErrSubmissionClosed) {
+		t.Errorf("submit after approval = %v, want ErrSubmissionClosed", err)
+	}
+
+	// رد کار عضو دوم پایانی است و تا برداشتن او از تسک، تکمیل را نگه می‌دارد
+	uploadFile(t, db, task, b)
+	rejected, err := SubmitWork(db, &task, b, "")
+	if err != nil {
+		t.Fatalf("SubmitWork(b): %v", err)
+	}
+	if err := ReviewSubmission(db, &task, rejected, models.ReviewReject, "off topic", owner); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if status := taskStatus(t, db, task.ID); status == models.StatusCompleted {
+		t.Fatal("task completed while an assignee's work is rejected")
+	}
+	if _, err := SubmitWork(db, &task, b, ""); !errors.Is(err, ErrSubmissionClosed) {
+		t.Errorf("submit after rejection = %v, want ErrSubmissionClosed", err)
+	}
+
+	if _, err := RemoveTaskAssignees(db, &task, []uint{b}, owner); err != nil {
+		t.Fatalf("RemoveTaskAssignees: %v", err)
+	}
+	if status := taskStatus(t, db, task.ID); status != models.StatusCompleted {
+		t.Errorf("task status after removing the rejected assignee = %s, want completed", status)
+	}
+}
+
+// setMemberProgress - ثبت پیشرفت عضو از مسیر گزارش خود او و ارزیابی دوباره تسک
+func setMemberProgress(t *testing.T, db *gorm.DB, task *models.Task, userID uint, percent int) {
+	t.Helper()
+	var progress models.GroupTaskProgress
+	db.Where("task_id = ? AND user_id = ?", task.ID, userID).First(&progress)
+	ApplyMemberProgress(&progress, percent)
+	if err := db.Save(&progress).Error; err != nil {
+		t.Fatalf("save progress: %v", err)
+	}
+	if err := SyncGroupTaskCompletion(db, task, userID); err != nil {
+		t.Fatalf("SyncGroupTaskCompletion: %v", err)
+	}
+}
+
+func TestGroupTaskNeedsApprovalAt100(t *testing.T) {
+	db := newTestDB(t)
+	task, owner, members := groupTaskFixture(t, db, false, 1)
+	member := members[0]
+
+	// گزارش 100 توسط خود عضو بدون تایید تسک را تکمیل نمی‌کند
+	setMemberProgress(t, db, &task, member, 100)
+	if status := taskStatus(t, db, task.ID); status == models.StatusCompleted {
+		t.Fatal("task completed at 100 without review")
+	}
+	if err := EnsureGroupTaskReady(db, &task); !errors.Is(err, ErrGroupTaskUnfinished) {
+		t.Errorf("EnsureGroupTaskReady without approval = %v, want ErrGroupTaskUnfinished", err)
+	}
+
+	// تایید مستقیم بدون ارسال برای تسکی که فایل الزامی ندارد
+	if err := ApproveMemberWork(db, &task, member, owner); err != nil {
+		t.Fatalf("ApproveMemberWork: %v", err)
+	}
+	if status := taskStatus(t, db, task.ID); status != models.StatusCompleted {
+		t.Errorf("task status after approval = %s, want completed", status)
+	}
+}
+
+func TestGroupTaskReopensWhenProgressDrops(t *testing.T) {
+	db := newTestDB(t)
+	task, owner, members := groupTaskFixture(t, db, false, 1)
+	member := members[0]
+
+	submission, err := SubmitWork(db, &task, member, "done")
+	if err != nil {
+		t.Fatalf("SubmitWork: %v", err)
+	}
+	if err := ReviewSubmission(db, &task, submission, models.ReviewApprove, "", owner); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if status := taskStatus(t, db, task.ID); status != models.StatusCompleted {
+		t.Fatalf("task status after approval = %s, want completed", status)
+	}
+
+	setMemberProgress(t, db, &task, member, 40)
+	if status := taskStatus(t, db, task.ID); status != models.StatusInProgress {
+		t.Errorf("task status after progress drop = %s, want in_progress", status)
+	}
+
+	// بازگشت به 100 تایید قبلی را برنمی‌گرداند
+	setMemberProgress(t, db, &task, member, 100)
+	if status := taskStatus(t, db, task.ID); status == models.StatusCompleted {
+		t.Fatal("task completed again without re-approval")
+	}
+
+	if err := ApproveMemberWork(db, &task, member, owner); err != nil {
+		t.Fatalf("re-approve: %v", err)
+	}
+	var progress models.GroupTaskProgress
+	db.Where("task_id = ? AND user_id = ?", task.ID, member).First(&progress)
+	if !progress.Approved || progress.ApprovedBy == nil || *progress.ApprovedBy != owner {
+		t.Errorf("progress after re-approval = %+v", progress)
+	}
+	if status := taskStatus(t, db, task.ID); status != models.StatusCompleted {
+		t.Errorf("task status after re-approval = %s, want completed", status)
+	}
+}
+
+func TestApproveMemberWorkRequiresSubmission(t *testing.T) {
+	db := newTestDB(t)
+	task, owner, members := groupTaskFixture(t, db, true, 1)
+
+	if err := ApproveMemberWork(db, &task, members[0], owner); !errors.Is(err, ErrNotAwaitingReview) {
+		t.Errorf("direct approval with required files = %v, want ErrNotAwaitingReview", err)
+	}
+}
@@ -0,0 +1,177 @@
+// backend/services/subtasks.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSubtasksIncomplete = errors.New("task has unfinished subtasks")
+	ErrTaskCycle          = errors.New("task cannot become a subtask of its own subtask")
+)
+
+// Subtask deletion modes
+const (
+	SubtasksMove   = "move"   // children move up to the deleted task's parent
+	SubtasksDelete = "delete" // children are deleted with their parent
+)
+
+// CountSubtasks - تعداد زیرتسک‌های مستقیم و تعداد تکمیل‌شده‌ها
+func CountSubtasks(tx *gorm.DB, taskID uint) (total, completed int64, err error) {
+	if err = tx.Model(&models.Task{}).Where("parent_id = ?", taskID).Count(&total).Error; err != nil {
+		return
+	}
+	err = tx.Model(&models.Task{}).Where("parent_id = ? AND status = ?", taskID, models.StatusCompleted).Count(&completed).Error
+	return
+}
+
+// PrepareCompletion - اعمال سیاست زیرتسک‌ها پیش از تکمیل task
+func PrepareCompletion(tx *gorm.DB, task *models.Task) error {
+	total, completed, err := CountSubtasks(tx, task.ID)
+	if err != nil || total == completed {
+		return err
+	}
+	if task.SubtaskPolicy != models.SubtaskPolicyAutoComplete {
+		return ErrSubtasksIncomplete
+	}
+	return completeDescendants(tx, task.ID)
+}
+
+func completeDescendants(tx *gorm.DB, taskID uint) error {
+	var children []models.Task
+	if err := tx.Where("parent_id = ?", taskID).Find(&children).Error; err != nil {
+		return err
+	}
+	for i := range children {
+		child := &children[i]
+		if err := completeDescendants(tx, child.ID); err != nil {
+			return err
+		}
+		if child.Status == models.StatusCompleted {
+			continue
+		}
+		child.Status = models.StatusCompleted
+		if err := tx.Save(child).Error; err != nil {
+			return err
+		}
+		if err := SetTaskProgress(tx, child.ID, child.CreatorID, 100); err != nil {
+			return err
+		}
+		if _, err := GenerateNextOccurrence(tx, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetTaskProgress - ایجاد یا بروزرسانی رکورد پیشرفت شخصی
+func SetTaskProgress(tx *gorm.DB, taskID, userID uint, percent int) error {
+	var progress models.TaskProgress
+	err := tx.Where("task_id = ? AND user_id = ?", taskID, userID).First(&progress).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	progress.TaskID = taskID
+	progress.UserID = userID
+	progress.Progress = percent
+	if percent == 100 && !progress.IsCompleted {
+		now := time.Now()
+		progress.IsCompleted = true
+		progress.CompletedAt = &now
+	} else if percent < 100 {
+		progress.IsCompleted = false
+		progress.CompletedAt = nil
+	}
+	return tx.Save(&progress).Error
+}
+
+// SyncParentProgress - محاسبه مجدد پیشرفت والدها از روی تکمیل زیرتسک‌ها
+// از parentID شروع کرده و تا ریشه درخت بالا می‌رود
+func SyncParentProgress(tx *gorm.DB, parentID *uint) error {
+	for parentID != nil {
+		var parent models.Task
+		if err := tx.First(&parent, *parentID).Error; err != nil {
+			return err
+		}
+
+		total, completed, err := CountSubtasks(tx, parent.ID)
+		if err != nil {
+			return err
+		}
+		// بدون زیرتسک، پیشرفت دستی والد دست نمی‌خورد
+		if total == 0 {
+			parentID = parent.ParentID
+			continue
+		}
+		percent := int(completed * 100 / total)
+		if err := SetTaskProgress(tx, parent.ID, parent.CreatorID, percent); err != nil {
+			return err
+		}
+
+		wasCompleted := parent.Status == models.StatusCompleted
+		switch {
+		case parent.IsGroupTask:
+			// وضعیت تسک گروهی از روی کار پذیرفته‌شده اعضا تعیین می‌شود، نه زیرتسک‌ها
+		case percent == 100:
+			parent.Status = models.StatusCompleted
+		case percent > 0 || wasCompleted:
+			parent.Status = models.StatusInProgress
+		}
+		if err := tx.Save(&parent).Error; err != nil {
+			return err
+		}
+		if !wasCompleted && parent.Status == models.StatusCompleted {
+			if _, err := GenerateNextOccurrence(tx, &parent); err != nil {
+				return err
+			}
+		}
+
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// IsAncestor - آیا ancestorID در مسیر taskID تا ریشه قرار دارد (شامل خود taskID)
+func IsAncestor(tx *gorm.DB, ancestorID, taskID uint) (bool, error) {
+	current := &taskID
+	for current != nil {
+		if *current == ancestorID {
+			return true, nil
+		}
+		var task models.Task
+		if err := tx.Select("id", "parent_id").First(&task, *current).Error; err != nil {
+			return false, err
+		}
+		current = task.ParentID
+	}
+	return false, nil
+}
+
+// DetachSubtasks - آماده‌سازی زیرتسک‌ها پیش از حذف task
+// در حالت move فرزندان به والد task منتقل می‌شوند و در حالت delete همه نوادگان با زمان حذف deletedAt
+// به سطل زباله می‌روند تا همراه task بازگردانده شوند
+func DetachSubtasks(tx *gorm.DB, task *models.Task, mode string, deletedAt time.Time) error {
+	if mode != SubtasksDelete {
+		return tx.Model(&models.Task{}).Where("parent_id = ?", task.ID).Update("parent_id", task.ParentID).Error
+	}
+
+	var children []models.Task
+	if err := tx.Where("parent_id = ?", task.ID).Find(&children).Error; err != nil {
+		return err
+	}
+	for i := range children {
+		if err := DetachSubtasks(tx, &children[i], SubtasksDelete, deletedAt); err != nil {
+			return err
+		}
+		if err := trashTaskRow(tx, children[i].ID, deletedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+// backend/services/teams.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTeamNameTaken     = errors.New("team name already in use")
+	ErrAlreadyTeamMember = errors.New("user is already a member of the team")
+	ErrNotTeamMember     = errors.New("user is not a member of the team")
+)
+
+// EnsureTeamNameFree - نام تیم در هر گروه یکتاست
+func EnsureTeamNameFree(tx *gorm.DB, team *models.GroupTeam) error {
+	var count int64
+	if err := tx.Model(&models.GroupTeam{}).
+		Where("group_id = ? AND name = ? AND id <> ?", team.GroupID, team.Name, team.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrTeamNameTaken
+	}
+	return nil
+}
+
+// TeamContributorIDs - اعضای تیم‌ها که نقششان در گروه اجازه انجام تسک دارد
+func TeamContributorIDs(tx *gorm.DB, groupID uint, teamIDs []uint) ([]uint, error) {
+	if len(teamIDs) == 0 {
+		return nil, nil
+	}
+	contributors, err := MembersWith(tx, groupID, models.PermContribute)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	err = tx.Model(&models.GroupTeamMember{}).Distinct().
+		Where("team_id IN ? AND user_id IN (?)", teamIDs, contributors).
+		Order("user_id ASC").
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// AssignTaskUsers - اختصاص تسک گروهی به کاربران همراه با رکورد پیشرفت هر کدام؛ شناسه کاربرانی که تازه اختصاص یافته‌اند برمی‌گردد
+// پیشرفت بایگانی‌شده از اختصاص قبلی کاربر بازگردانده می‌شود تا کار انجام‌شده از دست نرود
+// viaTeam یعنی اختصاص فقط از طریق تیم است؛ اختصاص مستقیم یک اختصاص تیمی موجود را مستقیم می‌کند
+func AssignTaskUsers(tx *gorm.DB, task *models.Task, userIDs []uint, actorID uint, viaTeam bool) ([]uint, error) {
+	var added []uint
+	for _, userID := range userIDs {
+		var assignment models.TaskAssignment
+		err := tx.Where("task_id = ? AND user_id = ?", task.ID, userID).First(&assignment).Error
+		if err == nil {
+			if assignment.ViaTeam && !viaTeam {
+				if err := tx.Model(&assignment).Update("via_team", false).Error; err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		if err := tx.Create(&models.TaskAssignment{TaskID: task.ID, UserID: userID, ViaTeam: viaTeam}).Error; err != nil {
+			return nil, err
+		}
+		var progress models.GroupTaskProgress
+		err = tx.Unscoped().Where("task_id = ? AND user_id = ?", task.ID, userID).Order("id DESC").First(&progress).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(&models.GroupTaskProgress{TaskID: task.ID, UserID: userID, AssignedBy: actorID}).Error; err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		case progress.DeletedAt.Valid:
+			if err := tx.Unscoped().Model(&progress).Updates(map[string]interface{}{"deleted_at": nil, "assigned_by": actorID}).Error; err != nil {
+				return nil, err
+			}
+		}
+		added = append(added, userID)
+	}
+	return added, nil
+}
+
+// UnassignTaskUsers - برداشتن اختصاص تسک از کاربران؛ رکورد پیشرفت حذف نرم (بایگانی) می‌شود و با اختصاص دوباره برمی‌گردد
+// یادآوری‌های در انتظار آن‌ها برای این تسک لغو می‌شوند
+func UnassignTaskUsers(tx *gorm.DB, task *models.Task, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("task_id = ? AND user_id IN ?", task.ID, userIDs).Delete(&models.TaskAssignment{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Reminder{}).
+		Where("task_id = ? AND user_id IN ? AND status = ?", task.ID, userIDs, models.ReminderPending).
+		Update("status", models.ReminderCancelled).Error; err != nil {
+		return err
+	}
+	return tx.Where("task_id = ? AND user_id IN ?", task.ID, userIDs).Delete(&models.GroupTaskProgress{}).Error
+}
+
+// taskTeamIDs - زیرکوئری شناسه تیم‌هایی که تسک به آن‌ها اختصاص دارد
+func taskTeamIDs(tx *gorm.DB, taskID uint) *gorm.DB {
+	return tx.Table("task_teams").Select("team_id").Where("task_id = ?", taskID)
+}
+
+// releaseTeamAssignments - اختصاص‌هایی که فقط از طریق تیم بوده‌اند و دیگر هیچ تیمی از تسک پوشش‌شان نمی‌دهد برداشته می‌شوند
+// اختصاص مستقیم و کاری که عضو تکمیل کرده دست‌نخورده می‌ماند
+func releaseTeamAssignments(tx *gorm.DB, task *models.Task, userIDs []uint) ([]uint, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	covered := tx.Model(&models.GroupTeamMember{}).Select("user_id").Where("team_id IN (?)", taskTeamIDs(tx, task.ID))
+	completed := tx.Model(&models.GroupTaskProgress{}).Select("user_id").Where("task_id = ? AND is_completed = ?", task.ID, true)
+
+	var released []uint
+	if err := tx.Model(&models.TaskAssignment{}).
+		Where("task_id = ? AND via_team = ? AND user_id IN ?", task.ID, true, userIDs).
+		Where("user_id NOT IN (?) AND user_id NOT IN (?)", covered, completed).
+		Pluck("user_id", &released).Error; err != nil {
+		return nil, err
+	}
+	return released, UnassignTaskUsers(tx, task, released)
+}
+
+// teamOpenTasks - تسک‌های تکمیل‌نشده‌ای که به تیم اختصاص دارند؛ تغییر اعضای تیم فقط روی این تسک‌ها اثر دارد
+func teamOpenTasks(tx *gorm.DB, teamID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := tx.Where("id IN (?) AND status <> ?",
+		tx.Table("task_teams").Select("task_id").Where("team_id = ?", teamID), models.StatusCompleted).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// AssignTaskTeams - اختصاص تسک گروهی به تیم‌ها؛ اعضای فعلی تیم‌ها به تسک اختصاص داده می‌شوند
+func AssignTaskTeams(tx *gorm.DB, task *models.Task, teams []models.GroupTeam, actorID uint) ([]uint, error) {
+	if len(teams) == 0 {
+		return nil, nil
+	}
+	if err := tx.Model(task).Association("Teams").Append(&teams); err != nil {
+		return nil, err
+	}
+	teamIDs := make([]uint, len(teams))
+	for i, team := range teams {
+		teamIDs[i] = team.ID
+	}
+	userIDs, err := TeamContributorIDs(tx, *task.GroupID, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := AssignTaskUsers(tx, task, userIDs, actorID, true)
+	if err != nil {
+		return nil, err
+	}
+	return assigned, LogTaskActivity(tx, task, actorID, models.ActivityTaskTeamsChanged, map[string]interface{}{
+		"added":    teamIDs,
+		"assigned": assigned,
+	})
+}
+
+// UnassignTaskTeam - برداشتن تیم از تسک گروهی؛ اعضایی که فقط از طریق این تیم اختصاص یافته بودند کنار می‌روند
+func UnassignTaskTeam(tx *gorm.DB, task *models.Task, team *models.GroupTeam, actorID uint) ([]uint, error) {
+	if err := tx.Model(task).Association("Teams").Delete(team); err != nil {
+		return nil, err
+	}
+	var memberIDs []uint
+	if err := tx.Model(&models.GroupTeamMember{}).Where("team_id = ?", team.ID).Pluck("user_id", &memberIDs).Error; err != nil {
+		return nil, err
+	}
+	released, err := releaseTeamAssignments(tx, task, memberIDs)
+	if err != nil {
+		return nil, err
+	}
+	return released, LogTaskActivity(tx, task, actorID, models.ActivityTaskTeamsChanged, map[string]interface{}{
+		"removed":    []uint{team.ID},
+		"unassigned": released,
+	})
+}
+
+// AddTeamMember - افزودن عضو پذیرفته‌شده گروه به تیم؛ تسک‌های باز تیم به او اختصاص داده می‌شوند
+func AddTeamMember(tx *gorm.DB, team *models.GroupTeam, userID, actorID uint) (*models.GroupTeamMember, error) {
+	var member models.GroupMember
+	if err := tx.Where("group_id = ? AND user_id = ? AND accepted = ?", team.GroupID, userID, true).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, err
+	}
+	var count int64
+	if err := tx.Model(&models.GroupTeamMember{}).Where("team_id = ? AND user_id = ?", team.ID, userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrAlreadyTeamMember
+	}
+
+	teamMember := models.GroupTeamMember{TeamID: team.ID, GroupID: team.GroupID, UserID: userID, AddedBy: actorID}
+	if err := tx.Create(&teamMember).Error; err != nil {
+		return nil, err
+	}
+
+	// فقط نقش‌هایی که اجازه انجام تسک دارند تسک‌های تیم را می‌گیرند
+	contributors, err := MembersWith(tx, team.GroupID, models.PermContribute)
+	if err != nil {
+		return nil, err
+	}
+	if err := contributors.Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	var taskIDs []uint
+	if count > 0 {
+		tasks, err := teamOpenTasks(tx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tasks {
+			assigned, err := AssignTaskUsers(tx, &tasks[i], []uint{userID}, actorID, true)
+			if err != nil {
+				return nil, err
+			}
+			if len(assigned) > 0 {
+				taskIDs = append(taskIDs, tasks[i].ID)
+			}
+		}
+	}
+
+	return &teamMember, LogGroupActivity(tx, team.GroupID, actorID, models.ActivityTeamMemberAdded, map[string]interface{}{
+		"team_id": team.ID,
+		"user_id": userID,
+		"tasks":   taskIDs,
+	})
+}
+
+// RemoveTeamMember - حذف عضو از تیم؛ اختصاص‌هایی که فقط از طریق این تیم داشت از تسک‌های باز تیم برداشته می‌شوند
+func RemoveTeamMember(tx *gorm.DB, team *models.GroupTeam, userID, actorID uint) error {
+	result := tx.Where("team_id = ? AND user_id = ?", team.ID, userID).Delete(&models.GroupTeamMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotTeamMember
+	}
+	taskIDs, err := releaseTeamTasks(tx, team.ID, userID)
+	if err != nil {
+		return err
+	}
+	return LogGroupActivity(tx, team.GroupID, actorID, models.ActivityTeamMemberRemoved, map[string]interface{}{
+		"team_id": team.ID,
+		"user_id": userID,
+		"tasks":   taskIDs,
+	})
+}
+
+// releaseTeamTasks - آزاد کردن اختصاص‌های تیمی کاربر روی تسک‌های باز تیم؛ شناسه تسک‌هایی که از او برداشته شدند برمی‌گردد
+func releaseTeamTasks(tx *gorm.DB, teamID, userID uint) ([]uint, error) {
+	tasks, err := teamOpenTasks(tx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	var taskIDs []uint
+	for i := range tasks {
+		released, err := releaseTeamAssignments(tx, &tasks[i], []uint{userID})
+		if err != nil {
+			return nil, err
+		}
+		if len(released) > 0 {
+			taskIDs = append(taskIDs, tasks[i].ID)
+		}
+	}
+	return taskIDs, nil
+}
+
+// LeaveGroupTeams - خروج کاربر از تمام تیم‌های گروه هنگام خروج یا حذف از گروه
+func LeaveGroupTeams(tx *gorm.DB, groupID, userID uint) error {
+	var teamIDs []uint
+	if err := tx.Model(&models.GroupTeamMember{}).Where("group_id = ? AND user_id = ?", groupID, userID).Pluck("team_id", &teamIDs).Error; err != nil {
+		return err
+	}
+	if len(teamIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&models.GroupTeamMember{}).Error; err != nil {
+		return err
+	}
+	for _, teamID := range teamIDs {
+		if _, err := releaseTeamTasks(tx, teamID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTeam - حذف تیم؛ تیم از تسک‌ها برداشته می‌شود و اختصاص‌هایی که فقط از طریق آن بودند از تسک‌های باز کنار می‌روند
+func DeleteTeam(tx *gorm.DB, team *models.GroupTeam, actorID uint) error {
+	tasks, err := teamOpenTasks(tx, team.ID)
+	if err != nil {
+		return err
+	}
+	var memberIDs []uint
+	if err := tx.Model(&models.GroupTeamMember{}).Where("team_id = ?", team.ID).Pluck("user_id", &memberIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM task_teams WHERE team_id = ?", team.ID).Error; err != nil {
+		return err
+	}
+	for i := range tasks {
+		if _, err := releaseTeamAssignments(tx, &tasks[i], memberIDs); err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("team_id = ?", team.ID).Delete(&models.GroupTeamMember{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Delete(team).Error; err != nil {
+		return err
+	}
+	return LogGroupActivity(tx, team.GroupID, actorID, models.ActivityTeamDeleted, map[string]interface{}{"name": team.Name})
+}
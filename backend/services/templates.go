@@ -0,0 +1,205 @@
+// backend/services/templates.go
+
+package services
+
+import (
+	"errors"
+	"strings"
+	"task-manager/models"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoAssignees تسک گروهی بدون هیچ عضو پذیرفته‌شده‌ای برای اختصاص
+var ErrNoAssignees = errors.New("no accepted members to assign")
+
+// TemplateInstance مشخصات یک تسک ساخته‌شده از الگو
+// DueDate در صورت خالی بودن از DueIn الگو محاسبه می‌شود و Vars جای‌نگهدارهای دلخواه را مقدار می‌دهد
+type TemplateInstance struct {
+	DueDate   *time.Time
+	StartTime *time.Time
+	EndTime   *time.Time
+	UserIDs   []uint // فقط تسک گروهی؛ خالی برای تمام اعضا
+	Vars      map[string]string
+}
+
+// TemplateTarget محل ساخت تسک‌ها؛ GroupID خالی یعنی تسک شخصی کاربر
+// PerAssignee برای هر عضو یک تسک جدا می‌سازد تا {{assignee}} نام همان عضو باشد
+type TemplateTarget struct {
+	GroupID     *uint
+	PerAssignee bool
+}
+
+// InstantiateTemplate - ساخت یک یا چند تسک از الگو در تراکنش فراخواننده
+func InstantiateTemplate(tx *gorm.DB, tpl *models.TaskTemplate, target TemplateTarget, instances []TemplateInstance, actorID uint) ([]models.Task, error) {
+	var dueIn time.Duration
+	if tpl.DueIn != "" {
+		d, err := utils.ParseFriendlyDuration(tpl.DueIn)
+		if err != nil {
+			return nil, err
+		}
+		dueIn = d
+	}
+
+	var actor models.User
+	if err := tx.Select("id", "username").First(&actor, actorID).Error; err != nil {
+		return nil, err
+	}
+	var group models.Group
+	if target.GroupID != nil {
+		if err := tx.First(&group, *target.GroupID).Error; err != nil {
+			return nil, err
+		}
+		if group.ArchivedAt != nil {
+			return nil, ErrGroupArchived
+		}
+	}
+
+	now := time.Now()
+	var tasks []models.Task
+	for _, instance := range instances {
+		dueDate := instance.DueDate
+		if dueDate == nil && tpl.DueIn != "" {
+			due := now.Add(dueIn)
+			dueDate = &due
+		}
+		anchor := now
+		if dueDate != nil {
+			anchor = *dueDate
+		}
+		vars := utils.DateVars(anchor)
+		vars["group"] = group.Name
+		vars["assignee"] = actor.Username
+		for name, value := range instance.Vars {
+			vars[strings.ToLower(name)] = value
+		}
+
+		if target.GroupID == nil {
+			task, err := createFromTemplate(tx, tpl, nil, nil, instance, dueDate, vars, actorID)
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, *task)
+			continue
+		}
+
+		members, err := templateAssignees(tx, *target.GroupID, instance.UserIDs)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) == 0 {
+			return nil, ErrNoAssignees
+		}
+		if !target.PerAssignee {
+			names := make([]string, len(members))
+			for i, member := range members {
+				names[i] = member.Username
+			}
+			vars["assignee"] = strings.Join(names, ", ")
+			task, err := createFromTemplate(tx, tpl, target.GroupID, members, instance, dueDate, vars, actorID)
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, *task)
+			continue
+		}
+		for _, member := range members {
+			vars["assignee"] = member.Username
+			task, err := createFromTemplate(tx, tpl, target.GroupID, []models.User{member}, instance, dueDate, vars, actorID)
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, *task)
+		}
+	}
+	return tasks, nil
+}
+
+// templateAssignees - اعضای پذیرفته‌شده گروه که نقششان اجازه انجام تسک دارد، محدود به userIDs در صورت وجود
+func templateAssignees(tx *gorm.DB, groupID uint, userIDs []uint) ([]models.User, error) {
+	members, err := MembersWith(tx, groupID, models.PermContribute)
+	if err != nil {
+		return nil, err
+	}
+	if len(userIDs) > 0 {
+		members = members.Where("user_id IN ?", userIDs)
+	}
+	var users []models.User
+	err = tx.Select("id", "username").Where("id IN (?)", members).Order("id ASC").Find(&users).Error
+	return users, err
+}
+
+func createFromTemplate(tx *gorm.DB, tpl *models.TaskTemplate, groupID *uint, assignees []models.User, instance TemplateInstance, dueDate *time.Time, vars map[string]string, actorID uint) (*models.Task, error) {
+	task := models.Task{
+		Title:       utils.RenderPlaceholders(tpl.Title, vars),
+		Description: utils.RenderPlaceholders(tpl.Description, vars),
+		Priority:    tpl.Priority,
+		DueDate:     dueDate,
+		CreatorID:   actorID,
+		Status:      models.StatusPending,
+
+		EstimateMinutes:       tpl.EstimateMinutes,
+		ProgressFromChecklist: tpl.ProgressFromChecklist,
+		SubtaskPolicy:         models.SubtaskPolicyRequire,
+	}
+	if task.Priority == "" {
+		task.Priority = "medium"
+	}
+	if groupID != nil {
+		task.IsGroupTask = true
+		task.GroupID = groupID
+		task.StartTime = instance.StartTime
+		task.EndTime = instance.EndTime
+		task.RequireFiles = tpl.RequireFiles
+		task.MaxFiles = tpl.MaxFiles
+		task.AllowTypes = tpl.AllowTypes
+		if task.MaxFiles == 0 {
+			task.MaxFiles = 5
+		}
+	}
+	if err := tx.Create(&task).Error; err != nil {
+		return nil, err
+	}
+
+	owners := []uint{actorID}
+	if groupID != nil {
+		owners = owners[:0]
+		for _, user := range assignees {
+			if err := tx.Create(&models.TaskAssignment{TaskID: task.ID, UserID: user.ID}).Error; err != nil {
+				return nil, err
+			}
+			if err := tx.Create(&models.GroupTaskProgress{TaskID: task.ID, UserID: user.ID, AssignedBy: actorID}).Error; err != nil {
+				return nil, err
+			}
+			owners = append(owners, user.ID)
+		}
+	}
+
+	for _, ownerID := range owners {
+		for position, item := range tpl.Items {
+			if err := tx.Create(&models.ChecklistItem{
+				TaskID:    task.ID,
+				UserID:    ownerID,
+				Title:     utils.RenderPlaceholders(item.Title, vars),
+				Position:  position,
+				CreatedBy: actorID,
+			}).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	details := map[string]interface{}{
+		"title":       task.Title,
+		"template_id": tpl.ID,
+	}
+	if groupID != nil {
+		details["assignees"] = owners
+	}
+	if err := LogTaskActivity(tx, &task, actorID, models.ActivityTaskCreated, details); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
@@ -0,0 +1,68 @@
+// backend/services/testdb_test.go
+
+package services
+
+import (
+	"task-manager/config"
+	"task-manager/models"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB - پایگاه داده sqlite در حافظه با تمام جداول برنامه
+// یک اتصال تا همه کوئری‌ها همان پایگاه داده حافظه را ببینند
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(config.Models()...); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+	return db
+}
+
+// mustCreate - ذخیره رکوردهای آزمایشی
+func mustCreate(t *testing.T, db *gorm.DB, values ...interface{}) {
+	t.Helper()
+	for _, value := range values {
+		if err := db.Create(value).Error; err != nil {
+			t.Fatalf("create %T: %v", value, err)
+		}
+	}
+}
+
+// createUsers - ساخت n کاربر با نام‌های یکتا
+func createUsers(t *testing.T, db *gorm.DB, n int) []models.User {
+	t.Helper()
+	users := make([]models.User, n)
+	for i := range users {
+		users[i].Username = "user" + string(rune('a'+i))
+		users[i].Email = users[i].Username + "@example.com"
+		mustCreate(t, db, &users[i])
+	}
+	return users
+}
+
+// createGroup - گروهی با مالک owner و اعضای پذیرفته‌شده members با نقش member
+func createGroup(t *testing.T, db *gorm.DB, owner uint, members ...uint) models.Group {
+	t.Helper()
+	group := models.Group{Name: "group", CreatedBy: owner}
+	mustCreate(t, db, &group)
+	mustCreate(t, db, &models.GroupMember{GroupID: group.ID, UserID: owner, Role: models.RoleOwner, Accepted: true})
+	for _, userID := range members {
+		mustCreate(t, db, &models.GroupMember{GroupID: group.ID, UserID: userID, Role: models.RoleMember, Accepted: true})
+	}
+	return group
+}
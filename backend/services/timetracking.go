@@ -0,0 +1,88 @@
+// backend/services/timetracking.go
+
+package services
+
+import (
+	"errors"
+	"task-manager/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTimerRunning  = errors.New("another timer is already running")
+	ErrNoActiveTimer = errors.New("no timer is running")
+)
+
+// TrackedSecondsExpr - مدت هر رکورد زمان در SQL؛ تایمر در حال اجرا تا همین لحظه حساب می‌شود
+// TIMESTAMPDIFF فقط در MySQL وجود دارد؛ sqlite (پایگاه داده تست‌ها) فاصله را با strftime حساب می‌کند
+func TrackedSecondsExpr(db *gorm.DB) string {
+	elapsed := "TIMESTAMPDIFF(SECOND, time_entries.started_at, NOW())"
+	if db.Dialector.Name() == "sqlite" {
+		elapsed = "(CAST(strftime('%s', 'now') AS INTEGER) - CAST(strftime('%s', time_entries.started_at) AS INTEGER))"
+	}
+	return "CASE WHEN time_entries.ended_at IS NULL THEN " + elapsed + " ELSE time_entries.seconds END"
+}
+
+// ActiveTimer - تایمر در حال اجرای کاربر
+func ActiveTimer(tx *gorm.DB, userID uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	if err := tx.Where("active_user_id = ?", userID).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoActiveTimer
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// StartTimer - شروع تایمر؛ اگر تایمر دیگری فعال باشد ErrTimerRunning برمی‌گردد
+func StartTimer(tx *gorm.DB, userID, taskID uint, note string) (*models.TimeEntry, error) {
+	if _, err := ActiveTimer(tx, userID); err == nil {
+		return nil, ErrTimerRunning
+	} else if !errors.Is(err, ErrNoActiveTimer) {
+		return nil, err
+	}
+
+	entry := models.TimeEntry{
+		TaskID:       taskID,
+		UserID:       userID,
+		StartedAt:    time.Now(),
+		Note:         note,
+		ActiveUserID: &userID,
+	}
+	// ایندکس یکتای active_user_id شروع هم‌زمان دو تایمر را رد می‌کند
+	if err := tx.Create(&entry).Error; err != nil {
+		if _, activeErr := ActiveTimer(tx, userID); activeErr == nil {
+			return nil, ErrTimerRunning
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// StopTimer - توقف تایمر فعال کاربر و ثبت مدت آن
+func StopTimer(tx *gorm.DB, userID uint) (*models.TimeEntry, error) {
+	entry, err := ActiveTimer(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	seconds := int64(now.Sub(entry.StartedAt) / time.Second)
+	result := tx.Model(&models.TimeEntry{}).
+		Where("id = ? AND active_user_id = ?", entry.ID, userID).
+		Updates(map[string]interface{}{"ended_at": now, "seconds": seconds, "active_user_id": nil})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNoActiveTimer
+	}
+
+	entry.EndedAt = &now
+	entry.Seconds = seconds
+	entry.ActiveUserID = nil
+	return entry, nil
+}
@@ -0,0 +1,32 @@
+// backend/services/timetracking_test.go
+
+package services
+
+import (
+	"task-manager/models"
+	"testing"
+	"time"
+)
+
+func TestTrackedSecondsExpr(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 1)
+	task := models.Task{Title: "task", CreatorID: users[0].ID}
+	mustCreate(t, db, &task)
+
+	ended := time.Now().Add(-time.Hour)
+	mustCreate(t, db, &models.TimeEntry{TaskID: task.ID, UserID: users[0].ID, StartedAt: ended.Add(-90 * time.Second), EndedAt: &ended, Seconds: 90})
+	if _, err := StartTimer(db, users[0].ID, task.ID, ""); err != nil {
+		t.Fatalf("StartTimer: %v", err)
+	}
+	// تایمر در حال اجرا از دو دقیقه پیش
+	db.Model(&models.TimeEntry{}).Where("ended_at IS NULL").Update("started_at", time.Now().Add(-2*time.Minute))
+
+	var total int64
+	if err := db.Table("time_entries").Select("SUM(" + TrackedSecondsExpr(db) + ")").Scan(&total).Error; err != nil {
+		t.Fatalf("sum tracked seconds: %v", err)
+	}
+	if total < 210 || total > 215 {
+		t.Errorf("tracked seconds = %d, want about 210", total)
+	}
+}
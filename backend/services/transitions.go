@@ -0,0 +1,20 @@
+// backend/services/transitions.go
+
+package services
+
+import (
+	"task-manager/models"
+
+	"gorm.io/gorm"
+)
+
+// RecordTransition - ثبت تغییر وضعیت تسک؛ changedBy برای تغییرات سیستمی nil است
+func RecordTransition(tx *gorm.DB, taskID uint, from, to models.TaskStatus, reason string, changedBy *uint) error {
+	return tx.Create(&models.TaskStatusTransition{
+		TaskID:     taskID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+	}).Error
+}
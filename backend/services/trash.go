@@ -0,0 +1,292 @@
+// backend/services/trash.go
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"task-manager/models"
+	"task-manager/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrParentTrashed بازگردانی آیتمی که گروه یا تسک آن هنوز در سطل زباله است
+var ErrParentTrashed = errors.New("parent is in the trash")
+
+// TrashRetention - مدت نگهداری آیتم‌ها در سطل زباله پیش از پاک‌سازی نهایی (TRASH_RETENTION، پیش‌فرض 30 روز)
+func TrashRetention() time.Duration {
+	return utils.GetEnvPositiveDuration("TRASH_RETENTION", 30*24*time.Hour)
+}
+
+// trashTaskRow - انتقال یک تسک به سطل زباله با زمان مشخص؛ زمان یکسان تشخیص می‌دهد چه چیزهایی با هم حذف شده‌اند
+func trashTaskRow(tx *gorm.DB, taskID uint, deletedAt time.Time) error {
+	if err := tx.Model(&models.Task{}).Where("id = ?", taskID).Update("deleted_at", deletedAt).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Reminder{}).
+		Where("task_id = ? AND status = ?", taskID, models.ReminderPending).
+		Update("status", models.ReminderCancelled).Error
+}
+
+// TrashTask - انتقال تسک (و در حالت delete زیرتسک‌هایش) به سطل زباله
+// اختصاص‌ها، پیشرفت، فایل‌ها، چک‌لیست، دیدگاه‌ها و وابستگی‌ها دست‌نخورده می‌مانند تا با بازگردانی برگردند؛
+// پیش‌نیاز حذف‌شده در EnsureUnblocked و OpenBlockers نادیده گرفته می‌شود و تسک دیگری را مسدود نمی‌کند
+func TrashTask(tx *gorm.DB, task *models.Task, children string) error {
+	deletedAt := time.Now()
+	if err := DetachSubtasks(tx, task, children, deletedAt); err != nil {
+		return err
+	}
+	if err := trashTaskRow(tx, task.ID, deletedAt); err != nil {
+		return err
+	}
+	task.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+	return nil
+}
+
+// RestoreTask - بازگردانی تسک و زیرتسک‌هایی که همراه آن حذف شده بودند
+// اگر والد تسک دیگر وجود نداشته باشد تسک به عنوان تسک مستقل برمی‌گردد
+func RestoreTask(tx *gorm.DB, task *models.Task, actorID uint) error {
+	if task.IsGroupTask && task.GroupID != nil {
+		var group models.Group
+		if err := tx.Select("id").First(&group, *task.GroupID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrParentTrashed
+			}
+			return err
+		}
+	}
+	if task.ParentID != nil {
+		var parent models.Task
+		if err := tx.Select("id").First(&parent, *task.ParentID).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			task.ParentID = nil
+			if err := tx.Unscoped().Model(task).Update("parent_id", nil).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := restoreTaskTree(tx, task.ID, task.DeletedAt.Time); err != nil {
+		return err
+	}
+	task.DeletedAt = gorm.DeletedAt{}
+
+	if err := SyncTaskReminders(tx, task); err != nil {
+		return err
+	}
+	if err := LogTaskActivity(tx, task, actorID, models.ActivityTaskRestored, map[string]interface{}{"title": task.Title}); err != nil {
+		return err
+	}
+	return SyncParentProgress(tx, task.ParentID)
+}
+
+func restoreTaskTree(tx *gorm.DB, taskID uint, deletedAt time.Time) error {
+	if err := tx.Unscoped().Model(&models.Task{}).Where("id = ?", taskID).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	var children []uint
+	if err := tx.Unscoped().Model(&models.Task{}).
+		Where("parent_id = ? AND deleted_at = ?", taskID, deletedAt).
+		Pluck("id", &children).Error; err != nil {
+		return err
+	}
+	for _, childID := range children {
+		if err := restoreTaskTree(tx, childID, deletedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurgeTask - حذف دائمی تسک، زیرتسک‌ها و تمام داده‌های وابسته از جمله اعلان‌ها
+// مسیر فایل‌ها و پوشه آپلود تسک برمی‌گردد تا پس از commit پاک شوند
+func PurgeTask(tx *gorm.DB, task *models.Task) ([]string, error) {
+	var paths []string
+
+	var children []models.Task
+	if err := tx.Unscoped().Where("parent_id = ?", task.ID).Find(&children).Error; err != nil {
+		return nil, err
+	}
+	for i := range children {
+		childPaths, err := PurgeTask(tx, &children[i])
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, childPaths...)
+	}
+
+	var files []models.File
+	if err := tx.Unscoped().Where("task_id = ?", task.ID).Find(&files).Error; err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		paths = append(paths, file.Filepath)
+	}
+	paths = append(paths, fmt.Sprintf("uploads/tasks/%d", task.ID))
+
+	if err := tx.Where("task_id = ? OR depends_on_id = ?", task.ID, task.ID).Delete(&models.TaskDependency{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Exec("DELETE FROM task_labels WHERE task_id = ?", task.ID).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Exec("DELETE FROM task_teams WHERE task_id = ?", task.ID).Error; err != nil {
+		return nil, err
+	}
+	if err := DeleteTaskComments(tx, task.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Where("related_id = ? AND type IN ?", task.ID, models.TaskNotificationTypes).Delete(&models.Notification{}).Error; err != nil {
+		return nil, err
+	}
+	for _, model := range []interface{}{
+		&models.TimeEntry{},
+		&models.ChecklistItem{},
+		&models.TaskAssignment{},
+		&models.GroupTaskProgress{},
+		&models.TaskProgress{},
+		&models.Reminder{},
+		&models.ReminderRule{},
+		&models.TaskStatusTransition{},
+		&models.File{},
+		&models.TaskSubmission{},
+	} {
+		if err := tx.Unscoped().Where("task_id = ?", task.ID).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Unscoped().Delete(task).Error; err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// TrashGroup - انتقال گروه و تسک‌های آن به سطل زباله؛ عضویت‌ها برای بازگردانی باقی می‌مانند
+func TrashGroup(tx *gorm.DB, group *models.Group) error {
+	deletedAt := time.Now()
+
+	var taskIDs []uint
+	if err := tx.Model(&models.Task{}).Where("group_id = ?", group.ID).Pluck("id", &taskIDs).Error; err != nil {
+		return err
+	}
+	for _, taskID := range taskIDs {
+		if err := trashTaskRow(tx, taskID, deletedAt); err != nil {
+			return err
+		}
+	}
+	if err := tx.Model(group).Update("deleted_at", deletedAt).Error; err != nil {
+		return err
+	}
+	group.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+	return nil
+}
+
+// RestoreGroup - بازگردانی گروه و تسک‌هایی که همراه آن حذف شده بودند؛ تسک‌هایی که جداگانه حذف شده‌اند در سطل زباله می‌مانند
+func RestoreGroup(tx *gorm.DB, group *models.Group, actorID uint) error {
+	deletedAt := group.DeletedAt.Time
+
+	var tasks []models.Task
+	if err := tx.Unscoped().Where("group_id = ? AND deleted_at = ?", group.ID, deletedAt).Find(&tasks).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(group).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	group.DeletedAt = gorm.DeletedAt{}
+
+	for i := range tasks {
+		if err := tx.Unscoped().Model(&tasks[i]).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		tasks[i].DeletedAt = gorm.DeletedAt{}
+		if err := SyncTaskReminders(tx, &tasks[i]); err != nil {
+			return err
+		}
+	}
+	return LogGroupActivity(tx, group.ID, actorID, models.ActivityGroupRestored, map[string]interface{}{"name": group.Name})
+}
+
+// PurgeGroup - حذف دائمی گروه، تمام تسک‌ها (حذف‌شده یا نه) و داده‌های وابسته به گروه
+func PurgeGroup(tx *gorm.DB, group *models.Group) ([]string, error) {
+	var paths []string
+
+	// زیرتسک‌هایی که همراه والدشان پاک شده‌اند در دور بعدی حلقه کاری برای حذف ندارند
+	var tasks []models.Task
+	if err := tx.Unscoped().Where("group_id = ?", group.ID).Order("id ASC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		taskPaths, err := PurgeTask(tx, &tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, taskPaths...)
+	}
+
+	groupLabels := tx.Model(&models.Label{}).Select("id").Where("group_id = ?", group.ID)
+	if err := tx.Exec("DELETE FROM task_labels WHERE label_id IN (?)", groupLabels).Error; err != nil {
+		return nil, err
+	}
+	groupTemplates := tx.Model(&models.TaskTemplate{}).Select("id").Where("group_id = ?", group.ID)
+	if err := tx.Where("template_id IN (?)", groupTemplates).Delete(&models.TaskTemplateItem{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("related_id = ? AND type IN ?", group.ID, models.GroupNotificationTypes).Delete(&models.Notification{}).Error; err != nil {
+		return nil, err
+	}
+	for _, model := range []interface{}{
+		&models.Label{},
+		&models.TaskTemplate{},
+		&models.BoardColumn{},
+		&models.GroupRole{},
+		&models.OwnershipTransfer{},
+		&models.GroupInviteUse{},
+		&models.GroupInvite{},
+		&models.GroupInvitation{},
+		&models.GroupJoinRequest{},
+		&models.GroupTeamMember{},
+		&models.GroupTeam{},
+		&models.GroupMember{},
+	} {
+		if err := tx.Where("group_id = ?", group.ID).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Unscoped().Delete(group).Error; err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// RestoreFile - بازگردانی فایل؛ تسک فایل باید خارج از سطل زباله باشد
+func RestoreFile(tx *gorm.DB, file *models.File) error {
+	var task models.Task
+	if err := tx.Select("id").First(&task, file.TaskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrParentTrashed
+		}
+		return err
+	}
+	if err := tx.Unscoped().Model(file).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	file.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+// RemoveStoredFiles - حذف فایل‌ها و پوشه‌های آپلود پس از commit پاک‌سازی؛ خطاها فقط ثبت می‌شوند
+func RemoveStoredFiles(paths []string) {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			utils.LogErrorWithDetails("Failed to remove stored file", err, path)
+		}
+	}
+}
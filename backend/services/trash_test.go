@@ -0,0 +1,210 @@
+// backend/services/trash_test.go
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"task-manager/models"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// taskExists - آیا تسک خارج از سطل زباله است
+func taskExists(t *testing.T, db *gorm.DB, id uint) bool {
+	t.Helper()
+	var count int64
+	db.Model(&models.Task{}).Where("id = ?", id).Count(&count)
+	return count > 0
+}
+
+func TestTrashAndRestoreTask(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 1)
+	owner := users[0].ID
+
+	parent := models.Task{Title: "parent", CreatorID: owner}
+	mustCreate(t, db, &parent)
+	child := models.Task{Title: "child", CreatorID: owner, ParentID: &parent.ID}
+	removed := models.Task{Title: "removed earlier", CreatorID: owner, ParentID: &parent.ID}
+	mustCreate(t, db, &child, &removed)
+	grandchild := models.Task{Title: "grandchild", CreatorID: owner, ParentID: &child.ID}
+	blocked := models.Task{Title: "blocked", CreatorID: owner}
+	mustCreate(t, db, &grandchild, &blocked)
+	mustCreate(t, db, &models.TaskDependency{TaskID: blocked.ID, DependsOnID: parent.ID, CreatedBy: owner})
+
+	// زیرتسکی که جداگانه و زودتر حذف شده با بازگردانی والد برنمی‌گردد
+	if err := TrashTask(db, &removed, SubtasksDelete); err != nil {
+		t.Fatalf("TrashTask(removed): %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if err := TrashTask(db, &parent, SubtasksDelete); err != nil {
+		t.Fatalf("TrashTask: %v", err)
+	}
+	for _, task := range []models.Task{parent, child, grandchild} {
+		if taskExists(t, db, task.ID) {
+			t.Errorf("task %q should be in the trash", task.Title)
+		}
+	}
+
+	var dependencies int64
+	db.Model(&models.TaskDependency{}).Where("task_id = ?", blocked.ID).Count(&dependencies)
+	if dependencies != 1 {
+		t.Fatalf("dependency rows after trash = %d, want 1", dependencies)
+	}
+	if err := EnsureUnblocked(db, blocked.ID); err != nil {
+		t.Errorf("trashed prerequisite should not block: %v", err)
+	}
+
+	var trashed models.Task
+	db.Unscoped().First(&trashed, parent.ID)
+	if err := RestoreTask(db, &trashed, owner); err != nil {
+		t.Fatalf("RestoreTask: %v", err)
+	}
+	for _, task := range []models.Task{parent, child, grandchild} {
+		if !taskExists(t, db, task.ID) {
+			t.Errorf("task %q should be restored", task.Title)
+		}
+	}
+	if taskExists(t, db, removed.ID) {
+		t.Error("separately trashed subtask should stay in the trash")
+	}
+	if err := EnsureUnblocked(db, blocked.ID); !errors.Is(err, ErrTaskBlocked) {
+		t.Errorf("restored prerequisite should block again, got %v", err)
+	}
+}
+
+func TestRestoreTaskWithoutParent(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 1)
+	owner := users[0].ID
+
+	parent := models.Task{Title: "parent", CreatorID: owner}
+	mustCreate(t, db, &parent)
+	child := models.Task{Title: "child", CreatorID: owner, ParentID: &parent.ID}
+	mustCreate(t, db, &child)
+
+	if err := TrashTask(db, &child, SubtasksDelete); err != nil {
+		t.Fatalf("TrashTask(child): %v", err)
+	}
+	if _, err := PurgeTask(db, &parent); err != nil {
+		t.Fatalf("PurgeTask(parent): %v", err)
+	}
+
+	var trashed models.Task
+	db.Unscoped().First(&trashed, child.ID)
+	if err := RestoreTask(db, &trashed, owner); err != nil {
+		t.Fatalf("RestoreTask: %v", err)
+	}
+	db.First(&trashed, child.ID)
+	if trashed.ParentID != nil {
+		t.Errorf("restored task parent = %d, want standalone task", *trashed.ParentID)
+	}
+}
+
+func TestPurgeTask(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 1)
+	owner := users[0].ID
+
+	task := models.Task{Title: "task", CreatorID: owner}
+	other := models.Task{Title: "other", CreatorID: owner}
+	mustCreate(t, db, &task, &other)
+	child := models.Task{Title: "child", CreatorID: owner, ParentID: &task.ID}
+	mustCreate(t, db, &child)
+	mustCreate(t, db,
+		&models.File{TaskID: child.ID, UserID: owner, Filename: "a.pdf", Filepath: "uploads/tasks/a.pdf"},
+		&models.ChecklistItem{TaskID: task.ID, UserID: owner, Title: "step"},
+		&models.TaskDependency{TaskID: other.ID, DependsOnID: task.ID, CreatedBy: owner},
+	)
+
+	if err := TrashTask(db, &task, SubtasksDelete); err != nil {
+		t.Fatalf("TrashTask: %v", err)
+	}
+	paths, err := PurgeTask(db, &task)
+	if err != nil {
+		t.Fatalf("PurgeTask: %v", err)
+	}
+
+	want := map[string]bool{
+		"uploads/tasks/a.pdf":                     true,
+		fmt.Sprintf("uploads/tasks/%d", task.ID):  true,
+		fmt.Sprintf("uploads/tasks/%d", child.ID): true,
+	}
+	for _, path := range paths {
+		delete(want, path)
+	}
+	if len(want) > 0 {
+		t.Errorf("PurgeTask paths = %v, missing %v", paths, want)
+	}
+
+	var tasks int64
+	db.Unscoped().Model(&models.Task{}).Where("id IN ?", []uint{task.ID, child.ID}).Count(&tasks)
+	if tasks != 0 {
+		t.Errorf("purged tasks left = %d, want 0", tasks)
+	}
+	for _, model := range []interface{}{&models.File{}, &models.ChecklistItem{}, &models.TaskDependency{}} {
+		var count int64
+		db.Unscoped().Model(model).Count(&count)
+		if count != 0 {
+			t.Errorf("%T rows after purge = %d, want 0", model, count)
+		}
+	}
+}
+
+func TestTrashAndRestoreGroup(t *testing.T) {
+	db := newTestDB(t)
+	users := createUsers(t, db, 1)
+	owner := users[0].ID
+
+	group := models.Group{Name: "team", CreatedBy: owner}
+	mustCreate(t, db, &group)
+	mustCreate(t, db, &models.GroupMember{GroupID: group.ID, UserID: owner, Role: models.RoleOwner, Accepted: true})
+	kept := models.Task{Title: "kept", CreatorID: owner, IsGroupTask: true, GroupID: &group.ID}
+	removed := models.Task{Title: "removed earlier", CreatorID: owner, IsGroupTask: true, GroupID: &group.ID}
+	mustCreate(t, db, &kept, &removed)
+
+	if err := TrashTask(db, &removed, SubtasksMove); err != nil {
+		t.Fatalf("TrashTask: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := TrashGroup(db, &group); err != nil {
+		t.Fatalf("TrashGroup: %v", err)
+	}
+	if taskExists(t, db, kept.ID) {
+		t.Error("group tasks should be trashed with the group")
+	}
+
+	// تسک گروه حذف‌شده تا بازگردانی گروه قابل بازگردانی نیست
+	var trashedTask models.Task
+	db.Unscoped().First(&trashedTask, kept.ID)
+	if err := RestoreTask(db, &trashedTask, owner); !errors.Is(err, ErrParentTrashed) {
+		t.Fatalf("RestoreTask in trashed group = %v, want ErrParentTrashed", err)
+	}
+
+	var trashedGroup models.Group
+	db.Unscoped().First(&trashedGroup, group.ID)
+	if err := RestoreGroup(db, &trashedGroup, owner); err != nil {
+		t.Fatalf("RestoreGroup: %v", err)
+	}
+	if !taskExists(t, db, kept.ID) {
+		t.Error("task trashed with the group should be restored")
+	}
+	if taskExists(t, db, removed.ID) {
+		t.Error("task trashed before the group should stay in the trash")
+	}
+
+	if _, err := PurgeGroup(db, &trashedGroup); err != nil {
+		t.Fatalf("PurgeGroup: %v", err)
+	}
+	var groups, members, tasks int64
+	db.Unscoped().Model(&models.Group{}).Count(&groups)
+	db.Model(&models.GroupMember{}).Count(&members)
+	db.Unscoped().Model(&models.Task{}).Count(&tasks)
+	if groups != 0 || members != 0 || tasks != 0 {
+		t.Errorf("after PurgeGroup: groups=%d members=%d tasks=%d, want none", groups, members, tasks)
+	}
+}
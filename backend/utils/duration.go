@@ -0,0 +1,29 @@
+// backend/utils/duration.go
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseFriendlyDuration - مانند time.ParseDuration با پشتیبانی از واحدهای d (روز) و w (هفته)
+// مثال: "1d"، "2h"، "1w"، "90m"
+func ParseFriendlyDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+			if err != nil || n < 0 {
+				return 0, &ValidationError{Field: "duration", Message: "invalid value " + s}
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, &ValidationError{Field: "duration", Message: "invalid value " + s}
+	}
+	return d, nil
+}
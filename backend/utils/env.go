@@ -0,0 +1,33 @@
+// backend/utils/env.go
+
+package utils
+
+import (
+	"os"
+	"time"
+)
+
+// GetEnvDuration - خواندن مدت زمان از متغیر محیطی (مثلا 30m، 24h یا 30d) با مقدار پیش‌فرض
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := ParseFriendlyDuration(value)
+	if err != nil {
+		LogWarn("Invalid duration in environment, using default", key, value)
+		return defaultValue
+	}
+	return d
+}
+
+// GetEnvPositiveDuration - مانند GetEnvDuration ولی مقدار صفر را هم نامعتبر می‌داند
+// برای فاصله اجرای job ها و دوره‌های نگهداری که صفر در آن‌ها معنا ندارد
+func GetEnvPositiveDuration(key string, defaultValue time.Duration) time.Duration {
+	d := GetEnvDuration(key, defaultValue)
+	if d <= 0 {
+		LogWarn("Non-positive duration in environment, using default", key, os.Getenv(key))
+		return defaultValue
+	}
+	return d
+}
@@ -0,0 +1,35 @@
+// backend/utils/mentions.go
+
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	fencedCodePattern = regexp.MustCompile("(?s)```.*?(```|$)")
+	inlineCodePattern = regexp.MustCompile("`[^`\n]*`")
+	mentionPattern    = regexp.MustCompile(`(^|[^\w@])@([A-Za-z0-9_][A-Za-z0-9_.-]*)`)
+)
+
+// ExtractMentions - نام‌های کاربری @username در متن Markdown، بدون تکرار
+// اشاره‌های داخل بلوک‌های کد و ایمیل‌ها نادیده گرفته می‌شوند
+func ExtractMentions(body string) []string {
+	body = fencedCodePattern.ReplaceAllString(body, " ")
+	body = inlineCodePattern.ReplaceAllString(body, " ")
+
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// نقطه پایان جمله جزو نام کاربری نیست
+		username := strings.TrimRight(match[2], ".-")
+		key := strings.ToLower(username)
+		if username == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
@@ -0,0 +1,35 @@
+// backend/utils/placeholders.go
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// RenderPlaceholders - جایگزینی {{name}} با مقدار متناظر در vars
+// جای‌نگهدارهای ناشناخته دست‌نخورده می‌مانند تا اشتباه تایپی در خروجی دیده شود
+func RenderPlaceholders(text string, vars map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := strings.ToLower(placeholderPattern.FindStringSubmatch(match)[1])
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// DateVars - جای‌نگهدارهای تاریخی: week (هفته ISO مانند 2026-W42)، date، year و month
+func DateVars(t time.Time) map[string]string {
+	year, week := t.ISOWeek()
+	return map[string]string{
+		"week":  fmt.Sprintf("%d-W%02d", year, week),
+		"date":  t.Format("2006-01-02"),
+		"year":  t.Format("2006"),
+		"month": t.Format("01"),
+	}
+}
@@ -0,0 +1,334 @@
+// backend/utils/query.go
+
+package utils
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ListSpec توصیف فیلدهای قابل فیلتر، جستجو و مرتب‌سازی یک لیست
+// مقادیر map ها عبارت SQL هستند؛ عبارت‌های مرتب‌سازی نباید NULL برگردانند چون در cursor مقایسه می‌شوند
+type ListSpec struct {
+	Table       string            // جدول اصلی؛ ستون id آن برای ترتیب پایدار و cursor استفاده می‌شود
+	Sortable    map[string]string // نام پارامتر sort -> عبارت SQL
+	DefaultSort string            // مثلا "-created_at"
+	Searchable  []string          // ستون‌هایی که q در آن‌ها جستجو می‌شود
+	Filters     map[string]string // پارامتر -> ستون، با مقادیر چندگانه جداشده با کاما
+	DateColumn  string            // ستونی که due_from/due_to روی آن اعمال می‌شود
+}
+
+type SortField struct {
+	Expr string
+	Desc bool
+}
+
+// ListQuery پارامترهای لیست دریافت‌شده از query string
+// صفحه‌بندی offset با page/size و صفحه‌بندی cursor با وجود پارامتر cursor فعال می‌شود
+type ListQuery struct {
+	Page       int
+	Size       int
+	UseCursor  bool
+	Cursor     *listCursor
+	NextCursor string
+	Total      int64
+
+	spec    ListSpec
+	sort    []SortField
+	filters map[string][]string
+	search  string
+	dueFrom *time.Time
+	dueTo   *time.Time
+}
+
+type listCursor struct {
+	Values []string `json:"v"`
+	ID     uint     `json:"id"`
+}
+
+// ParseListQuery - خواندن و اعتبارسنجی پارامترهای لیست
+func ParseListQuery(c *gin.Context, spec ListSpec) (*ListQuery, error) {
+	q := &ListQuery{
+		Page:    1,
+		Size:    DefaultPageSize,
+		spec:    spec,
+		filters: make(map[string][]string),
+		search:  strings.TrimSpace(c.Query("q")),
+	}
+
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return nil, &ValidationError{Field: "page", Message: "must be a positive integer"}
+		}
+		q.Page = page
+	}
+	if v := c.Query("size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 {
+			return nil, &ValidationError{Field: "size", Message: "must be a positive integer"}
+		}
+		if size > MaxPageSize {
+			size = MaxPageSize
+		}
+		q.Size = size
+	}
+
+	if v, ok := c.GetQuery("cursor"); ok {
+		q.UseCursor = true
+		if v != "" {
+			cursor, err := decodeCursor(v)
+			if err != nil {
+				return nil, &ValidationError{Field: "cursor", Message: "invalid cursor"}
+			}
+			q.Cursor = cursor
+		}
+	}
+
+	sortParam := c.DefaultQuery("sort", spec.DefaultSort)
+	for _, name := range strings.Split(sortParam, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		desc := strings.HasPrefix(name, "-")
+		expr, ok := spec.Sortable[strings.TrimPrefix(name, "-")]
+		if !ok {
+			return nil, &ValidationError{Field: "sort", Message: "unsupported sort field " + strings.TrimPrefix(name, "-")}
+		}
+		q.sort = append(q.sort, SortField{Expr: expr, Desc: desc})
+	}
+
+	for param := range spec.Filters {
+		if v := c.Query(param); v != "" {
+			for _, value := range strings.Split(v, ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					q.filters[param] = append(q.filters[param], value)
+				}
+			}
+		}
+	}
+
+	if spec.DateColumn != "" {
+		var err error
+		if q.dueFrom, err = parseQueryTime(c.Query("due_from"), false); err != nil {
+			return nil, &ValidationError{Field: "due_from", Message: "expected RFC3339 or YYYY-MM-DD"}
+		}
+		if q.dueTo, err = parseQueryTime(c.Query("due_to"), true); err != nil {
+			return nil, &ValidationError{Field: "due_to", Message: "expected RFC3339 or YYYY-MM-DD"}
+		}
+	}
+
+	if q.Cursor != nil && len(q.Cursor.Values) != len(q.sort) {
+		return nil, &ValidationError{Field: "cursor", Message: "cursor does not match sort"}
+	}
+	return q, nil
+}
+
+// Filter - اعمال فیلترها و جستجو بدون مرتب‌سازی و صفحه‌بندی
+func (q *ListQuery) Filter(db *gorm.DB) *gorm.DB {
+	for param, values := range q.filters {
+		db = db.Where(q.spec.Filters[param]+" IN ?", values)
+	}
+	if q.dueFrom != nil {
+		db = db.Where(q.spec.DateColumn+" >= ?", *q.dueFrom)
+	}
+	if q.dueTo != nil {
+		db = db.Where(q.spec.DateColumn+" <= ?", *q.dueTo)
+	}
+	if q.search != "" && len(q.spec.Searchable) > 0 {
+		pattern := "%" + q.search + "%"
+		conditions := make([]string, len(q.spec.Searchable))
+		args := make([]interface{}, len(q.spec.Searchable))
+		for i, column := range q.spec.Searchable {
+			conditions[i] = column + " LIKE ?"
+			args[i] = pattern
+		}
+		db = db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+	return db
+}
+
+// Find - اجرای کوئری با فیلتر، مرتب‌سازی و صفحه‌بندی؛ dest باید اشاره‌گر به slice از struct های دارای ID باشد
+// scopes (مثل Preload) بعد از شمارش رکوردها اعمال می‌شوند
+func (q *ListQuery) Find(db *gorm.DB, dest interface{}, scopes ...func(*gorm.DB) *gorm.DB) error {
+	db = q.Filter(db)
+
+	if err := db.Session(&gorm.Session{}).Count(&q.Total).Error; err != nil {
+		return err
+	}
+
+	db = db.Scopes(scopes...)
+	for _, field := range q.sort {
+		db = db.Order(field.Expr + direction(field.Desc))
+	}
+	db = db.Order(q.idColumn() + " ASC")
+
+	if q.UseCursor {
+		if q.Cursor != nil {
+			condition, args := q.keysetCondition()
+			db = db.Where(condition, args...)
+		}
+	} else {
+		db = db.Offset((q.Page - 1) * q.Size)
+	}
+
+	if err := db.Limit(q.Size).Find(dest).Error; err != nil {
+		return err
+	}
+
+	if q.UseCursor {
+		return q.buildNextCursor(db, dest)
+	}
+	return nil
+}
+
+// keysetCondition - (a > va) OR (a = va AND b > vb) OR ... OR (... AND id > vid)
+func (q *ListQuery) keysetCondition() (string, []interface{}) {
+	exprs := make([]string, 0, len(q.sort)+1)
+	descs := make([]bool, 0, len(q.sort)+1)
+	for _, field := range q.sort {
+		exprs = append(exprs, field.Expr)
+		descs = append(descs, field.Desc)
+	}
+	exprs = append(exprs, q.idColumn())
+	descs = append(descs, false)
+
+	values := make([]interface{}, 0, len(exprs))
+	for _, v := range q.Cursor.Values {
+		values = append(values, v)
+	}
+	values = append(values, q.Cursor.ID)
+
+	var branches []string
+	var args []interface{}
+	for i := range exprs {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, exprs[j]+" = ?")
+			args = append(args, values[j])
+		}
+		op := " > ?"
+		if descs[i] {
+			op = " < ?"
+		}
+		parts = append(parts, exprs[i]+op)
+		args = append(args, values[i])
+		branches = append(branches, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(branches, " OR ") + ")", args
+}
+
+// buildNextCursor - خواندن مقادیر مرتب‌سازی آخرین ردیف برای ساخت cursor صفحه بعد
+func (q *ListQuery) buildNextCursor(db *gorm.DB, dest interface{}) error {
+	items := reflect.Indirect(reflect.ValueOf(dest))
+	if items.Kind() != reflect.Slice || items.Len() < q.Size {
+		return nil
+	}
+	last := reflect.Indirect(items.Index(items.Len() - 1))
+	idField := last.FieldByName("ID")
+	if !idField.IsValid() {
+		return fmt.Errorf("list item has no ID field")
+	}
+	cursor := listCursor{ID: uint(idField.Uint())}
+
+	if len(q.sort) > 0 {
+		selects := make([]string, len(q.sort))
+		for i, field := range q.sort {
+			selects[i] = "CAST(" + field.Expr + " AS CHAR)"
+		}
+		values := make([]sql.NullString, len(q.sort))
+		targets := make([]interface{}, len(values))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		row := db.Session(&gorm.Session{NewDB: true}).
+			Table(q.spec.Table).
+			Select(strings.Join(selects, ", ")).
+			Where(q.idColumn()+" = ?", cursor.ID).
+			Row()
+		if err := row.Scan(targets...); err != nil {
+			return err
+		}
+		for _, v := range values {
+			cursor.Values = append(cursor.Values, v.String)
+		}
+	}
+
+	encoded, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	q.NextCursor = base64.RawURLEncoding.EncodeToString(encoded)
+	return nil
+}
+
+func (q *ListQuery) idColumn() string {
+	return q.spec.Table + ".id"
+}
+
+func decodeCursor(s string) (*listCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
+	}
+	return " ASC"
+}
+
+// parseQueryTime - تاریخ بدون ساعت برای endOfDay تا پایان همان روز در نظر گرفته می‌شود
+func parseQueryTime(v string, endOfDay bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// ListResponse - پاسخ صفحه‌بندی‌شده یکسان برای تمام لیست‌ها
+func ListResponse(c *gin.Context, data interface{}, q *ListQuery) {
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Success:    true,
+		Message:    "OK",
+		Data:       data,
+		Total:      q.Total,
+		Page:       q.Page,
+		Size:       q.Size,
+		NextCursor: q.NextCursor,
+		Code:       http.StatusOK,
+	})
+}
@@ -0,0 +1,94 @@
+// backend/utils/rank.go
+
+package utils
+
+import (
+	"strings"
+)
+
+// rankDigits ارقام رتبه‌های کسری؛ فقط رقم و حروف بزرگ تا ترتیب در collation های
+// غیرحساس به حروف MySQL هم با ترتیب بایت‌ها یکی باشد
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// RankBetween - رتبه‌ای که بین a و b قرار می‌گیرد (ترتیب کسری، مثل اعشار در مبنای 36)
+// a خالی یعنی ابتدای لیست و b خالی یعنی انتهای لیست؛ رتبه‌ها هیچ‌وقت به رقم صفر ختم نمی‌شوند
+// تا همیشه بتوان رتبه‌ای کوچک‌تر از آن‌ها ساخت
+func RankBetween(a, b string) (string, error) {
+	if b != "" && a >= b {
+		return "", &ValidationError{Field: "rank", Message: "lower bound must sort before upper bound"}
+	}
+	if strings.HasSuffix(a, "0") || strings.HasSuffix(b, "0") {
+		return "", &ValidationError{Field: "rank", Message: "rank must not end with 0"}
+	}
+	return rankMidpoint(a, b), nil
+}
+
+func rankMidpoint(a, b string) string {
+	if b != "" {
+		// پیشوند مشترک حفظ می‌شود؛ a کوتاه‌تر با صفر پر در نظر گرفته می‌شود
+		n := 0
+		for n < len(b) && rankDigitAt(a, n) == strings.IndexByte(rankDigits, b[n]) {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + rankMidpoint(rest, b[n:])
+		}
+	}
+
+	digitA := rankDigitAt(a, 0)
+	digitB := len(rankDigits)
+	if b != "" {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB+1)/2])
+	}
+
+	// ارقام مجاورند: اگر b بلندتر است رقم اول آن به تنهایی بین دو رتبه قرار می‌گیرد
+	if b != "" && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(rankDigits[digitA]) + rankMidpoint(rest, "")
+}
+
+func rankDigitAt(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	return strings.IndexByte(rankDigits, s[i])
+}
+
+// EvenRanks - n رتبه با فاصله یکسان برای بازسازی ترتیب وقتی رتبه‌ها بیش از حد بلند شده‌اند
+func EvenRanks(n int) []string {
+	width := 1
+	for capacity := len(rankDigits); capacity <= n; capacity *= len(rankDigits) {
+		width++
+	}
+	width++ // فضای خالی بین رتبه‌ها برای جابه‌جایی‌های بعدی
+
+	space := 1
+	for i := 0; i < width; i++ {
+		space *= len(rankDigits)
+	}
+	step := space / (n + 1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		value := (i + 1) * step
+		digits := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			digits[j] = rankDigits[value%len(rankDigits)]
+			value /= len(rankDigits)
+		}
+		ranks[i] = strings.TrimRight(string(digits), "0")
+	}
+	return ranks
+}
@@ -0,0 +1,97 @@
+// backend/utils/rank_test.go
+
+package utils
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"", ""},
+		{"", "1"},
+		{"Z", ""},
+		{"ZZZ", ""},
+		{"A", "B"},
+		{"A", "A1"},
+		{"A1", "A2"},
+		{"1", "12"},
+		{"AZ", "B"},
+	}
+	for _, tt := range tests {
+		got, err := RankBetween(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("RankBetween(%q, %q) error: %v", tt.a, tt.b, err)
+			continue
+		}
+		if got <= tt.a || (tt.b != "" && got >= tt.b) {
+			t.Errorf("RankBetween(%q, %q) = %q, not between bounds", tt.a, tt.b, got)
+		}
+		if strings.HasSuffix(got, "0") {
+			t.Errorf("RankBetween(%q, %q) = %q, ends with 0", tt.a, tt.b, got)
+		}
+	}
+
+	for _, tt := range []struct{ a, b string }{{"B", "A"}, {"A", "A"}, {"A0", ""}, {"", "10"}} {
+		if _, err := RankBetween(tt.a, tt.b); err == nil {
+			t.Errorf("RankBetween(%q, %q) expected error", tt.a, tt.b)
+		}
+	}
+}
+
+func TestRankBetweenRepeatedInserts(t *testing.T) {
+	// درج مکرر در یک نقطه باید همیشه رتبه‌ای بین دو همسایه پیدا کند
+	low, high := "A", "B"
+	for i := 0; i < 200; i++ {
+		mid, err := RankBetween(low, high)
+		if err != nil {
+			t.Fatalf("insert %d: RankBetween(%q, %q) error: %v", i, low, high, err)
+		}
+		if mid <= low || mid >= high {
+			t.Fatalf("insert %d: RankBetween(%q, %q) = %q", i, low, high, mid)
+		}
+		if i%2 == 0 {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+
+	// درج مکرر در ابتدای لیست
+	first := "1"
+	for i := 0; i < 100; i++ {
+		rank, err := RankBetween("", first)
+		if err != nil || rank >= first {
+			t.Fatalf("prepend %d: RankBetween(\"\", %q) = %q, %v", i, first, rank, err)
+		}
+		first = rank
+	}
+}
+
+func TestEvenRanks(t *testing.T) {
+	for _, n := range []int{1, 2, 35, 36, 500, 1296} {
+		ranks := EvenRanks(n)
+		if len(ranks) != n {
+			t.Fatalf("EvenRanks(%d) returned %d ranks", n, len(ranks))
+		}
+		if !sort.StringsAreSorted(ranks) {
+			t.Errorf("EvenRanks(%d) is not sorted", n)
+		}
+		for i, rank := range ranks {
+			if rank == "" || strings.HasSuffix(rank, "0") {
+				t.Fatalf("EvenRanks(%d)[%d] = %q", n, i, rank)
+			}
+			if i > 0 && rank == ranks[i-1] {
+				t.Fatalf("EvenRanks(%d) has duplicate %q", n, rank)
+			}
+			// بین هر دو رتبه همسایه باید جا برای درج باشد
+			if i > 0 {
+				if _, err := RankBetween(ranks[i-1], rank); err != nil {
+					t.Fatalf("RankBetween(%q, %q) error: %v", ranks[i-1], rank, err)
+				}
+			}
+		}
+	}
+}
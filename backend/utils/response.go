@@ -17,13 +17,14 @@ type Response struct {
 }
 
 type PaginatedResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data"`
-	Total   int64       `json:"total"`
-	Page    int         `json:"page"`
-	Size    int         `json:"size"`
-	Code    int         `json:"code"`
+	Success    bool        `json:"success"`
+	Message    string      `json:"message,omitempty"`
+	Data       interface{} `json:"data"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page"`
+	Size       int         `json:"size"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Code       int         `json:"code"`
 }
 
 func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
@@ -0,0 +1,272 @@
+// backend/utils/rrule.go
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// نام روزهای هفته در RRULE به ترتیب time.Weekday
+var rruleWeekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RRule زیرمجموعه‌ای از RRULE استاندارد RFC 5545
+// پشتیبانی شده: FREQ (DAILY/WEEKLY/MONTHLY)، INTERVAL، BYDAY، BYMONTHDAY، COUNT و UNTIL
+type RRule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+// ParseRRule - تبدیل رشته RRULE به ساختار RRule
+func ParseRRule(s string) (*RRule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, &ValidationError{Field: "rrule", Message: "rule is empty"}
+	}
+
+	rule := &RRule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, &ValidationError{Field: "rrule", Message: fmt.Sprintf("invalid part %q", part)}
+		}
+		key := strings.ToUpper(strings.TrimSpace(kv[0]))
+		value := strings.ToUpper(strings.TrimSpace(kv[1]))
+
+		switch key {
+		case "FREQ":
+			if value != FreqDaily && value != FreqWeekly && value != FreqMonthly {
+				return nil, &ValidationError{Field: "rrule", Message: "unsupported FREQ " + value}
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, &ValidationError{Field: "rrule", Message: "INTERVAL must be a positive integer"}
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := parseRRuleWeekday(code)
+				if !ok {
+					return nil, &ValidationError{Field: "rrule", Message: "invalid BYDAY value " + code}
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, &ValidationError{Field: "rrule", Message: "invalid BYMONTHDAY value " + v}
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, &ValidationError{Field: "rrule", Message: "COUNT must be a positive integer"}
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseRRuleTime(value)
+			if err != nil {
+				return nil, &ValidationError{Field: "rrule", Message: "invalid UNTIL value " + value}
+			}
+			rule.Until = &until
+		case "WKST":
+			if value != "MO" {
+				return nil, &ValidationError{Field: "rrule", Message: "only WKST=MO is supported"}
+			}
+		default:
+			return nil, &ValidationError{Field: "rrule", Message: "unsupported rule part " + key}
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Validate - بررسی سازگاری بخش‌های قانون
+func (r *RRule) Validate() error {
+	if r.Freq == "" {
+		return &ValidationError{Field: "rrule", Message: "FREQ is required"}
+	}
+	if r.Interval < 1 {
+		r.Interval = 1
+	}
+	if r.Count > 0 && r.Until != nil {
+		return &ValidationError{Field: "rrule", Message: "COUNT and UNTIL cannot be used together"}
+	}
+	if len(r.ByDay) > 0 && r.Freq != FreqWeekly {
+		return &ValidationError{Field: "rrule", Message: "BYDAY is only supported with FREQ=WEEKLY"}
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != FreqMonthly {
+		return &ValidationError{Field: "rrule", Message: "BYMONTHDAY is only supported with FREQ=MONTHLY"}
+	}
+
+	// مرتب‌سازی روزها بر اساس شروع هفته از دوشنبه
+	sort.Slice(r.ByDay, func(i, j int) bool {
+		return mondayOffset(r.ByDay[i]) < mondayOffset(r.ByDay[j])
+	})
+	return nil
+}
+
+// String - تبدیل قانون به رشته استاندارد RRULE
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			codes[i] = rruleWeekdays[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next - محاسبه رخداد بعد از prev
+// start زمان اولین رخداد سری (DTSTART) است و prev باید خودش یک رخداد سری باشد
+func (r *RRule) Next(start, prev time.Time) time.Time {
+	switch r.Freq {
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			return prev.AddDate(0, 0, 7*r.Interval)
+		}
+		offset := mondayOffset(prev.Weekday())
+		for _, day := range r.ByDay {
+			if d := mondayOffset(day); d > offset {
+				return prev.AddDate(0, 0, d-offset)
+			}
+		}
+		weekStart := prev.AddDate(0, 0, -offset)
+		return weekStart.AddDate(0, 0, 7*r.Interval+mondayOffset(r.ByDay[0]))
+
+	case FreqMonthly:
+		if len(r.ByMonthDay) == 0 {
+			// روز ماه از DTSTART گرفته می‌شود و در ماه‌های کوتاه‌تر به روز آخر محدود می‌شود
+			next := monthStart(prev, r.Interval)
+			day := start.Day()
+			if last := daysInMonth(next); day > last {
+				day = last
+			}
+			return next.AddDate(0, 0, day-1)
+		}
+		for _, day := range resolveMonthDays(r.ByMonthDay, monthStart(prev, 0)) {
+			if day > prev.Day() {
+				return monthStart(prev, 0).AddDate(0, 0, day-1)
+			}
+		}
+		// ماه‌هایی که هیچ روز معتبری ندارند (مثلا 31 در ماه 30 روزه) رد می‌شوند
+		for months := r.Interval; ; months += r.Interval {
+			next := monthStart(prev, months)
+			if days := resolveMonthDays(r.ByMonthDay, next); len(days) > 0 {
+				return next.AddDate(0, 0, days[0]-1)
+			}
+		}
+
+	default:
+		return prev.AddDate(0, 0, r.Interval)
+	}
+}
+
+// Permits - آیا رخداد شماره occurrence در زمان at هنوز در محدوده COUNT/UNTIL است
+func (r *RRule) Permits(occurrence int, at time.Time) bool {
+	if r.Count > 0 && occurrence > r.Count {
+		return false
+	}
+	if r.Until != nil && at.After(*r.Until) {
+		return false
+	}
+	return true
+}
+
+func parseRRuleWeekday(code string) (time.Weekday, bool) {
+	for i, name := range rruleWeekdays {
+		if name == strings.TrimSpace(code) {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// UNTIL بدون ساعت شامل کل آن روز است
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+// mondayOffset - فاصله روز از دوشنبه (WKST=MO)
+func mondayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// monthStart - اولین روز ماه، months ماه بعد از t با حفظ ساعت
+func monthStart(t time.Time, months int) time.Time {
+	return time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+// resolveMonthDays - تبدیل BYMONTHDAY (شامل مقادیر منفی) به روزهای مرتب ماه
+func resolveMonthDays(byMonthDay []int, month time.Time) []int {
+	last := daysInMonth(month)
+	seen := make(map[int]bool)
+	var days []int
+	for _, d := range byMonthDay {
+		if d < 0 {
+			d = last + d + 1
+		}
+		if d < 1 || d > last || seen[d] {
+			continue
+		}
+		seen[d] = true
+		days = append(days, d)
+	}
+	sort.Ints(days)
+	return days
+}
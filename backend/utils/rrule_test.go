@@ -0,0 +1,146 @@
+// backend/utils/rrule_test.go
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+func TestParseRRule(t *testing.T) {
+	valid := map[string]string{
+		"FREQ=DAILY":                            "FREQ=DAILY",
+		"RRULE:freq=weekly;byday=FR,MO":         "FREQ=WEEKLY;BYDAY=MO,FR",
+		"FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=-1": "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=-1",
+		"FREQ=DAILY;COUNT=3;WKST=MO":            "FREQ=DAILY;COUNT=3",
+		"FREQ=DAILY;UNTIL=20250110":             "FREQ=DAILY;UNTIL=20250110T235959Z",
+	}
+	for in, want := range valid {
+		rule, err := ParseRRule(in)
+		if err != nil {
+			t.Errorf("ParseRRule(%q) error: %v", in, err)
+			continue
+		}
+		if got := rule.String(); got != want {
+			t.Errorf("ParseRRule(%q).String() = %q, want %q", in, got, want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250110",
+		"FREQ=WEEKLY;WKST=SU",
+		"FREQ=DAILY;BYHOUR=9",
+	}
+	for _, in := range invalid {
+		if _, err := ParseRRule(in); err == nil {
+			t.Errorf("ParseRRule(%q) expected error", in)
+		}
+	}
+}
+
+func TestRRuleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "daily interval",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: date(2025, time.January, 30),
+			want:  []time.Time{date(2025, time.February, 2), date(2025, time.February, 5)},
+		},
+		{
+			name:  "weekly without days",
+			rule:  "FREQ=WEEKLY;INTERVAL=2",
+			start: date(2025, time.January, 1),
+			want:  []time.Time{date(2025, time.January, 15), date(2025, time.January, 29)},
+		},
+		{
+			// 2025-01-06 دوشنبه است
+			name:  "weekly by day with interval",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			start: date(2025, time.January, 6),
+			want: []time.Time{
+				date(2025, time.January, 10),
+				date(2025, time.January, 20),
+				date(2025, time.January, 24),
+				date(2025, time.February, 3),
+			},
+		},
+		{
+			name:  "monthly clamps to short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2025, time.January, 31),
+			want: []time.Time{
+				date(2025, time.February, 28),
+				date(2025, time.March, 31),
+				date(2025, time.April, 30),
+			},
+		},
+		{
+			name:  "monthly last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: date(2024, time.January, 31),
+			want:  []time.Time{date(2024, time.February, 29), date(2024, time.March, 31)},
+		},
+		{
+			name:  "monthly skips months without the day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=15,31",
+			start: date(2025, time.March, 31),
+			want: []time.Time{
+				date(2025, time.April, 15),
+				date(2025, time.May, 15),
+				date(2025, time.May, 31),
+				date(2025, time.June, 15),
+				date(2025, time.July, 15),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRRule: %v", err)
+			}
+			at := tt.start
+			for i, want := range tt.want {
+				at = rule.Next(tt.start, at)
+				if !at.Equal(want) {
+					t.Fatalf("occurrence %d = %s, want %s", i+2, at.Format(time.DateOnly), want.Format(time.DateOnly))
+				}
+			}
+		})
+	}
+}
+
+func TestRRulePermits(t *testing.T) {
+	count, _ := ParseRRule("FREQ=DAILY;COUNT=3")
+	if !count.Permits(3, date(2030, time.January, 1)) {
+		t.Error("COUNT=3 should permit the third occurrence")
+	}
+	if count.Permits(4, date(2025, time.January, 1)) {
+		t.Error("COUNT=3 should not permit the fourth occurrence")
+	}
+
+	until, _ := ParseRRule("FREQ=DAILY;UNTIL=20250110")
+	if !until.Permits(100, time.Date(2025, time.January, 10, 23, 0, 0, 0, time.UTC)) {
+		t.Error("UNTIL without a time should include the whole day")
+	}
+	if until.Permits(1, date(2025, time.January, 11)) {
+		t.Error("UNTIL should not permit occurrences after it")
+	}
+}
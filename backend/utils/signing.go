@@ -0,0 +1,58 @@
+// backend/utils/signing.go
+
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+)
+
+// signingSecret - کلید امضای کدهای قابل اشتراک (INVITE_SECRET، در نبود آن JWT_SECRET)
+func signingSecret() ([]byte, error) {
+	secret := os.Getenv("INVITE_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if secret == "" {
+		return nil, errors.New("INVITE_SECRET or JWT_SECRET is not set")
+	}
+	return []byte(secret), nil
+}
+
+func signature(secret []byte, purpose, nonce string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose + ":" + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:12])
+}
+
+// NewSignedCode - کد تصادفی امضاشده به شکل nonce.signature برای یک کاربرد مشخص (مثلا "group-invite")
+func NewSignedCode(purpose string) (string, error) {
+	secret, err := signingSecret()
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(buf)
+	return nonce + "." + signature(secret, purpose, nonce), nil
+}
+
+// VerifySignedCode - آیا کد با کلید فعلی و برای همین کاربرد امضا شده است
+func VerifySignedCode(purpose, code string) bool {
+	secret, err := signingSecret()
+	if err != nil {
+		return false
+	}
+	nonce, sig, ok := strings.Cut(code, ".")
+	if !ok || nonce == "" {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(signature(secret, purpose, nonce)))
+}